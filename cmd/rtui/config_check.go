@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"rtui/internal/config"
+	"rtui/internal/ui"
+)
+
+// runConfigCheck prints every config problem with its line, column and
+// suggested fix, and returns the exit code: 1 if any problem is an error.
+func runConfigCheck(w io.Writer) int {
+	path := config.ConfigPath()
+	if _, err := os.Stat(path); os.IsNotExist(err) && config.WorkspacePath() == "" {
+		fmt.Fprintf(w, "%s: no config file, using defaults\n", path)
+		return 0
+	}
+	cfg, _ := config.Load()
+	path = cfg.File()
+	problems := ui.Check(cfg)
+	if len(problems) == 0 {
+		fmt.Fprintf(w, "%s: OK\n", path)
+		return 0
+	}
+	if printProblems(w, path, problems) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// repoSetKeys are the settings that decide which repos are scanned.
+var repoSetKeys = []string{"paths", "path_depths", "scan_depth", "groups", "manifest", "include"}
+
+// loadConfig loads the config for a command that works on the scanned
+// repos. A config that does not parse, or has errors in the settings that
+// pick the repos, would quietly fall back to the wrong repo set, so those
+// problems are printed instead and ok is false; the caller exits 2.
+// Errors elsewhere (editor, sort_mode, ...) are left to rtui config check.
+func loadConfig(w io.Writer) (cfg config.Config, ok bool) {
+	cfg, err := config.Load()
+	problems := cfg.Problems
+	if err != nil && len(problems) == 0 {
+		problems = []config.Problem{{Severity: config.SeverityError, Message: err.Error()}}
+	}
+	if err == nil {
+		var repoSet []config.Problem
+		for _, p := range problems {
+			if slices.Contains(repoSetKeys, p.Key) {
+				repoSet = append(repoSet, p)
+			}
+		}
+		problems = repoSet
+	}
+	if !config.HasErrors(problems) {
+		return cfg, true
+	}
+	printProblems(w, cfg.File(), problems)
+	return cfg, false
+}
+
+// printProblems prints problems with their positions and fixes, then a
+// summary line, and returns the number of errors.
+func printProblems(w io.Writer, path string, problems []config.Problem) int {
+	errors := 0
+	for _, p := range problems {
+		if p.Severity == config.SeverityError {
+			errors++
+		}
+		where := path
+		if p.File != "" {
+			where = p.File
+		}
+		if p.Line > 0 {
+			where = fmt.Sprintf("%s:%d:%d", where, p.Line, p.Col)
+		}
+		fmt.Fprintf(w, "%s: %s: %s\n", where, p.Severity, p.Message)
+		if p.Fix != "" {
+			fmt.Fprintf(w, "    fix: %s\n", p.Fix)
+		}
+	}
+	warnings := len(problems) - errors
+	fmt.Fprintf(w, "%d %s, %d %s\n",
+		errors, plural(errors, "error", "errors"), warnings, plural(warnings, "warning", "warnings"))
+	return errors
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
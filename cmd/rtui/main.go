@@ -1,8 +1,10 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
+	"slices"
 
 	tea "github.com/charmbracelet/bubbletea"
 
@@ -11,15 +13,48 @@ import (
 )
 
 func main() {
-	cfg, err := config.Load()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Config error: %v\n", err)
-		os.Exit(1)
+	configFile := flag.String("config", "", "config file (default $RTUI_CONFIG, then $XDG_CONFIG_HOME/rtui/config.toml or ~/.config/rtui/config.toml)")
+	workspace := flag.String("workspace", "", "named workspace from the workspaces directory next to the config (default: nearest .rtui.toml)")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: rtui [--config file] [--workspace name] [config check | workspaces | sync-manifest [--jobs n] [--dry-run] [manifest] | snapshot save|restore|diff|list]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *configFile != "" {
+		config.SetPath(*configFile)
+	}
+	if *workspace != "" {
+		if !slices.Contains(config.Workspaces(), *workspace) {
+			fmt.Fprintf(os.Stderr, "rtui: unknown workspace %q\n", *workspace)
+			runWorkspaces(os.Stderr)
+			os.Exit(2)
+		}
+		config.SetWorkspace(*workspace)
 	}
 
+	if args := flag.Args(); len(args) > 0 {
+		switch {
+		case len(args) == 2 && args[0] == "config" && args[1] == "check":
+			os.Exit(runConfigCheck(os.Stdout))
+		case len(args) == 1 && args[0] == "workspaces":
+			os.Exit(runWorkspaces(os.Stdout))
+		case args[0] == "sync-manifest":
+			os.Exit(runSyncManifest(os.Stdout, args[1:]))
+		case args[0] == "snapshot":
+			os.Exit(runSnapshot(os.Stdout, args[1:]))
+		}
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// Config problems never stop the TUI: a file that cannot be parsed
+	// yields defaults, and every problem is listed in the banner.
+	cfg, _ := config.Load()
+
 	p := tea.NewProgram(
 		ui.NewModel(cfg),
 		tea.WithAltScreen(),
+		tea.WithMouseCellMotion(),
 	)
 
 	if _, err := p.Run(); err != nil {
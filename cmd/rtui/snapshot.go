@@ -0,0 +1,162 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"rtui/internal/config"
+	"rtui/internal/git"
+	"rtui/internal/snapshot"
+)
+
+const snapshotUsage = `usage: rtui snapshot save [--force] <name>
+       rtui snapshot restore [--no-stash] [--exact] <name>
+       rtui snapshot diff <name> [<name>]
+       rtui snapshot list
+`
+
+// runSnapshot saves, restores, compares and lists branch snapshots of the
+// scanned repos. It returns 1 when a restore leaves a repo behind and 2
+// on usage or snapshot errors.
+func runSnapshot(w io.Writer, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(w, snapshotUsage)
+		return 2
+	}
+	dir := config.SnapshotsDir()
+	fs := flag.NewFlagSet("snapshot "+args[0], flag.ContinueOnError)
+	fs.SetOutput(w)
+	fs.Usage = func() {
+		fmt.Fprint(w, snapshotUsage)
+		fs.PrintDefaults()
+	}
+	force := fs.Bool("force", false, "save: replace an existing snapshot")
+	noStash := fs.Bool("no-stash", false, "restore: skip repos with local changes instead of stashing them")
+	exact := fs.Bool("exact", false, "restore: check out the recorded commits (detached HEAD)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	names := fs.Args()
+
+	switch {
+	case args[0] == "list" && len(names) == 0:
+		return snapshotList(w, dir)
+	case args[0] == "save" && len(names) == 1:
+		repos, ok := scanRepos(w)
+		if !ok {
+			return 2
+		}
+		s := snapshot.Take(names[0], repos)
+		if err := snapshot.Save(dir, s, *force); err != nil {
+			fmt.Fprintf(w, "rtui: %v\n", err)
+			return 2
+		}
+		fmt.Fprintf(w, "saved %s: %d repos\n", s.Name, len(s.Repos))
+		return 0
+	case args[0] == "restore" && len(names) == 1:
+		s, err := snapshot.Load(dir, names[0])
+		if err != nil {
+			fmt.Fprintf(w, "rtui: %v\n", err)
+			return 2
+		}
+		return snapshotRestore(w, s, snapshot.Options{NoStash: *noStash, Exact: *exact})
+	case args[0] == "diff" && (len(names) == 1 || len(names) == 2):
+		a, err := snapshot.Load(dir, names[0])
+		if err != nil {
+			fmt.Fprintf(w, "rtui: %v\n", err)
+			return 2
+		}
+		var b snapshot.Snapshot
+		if len(names) == 2 {
+			if b, err = snapshot.Load(dir, names[1]); err != nil {
+				fmt.Fprintf(w, "rtui: %v\n", err)
+				return 2
+			}
+		} else {
+			repos, ok := scanRepos(w)
+			if !ok {
+				return 2
+			}
+			b = snapshot.Take("current", repos)
+		}
+		return snapshotDiff(w, a, b)
+	}
+	fs.Usage()
+	return 2
+}
+
+// scanRepos scans the repos the TUI would list. ok is false, with the
+// problems printed, when the config does not load (see loadConfig).
+func scanRepos(w io.Writer) (repos []git.Repo, ok bool) {
+	cfg, ok := loadConfig(w)
+	if !ok {
+		return nil, false
+	}
+	roots, _ := cfg.ScanRoots()
+	return git.ScanReposDepths(roots, cfg.DepthFor, cfg.DefaultBranchFor), true
+}
+
+func snapshotList(w io.Writer, dir string) int {
+	names := snapshot.List(dir)
+	if len(names) == 0 {
+		fmt.Fprintf(w, "no snapshots in %s\n", dir)
+		return 0
+	}
+	fmt.Fprintf(w, "snapshots in %s:\n", dir)
+	for _, name := range names {
+		s, err := snapshot.Load(dir, name)
+		if err != nil {
+			fmt.Fprintf(w, "  %s (%v)\n", name, err)
+			continue
+		}
+		fmt.Fprintf(w, "  %-20s %s  %d repos\n", name, s.Created.Local().Format("2006-01-02 15:04"), len(s.Repos))
+	}
+	return 0
+}
+
+func snapshotRestore(w io.Writer, s snapshot.Snapshot, opts snapshot.Options) int {
+	counts := map[snapshot.Outcome]int{}
+	snapshot.Restore(s, opts, func(r snapshot.Result) {
+		counts[r.Outcome]++
+		line := fmt.Sprintf("%-9s %s  %s", r.Outcome, r.Repo.Path, r.Repo.Ref())
+		if r.Detail != "" {
+			line += "  (" + r.Detail + ")"
+		}
+		fmt.Fprintln(w, line)
+	})
+	fmt.Fprintf(w, "restored %s: %d switched, %d stashed, %d unchanged, %d skipped, %d failed\n", s.Name,
+		counts[snapshot.Switched], counts[snapshot.Stashed], counts[snapshot.Unchanged], counts[snapshot.Skipped], counts[snapshot.Failed])
+	if counts[snapshot.Skipped]+counts[snapshot.Failed] > 0 {
+		return 1
+	}
+	return 0
+}
+
+func snapshotDiff(w io.Writer, a, b snapshot.Snapshot) int {
+	changes := snapshot.Diff(a, b)
+	fmt.Fprintf(w, "%s -> %s\n", a.Name, b.Name)
+	differ := 0
+	for _, c := range changes {
+		if c.Kind == snapshot.Same {
+			continue
+		}
+		differ++
+		from, to := "-", "-"
+		if c.Kind != snapshot.Added {
+			from = c.From.Ref()
+		}
+		if c.Kind != snapshot.Removed {
+			to = c.To.Ref()
+		}
+		line := fmt.Sprintf("%-8s %s  %s -> %s", c.Kind, c.Path, from, to)
+		if c.Kind == snapshot.BranchChanged || c.Kind == snapshot.Moved {
+			if behind, ahead, err := git.CommitsBetween(c.Path, c.From.SHA, c.To.SHA); err == nil {
+				line += fmt.Sprintf("  (+%d -%d)", ahead, behind)
+			}
+		}
+		fmt.Fprintln(w, line)
+	}
+	fmt.Fprintf(w, "%d of %d repos differ\n", differ, len(changes))
+	return 0
+}
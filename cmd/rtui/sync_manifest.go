@@ -0,0 +1,96 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"sync"
+
+	"rtui/internal/config"
+	"rtui/internal/manifest"
+)
+
+// runSyncManifest compares a manifest with the repos on disk, clones the
+// missing ones and reports extra and mismatched repos. The manifest is
+// the argument, else the config's manifest key. It returns 1 if a clone
+// failed and 2 on usage errors.
+func runSyncManifest(w io.Writer, args []string) int {
+	fs := flag.NewFlagSet("sync-manifest", flag.ContinueOnError)
+	fs.SetOutput(w)
+	jobs := fs.Int("jobs", 4, "clones to run at once")
+	dryRun := fs.Bool("dry-run", false, "report only, clone nothing")
+	fs.Usage = func() {
+		fmt.Fprintf(w, "usage: rtui sync-manifest [--jobs n] [--dry-run] [manifest]\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil || fs.NArg() > 1 {
+		if err == nil {
+			fs.Usage()
+		}
+		return 2
+	}
+
+	cfg, ok := loadConfig(w)
+	if !ok {
+		return 2
+	}
+	path := cfg.Manifest
+	if fs.NArg() == 1 {
+		path = config.NormalizePath(fs.Arg(0))
+	}
+	if path == "" {
+		fmt.Fprintf(w, "rtui: no manifest: pass a file or set manifest in %s\n", cfg.File())
+		return 2
+	}
+	m, err := manifest.Load(path)
+	if err != nil {
+		fmt.Fprintf(w, "rtui: %v\n", err)
+		return 2
+	}
+
+	statuses := manifest.Compare(m, m.Scan(cfg.ScanPaths(), cfg.DepthFor, cfg.DefaultBranchFor))
+	counts := map[manifest.State]int{}
+	for _, st := range statuses {
+		counts[st.State]++
+		switch st.State {
+		case manifest.StateMismatch, manifest.StateExtra:
+			fmt.Fprintf(w, "%-8s %s: %s\n", st.State, st.Entry.Path, st.Detail)
+		}
+	}
+
+	missing := manifest.Missing(statuses)
+	failed := 0
+	if *dryRun {
+		for _, e := range missing {
+			fmt.Fprintf(w, "%-8s %s (%s)\n", "missing", e.Path, e.URL)
+		}
+	} else if len(missing) > 0 {
+		var mu sync.Mutex
+		results := manifest.CloneAll(missing, *jobs, func(r manifest.CloneResult) {
+			mu.Lock()
+			defer mu.Unlock()
+			if r.Err != nil {
+				fmt.Fprintf(w, "%-8s %s: %v\n", "failed", r.Entry.Path, r.Err)
+			} else {
+				fmt.Fprintf(w, "%-8s %s\n", "cloned", r.Entry.Path)
+			}
+		})
+		for _, r := range results {
+			if r.Err != nil {
+				failed++
+			}
+		}
+	}
+
+	summary := fmt.Sprintf("%d ok, %d missing, %d mismatch, %d extra",
+		counts[manifest.StateOK], counts[manifest.StateMissing], counts[manifest.StateMismatch], counts[manifest.StateExtra])
+	if !*dryRun && len(missing) > 0 {
+		summary = fmt.Sprintf("%d ok, %d cloned, %d failed, %d mismatch, %d extra",
+			counts[manifest.StateOK], len(missing)-failed, failed, counts[manifest.StateMismatch], counts[manifest.StateExtra])
+	}
+	fmt.Fprintf(w, "%s: %s\n", path, summary)
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"rtui/internal/config"
+)
+
+// runWorkspaces lists the named workspaces usable with --workspace, and
+// the .rtui.toml that applies in the current directory, if any.
+func runWorkspaces(w io.Writer) int {
+	names := config.Workspaces()
+	if len(names) == 0 {
+		fmt.Fprintf(w, "no named workspaces in %s\n", config.WorkspacesDir())
+	} else {
+		fmt.Fprintf(w, "workspaces in %s:\n", config.WorkspacesDir())
+		for _, name := range names {
+			fmt.Fprintf(w, "  %s\n", name)
+		}
+	}
+	if ws := config.WorkspacePath(); ws != "" {
+		fmt.Fprintf(w, "active here: %s\n", ws)
+	}
+	return 0
+}
@@ -0,0 +1,397 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Severity ranks a config problem. Errors mark settings that cannot work
+// as written; warnings point at settings that work but probably not as
+// intended.
+type Severity int
+
+const (
+	SeverityWarning Severity = iota
+	SeverityError
+)
+
+func (s Severity) String() string {
+	if s == SeverityError {
+		return "error"
+	}
+	return "warning"
+}
+
+// Problem is one config issue with its position and a suggested fix.
+// File is the included file it is in, empty for the main config file.
+// Line and Col are 1-based; 0 means unknown. Key is the top-level setting
+// it is about (e.g. "paths", "groups"), empty for a file that does not
+// decode.
+type Problem struct {
+	Severity Severity
+	Key      string
+	File     string
+	Line     int
+	Col      int
+	Message  string
+	Fix      string
+}
+
+func (p Problem) String() string {
+	var where []string
+	if p.File != "" {
+		where = append(where, filepath.Base(p.File))
+	}
+	if p.Line > 0 {
+		where = append(where, strconv.Itoa(p.Line), strconv.Itoa(p.Col))
+	}
+	s := p.Severity.String() + ": " + p.Message
+	if len(where) > 0 {
+		s = strings.Join(where, ":") + ": " + s
+	}
+	if p.Fix != "" {
+		s += " (fix: " + p.Fix + ")"
+	}
+	return s
+}
+
+// HasErrors reports whether any problem is an error.
+func HasErrors(problems []Problem) bool {
+	for _, p := range problems {
+		if p.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// NewProblem builds a problem positioned at table.key in the file that
+// sets it: the main file, else the last include that does. An empty key
+// points at the table header.
+func (c Config) NewProblem(severity Severity, table, key, message, fix string) Problem {
+	top := key
+	if table != "" {
+		top, _, _ = strings.Cut(table, ".")
+	}
+	p := Problem{Severity: severity, Key: top, Message: message, Fix: fix}
+	for _, src := range c.sources() {
+		if line, col := src.locate(table, key); line > 0 {
+			p.File, p.Line, p.Col = src.file, line, col
+			break
+		}
+	}
+	return p
+}
+
+// valueProblem is NewProblem positioned at a quoted string value of the
+// top-level setting key.
+func (c Config) valueProblem(severity Severity, key, value, message, fix string) Problem {
+	p := Problem{Severity: severity, Key: key, Message: message, Fix: fix}
+	for _, src := range c.sources() {
+		if line, col := src.locateValue(value); line > 0 {
+			p.File, p.Line, p.Col = src.file, line, col
+			break
+		}
+	}
+	return p
+}
+
+// missingKeyProblem is NewProblem positioned at the n-th [[table]] that
+// does not set key, counting through the files in sources order.
+func (c Config) missingKeyProblem(severity Severity, table, key string, n int, message, fix string) Problem {
+	p := Problem{Severity: severity, Key: table, Message: message, Fix: fix}
+	for _, src := range c.sources() {
+		lines := src.tablesWithout(table, key)
+		if n < len(lines) {
+			p.File, p.Line, p.Col = src.file, lines[n]+1, 1
+			return p
+		}
+		n -= len(lines)
+	}
+	return p
+}
+
+// sources lists the files behind c in lookup order: the main file, then
+// includes from the last loaded (which won) back.
+func (c Config) sources() []source {
+	out := []source{{text: c.source}}
+	for i := len(c.includes) - 1; i >= 0; i-- {
+		out = append(out, c.includes[i])
+	}
+	return out
+}
+
+func (s source) locate(table, key string) (int, int) {
+	if s.text == "" {
+		return 0, 0
+	}
+	doc := parseDocument(s.text)
+	headers, keys := doc.scan()
+	if key != "" {
+		for _, span := range keys {
+			if span.matches(table, key) {
+				return span.start + 1, indexOf(doc.lines[span.start], strings.TrimSpace(doc.lines[span.start])) + 1
+			}
+		}
+	}
+	for _, h := range headers {
+		if h.name == table || (key != "" && h.name == table+"."+key) {
+			return h.line + 1, indexOf(doc.lines[h.line], "[") + 1
+		}
+	}
+	// An inline table (theme = { ... }) has no header: point at its key.
+	if table != "" {
+		parent, name := "", table
+		if i := strings.LastIndex(table, "."); i >= 0 {
+			parent, name = table[:i], table[i+1:]
+		}
+		return s.locate(parent, name)
+	}
+	return 0, 0
+}
+
+// tablesWithout returns the 0-based header lines of the [[table]] entries
+// that do not set key.
+func (s source) tablesWithout(table, key string) []int {
+	headers, keys := parseDocument(s.text).scan()
+	var out []int
+	for i, h := range headers {
+		if !h.array || h.name != table {
+			continue
+		}
+		end := math.MaxInt
+		if i+1 < len(headers) {
+			end = headers[i+1].line
+		}
+		if !slices.ContainsFunc(keys, func(k keySpan) bool {
+			return k.key == key && k.start > h.line && k.start < end
+		}) {
+			out = append(out, h.line)
+		}
+	}
+	return out
+}
+
+// locateValue finds a quoted string value anywhere in the file.
+func (s source) locateValue(value string) (int, int) {
+	if s.text == "" {
+		return 0, 0
+	}
+	for i, line := range strings.Split(s.text, "\n") {
+		for _, quoted := range []string{strconv.Quote(value), "'" + value + "'"} {
+			if col := strings.Index(line, quoted); col >= 0 {
+				return i + 1, col + 1
+			}
+		}
+	}
+	return 0, 0
+}
+
+func indexOf(s, sub string) int {
+	return max(strings.Index(s, sub), 0)
+}
+
+// syntaxFix is the default fix for a file that does not decode.
+const syntaxFix = "fix the TOML syntax; rtui runs with defaults until then"
+
+// parseProblem turns a decode error into a problem at its position.
+func parseProblem(err error) Problem {
+	var perr toml.ParseError
+	if errors.As(err, &perr) {
+		fix := syntaxFix
+		if perr.Usage != "" {
+			fix = strings.TrimSpace(strings.SplitN(perr.Usage, "\n", 2)[0])
+		}
+		msg := perr.Message
+		if perr.LastKey != "" {
+			msg = perr.LastKey + ": " + msg
+		}
+		return Problem{Severity: SeverityError, Line: perr.Position.Line, Col: perr.Position.Col, Message: msg, Fix: fix}
+	}
+	return Problem{Severity: SeverityError, Message: err.Error(), Fix: "fix the file; rtui runs with defaults until then"}
+}
+
+// check validates decoded values. rawPaths maps normalized paths to how
+// they were written, so they can be found in the file.
+func check(cfg Config, rawPaths map[string]string) []Problem {
+	var problems []Problem
+	add := func(p Problem) { problems = append(problems, p) }
+	defaults := DefaultConfig()
+
+	if cfg.ScanDepth < 0 {
+		add(cfg.NewProblem(SeverityError, "", "scan_depth", fmt.Sprintf("scan_depth must be >= 0, got %d", cfg.ScanDepth), fmt.Sprintf("scan_depth = %d", defaults.ScanDepth)))
+	}
+	if cfg.RefreshInterval < 0 {
+		add(cfg.NewProblem(SeverityError, "", "refresh_interval", fmt.Sprintf("refresh_interval must be >= 0, got %d", cfg.RefreshInterval), "refresh_interval = 0"))
+	}
+	for _, p := range cfg.Paths {
+		raw := rawPaths[p]
+		if raw == "" {
+			raw = p
+		}
+		if issue := pathIssue(p); issue != "" {
+			add(cfg.valueProblem(SeverityWarning, "paths", raw, fmt.Sprintf("path %s: %s", raw, issue), "create the directory or remove it from paths"))
+		}
+	}
+	for _, p := range sortedKeys(cfg.PathDepths) {
+		d := cfg.PathDepths[p]
+		if d < 0 {
+			add(cfg.NewProblem(SeverityError, "path_depths", p, fmt.Sprintf("depth for %s must be >= 0, got %d", p, d), "remove the entry to use scan_depth"))
+		}
+		if pathIndex(cfg.Paths, p) < 0 {
+			add(cfg.NewProblem(SeverityWarning, "path_depths", p, fmt.Sprintf("depth set for %s, which is not in paths", p), "add the path to paths or remove the entry"))
+		}
+	}
+	if !validPullStrategy(cfg.PullStrategy) {
+		add(cfg.NewProblem(SeverityError, "", "pull_strategy", fmt.Sprintf("unknown pull_strategy %q", cfg.PullStrategy), `use "ff-only", "rebase", "merge" or "" (git default)`))
+	}
+	if !validSortMode(cfg.SortMode) {
+		add(cfg.NewProblem(SeverityError, "", "sort_mode", fmt.Sprintf("unknown sort_mode %q", cfg.SortMode), "use one of "+strings.Join(SortModes, ", ")))
+	}
+	if cfg.Editor == "" {
+		add(cfg.NewProblem(SeverityError, "", "editor", "editor is empty", `set editor = "vim" or another command`))
+	} else if _, err := exec.LookPath(cfg.Editor); err != nil {
+		add(cfg.NewProblem(SeverityWarning, "", "editor", fmt.Sprintf("editor %q not found on $PATH", cfg.Editor), "install it or set editor to a command on $PATH"))
+	}
+	if cfg.ShellCommand != "" {
+		if _, err := exec.LookPath(cfg.ShellCommand); err != nil {
+			add(cfg.NewProblem(SeverityWarning, "", "shell_command", fmt.Sprintf("shell_command %q not found on $PATH", cfg.ShellCommand), `install it or set shell_command = "" to use $SHELL`))
+		}
+	}
+	unnamed := 0
+	for _, g := range cfg.Groups {
+		if g.Name == "" {
+			add(cfg.missingKeyProblem(SeverityError, "groups", "name", unnamed, "group without a name", `add name = "..." to the [[groups]] table`))
+			unnamed++
+		}
+		if g.ShellCommand != "" {
+			if _, err := exec.LookPath(g.ShellCommand); err != nil {
+				add(cfg.valueProblem(SeverityWarning, "groups", g.ShellCommand, fmt.Sprintf("group %s: shell_command %q not found on $PATH", g.Name, g.ShellCommand), "install it or remove the override"))
+			}
+		}
+	}
+	if cfg.Manifest != "" {
+		if _, err := os.Stat(cfg.Manifest); err != nil {
+			add(cfg.NewProblem(SeverityWarning, "", "manifest", fmt.Sprintf("manifest %s: %v", cfg.Manifest, errors.Unwrap(err)), "fix the path or remove manifest"))
+		}
+	}
+	pathless := 0
+	for _, o := range cfg.Repos {
+		if o.Path == "" {
+			add(cfg.missingKeyProblem(SeverityError, "repo", "path", pathless, "[[repo]] without a path", `add path = "~/src/name" (globs allowed) to the table`))
+			pathless++
+			continue
+		}
+		if _, err := filepath.Match(o.Path, ""); err != nil {
+			add(cfg.valueProblem(SeverityError, "repo", o.Path, fmt.Sprintf("repo %s: bad glob: %v", o.Path, err), "fix the pattern; see filepath.Match syntax"))
+		}
+		if !validPullStrategy(o.PullStrategy) {
+			add(cfg.valueProblem(SeverityError, "repo", o.PullStrategy, fmt.Sprintf("repo %s: unknown pull_strategy %q", o.Path, o.PullStrategy), `use "ff-only", "rebase", "merge" or remove it`))
+		}
+		if o.Editor != "" {
+			if _, err := exec.LookPath(o.Editor); err != nil {
+				add(cfg.valueProblem(SeverityWarning, "repo", o.Editor, fmt.Sprintf("repo %s: editor %q not found on $PATH", o.Path, o.Editor), "install it or remove the override"))
+			}
+		}
+		for _, b := range o.Protected {
+			if _, err := filepath.Match(b, ""); err != nil {
+				add(cfg.valueProblem(SeverityError, "repo", b, fmt.Sprintf("repo %s: bad protected_branches pattern %q", o.Path, b), "fix the pattern; see filepath.Match syntax"))
+			}
+		}
+	}
+	return problems
+}
+
+// pathIssue explains why a scan path cannot be scanned, or returns "".
+func pathIssue(path string) string {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "does not exist"
+	}
+	if !info.IsDir() {
+		return "not a directory"
+	}
+	return ""
+}
+
+func validPullStrategy(s string) bool {
+	switch s {
+	case PullDefault, PullFFOnly, PullRebase, PullMerge:
+		return true
+	}
+	return false
+}
+
+func validSortMode(s string) bool {
+	for _, mode := range SortModes {
+		if mode == s {
+			return true
+		}
+	}
+	return false
+}
+
+// knownKeys are the top-level keys Load understands, for suggestions.
+var knownKeys = []string{
+	"paths", "editor", "editor_args", "editor_line_args", "refresh_interval",
+	"show_clean", "scan_depth", "path_depths", "pull_strategy", "pull_autostash",
+	"shell_command", "shell_args", "sort_mode", "keys", "theme", "groups",
+	"include", "repo", "manifest", "default_branch",
+}
+
+// unknownKeyProblem reports a key Load ignored, suggesting a known key
+// with a similar spelling.
+func (s source) unknownKeyProblem(key toml.Key) Problem {
+	table := ""
+	if len(key) > 1 {
+		table = key[:len(key)-1].String()
+	}
+	fix := "remove it"
+	if len(key) == 1 {
+		if near := closestKey(key[0]); near != "" {
+			fix = "rename it to " + near
+		}
+	}
+	line, col := s.locate(table, key[len(key)-1])
+	return Problem{Severity: SeverityWarning, Key: key[0], File: s.file, Line: line, Col: col, Message: fmt.Sprintf("unknown key %q (kept in file, ignored)", key.String()), Fix: fix}
+}
+
+func closestKey(key string) string {
+	best, bestDist := "", 3
+	for _, known := range knownKeys {
+		if d := editDistance(key, known); d < bestDist {
+			best, bestDist = known, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckReportsPositionsAndFixes(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	text := `paths = [
+  "~/missing",
+]
+scan_depth = -1
+sort_mode = "size"
+scna_depth = 2
+
+[path_depths]
+"~/elsewhere" = 3
+`
+	cfg, err := parse(filepath.Join(home, "config.toml"), text)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	want := []string{
+		`6:1: warning: unknown key "scna_depth" (kept in file, ignored) (fix: rename it to scan_depth)`,
+		`4:1: error: scan_depth must be >= 0, got -1 (fix: scan_depth = 1)`,
+		`2:3: warning: path ~/missing: does not exist`,
+		`9:1: warning: depth set for ` + filepath.Join(home, "elsewhere") + `, which is not in paths`,
+		`5:1: error: unknown sort_mode "size"`,
+	}
+	var got []string
+	for _, p := range cfg.Problems {
+		got = append(got, p.String())
+	}
+	joined := strings.Join(got, "\n")
+	for _, w := range want {
+		if !strings.Contains(joined, w) {
+			t.Errorf("missing %q in:\n%s", w, joined)
+		}
+	}
+	if !HasErrors(cfg.Problems) {
+		t.Error("expected errors")
+	}
+	if cfg.DepthFor(filepath.Join(home, "missing")) != 0 {
+		t.Error("expected negative scan_depth to scan roots only")
+	}
+}
+
+func TestCheckWarnsAboutMissingManifest(t *testing.T) {
+	dir := t.TempDir()
+	manifest := filepath.Join(dir, "team.toml")
+	cfg, err := parse(filepath.Join(dir, "config.toml"), "manifest = \""+manifest+"\"\n")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if !strings.Contains(fmt.Sprint(cfg.Problems), "1:1: warning: manifest "+manifest) {
+		t.Fatalf("expected a warning for the missing manifest, got %v", cfg.Problems)
+	}
+	if err := os.WriteFile(manifest, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if cfg, _ := parse(filepath.Join(dir, "config.toml"), "manifest = \""+manifest+"\"\n"); strings.Contains(fmt.Sprint(cfg.Problems), "manifest") {
+		t.Fatalf("unexpected problems: %v", cfg.Problems)
+	}
+}
+
+func TestParseErrorKeepsDefaultsWithPosition(t *testing.T) {
+	cfg, err := parse("config.toml", "editor = \"vim\"\nscan_depth = = 2\n")
+	if err == nil {
+		t.Fatal("expected parse error")
+	}
+	if cfg.ScanDepth != DefaultConfig().ScanDepth {
+		t.Errorf("expected defaults, got scan_depth %d", cfg.ScanDepth)
+	}
+	if len(cfg.Problems) != 1 || cfg.Problems[0].Line != 2 || cfg.Problems[0].Severity != SeverityError {
+		t.Fatalf("expected one error on line 2, got %+v", cfg.Problems)
+	}
+}
+
+func TestCheckCleanConfig(t *testing.T) {
+	dir := t.TempDir()
+	editor := filepath.Join(dir, "ed")
+	if err := os.WriteFile(editor, []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := parse(filepath.Join(dir, "config.toml"), "paths = [\""+dir+"\"]\neditor = \""+editor+"\"\n")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if strings.Contains(fmt.Sprint(cfg.Problems), "manifest") {
+		t.Fatalf("expected no problems, got %v", cfg.Problems)
+	}
+}
+
+func TestProblemsNameTheirSetting(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	text := `editor = ""
+scan_depth = -1
+include = ["missing.toml"]
+
+[[groups]]
+repos = ["api"]
+
+[path_depths]
+"~/a.b" = -1
+`
+	cfg, err := parse(filepath.Join(home, "config.toml"), text)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	keys := map[string]bool{}
+	for _, p := range cfg.Problems {
+		if p.Severity == SeverityError {
+			keys[p.Key] = true
+		}
+	}
+	for _, want := range []string{"editor", "scan_depth", "include", "groups", "path_depths"} {
+		if !keys[want] {
+			t.Errorf("no error with key %q in %v", want, cfg.Problems)
+		}
+	}
+}
@@ -4,19 +4,149 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
+	"slices"
+	"sort"
 	"strconv"
-
-	"github.com/BurntSushi/toml"
+	"strings"
 )
 
 type Config struct {
-	Paths           []string `toml:"paths"`
-	Editor          string   `toml:"editor"`
-	EditorArgs      []string `toml:"editor_args"`
-	RefreshInterval int      `toml:"refresh_interval"`
-	ShowClean       bool     `toml:"show_clean"`
-	ScanDepth       int      `toml:"scan_depth"`
+	Paths           []string           `toml:"paths"`
+	Editor          string             `toml:"editor"`
+	EditorArgs      []string           `toml:"editor_args"`
+	EditorLineArgs  []string           `toml:"editor_line_args"`
+	RefreshInterval int                `toml:"refresh_interval"`
+	ShowClean       bool               `toml:"show_clean"`
+	ScanDepth       int                `toml:"scan_depth"`
+	PathDepths      map[string]int     `toml:"path_depths"`
+	PullStrategy    string             `toml:"pull_strategy"`
+	PullAutostash   bool               `toml:"pull_autostash"`
+	ShellCommand    string             `toml:"shell_command"`
+	ShellArgs       []string           `toml:"shell_args"`
+	SortMode        string             `toml:"sort_mode"`
+	Keys            map[string]KeyList `toml:"keys"`
+	Theme           Theme              `toml:"theme"`
+	Groups          []Group            `toml:"groups"`
+	// Manifest is a manifest file listing the repos to clone (see
+	// internal/manifest).
+	Manifest string `toml:"manifest"`
+	// DefaultBranch is the branch repos are compared with, e.g. "main"
+	// or "origin/develop". Empty detects it per repo from origin/HEAD.
+	DefaultBranch string `toml:"default_branch"`
+	// Repos are [[repo]] tables overriding settings for matching repos.
+	// Paths are kept as written (~ is expanded when matching).
+	Repos []RepoOverride `toml:"repo"`
+	// Include lists config files layered under this one (see merge).
+	// Relative paths are relative to the including file.
+	Include []string `toml:"include"`
+
+	// Problems lists what Load found wrong with the file. None of them
+	// stop rtui from starting.
+	Problems []Problem `toml:"-"`
+
+	// source is the main file text, used to position problems.
+	source string
+	// includes are the included files, in load order.
+	includes []source
+	// base is the config before the main file was applied: defaults plus
+	// includes. nil without includes. Save uses it to write only what the
+	// main file holds.
+	base *Config
+	// workspace is the workspace file layered over the user config; the
+	// main file then. Empty outside a workspace.
+	workspace string
+	// project marks a .rtui.toml found from the current directory; Save
+	// then only writes projectKeys to it.
+	project bool
+}
+
+// Theme selects a built-in color theme and overrides individual styles.
+type Theme struct {
+	Name   string                   `toml:"name"`
+	Styles map[string]StyleOverride `toml:"styles"`
+}
+
+// StyleOverride replaces parts of one UI style. Colors are ANSI indices
+// ("0"-"255") or hex ("#rrggbb"); unset fields keep the theme's value.
+type StyleOverride struct {
+	Fg        string `toml:"fg"`
+	Bg        string `toml:"bg"`
+	Bold      *bool  `toml:"bold"`
+	Underline *bool  `toml:"underline"`
+	Reverse   *bool  `toml:"reverse"`
+}
+
+// Built-in theme names accepted by theme.name.
+const (
+	ThemeDark         = "dark"
+	ThemeLight        = "light"
+	ThemeHighContrast = "high-contrast"
+	ThemeSolarized    = "solarized"
+)
+
+// KeyList is the keys bound to one action. In TOML it is a single string
+// or an array of strings; an empty array unbinds the action.
+type KeyList []string
+
+// UnmarshalTOML accepts "p" as well as ["p", "ctrl+p"].
+func (k *KeyList) UnmarshalTOML(value any) error {
+	switch v := value.(type) {
+	case string:
+		*k = KeyList{v}
+	case []any:
+		keys := make(KeyList, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return fmt.Errorf("key binding must be a string, got %T", item)
+			}
+			keys = append(keys, s)
+		}
+		*k = keys
+	default:
+		return fmt.Errorf("key binding must be a string or array, got %T", value)
+	}
+	return nil
+}
+
+// Group names a set of scan paths shown as one section in the repo list.
+type Group struct {
+	Name         string   `toml:"name"`
+	Paths        []string `toml:"paths"`
+	ShellCommand string   `toml:"shell_command"`
+	ShellArgs    []string `toml:"shell_args"`
+}
+
+// Pull strategies accepted by pull_strategy. Empty defers to git config.
+const (
+	PullDefault = ""
+	PullFFOnly  = "ff-only"
+	PullRebase  = "rebase"
+	PullMerge   = "merge"
+)
+
+// Repo list orders accepted by sort_mode.
+const (
+	SortPath      = "path"
+	SortName      = "name"
+	SortCommitted = "committed"
+	SortModified  = "modified"
+	SortDirty     = "dirty"
+	SortBehind    = "behind"
+)
+
+// SortModes lists sort modes in the order the sort key cycles them.
+var SortModes = []string{SortPath, SortName, SortCommitted, SortModified, SortDirty, SortBehind}
+
+// NextSortMode returns the mode after current, wrapping around. Unknown
+// modes restart the cycle.
+func NextSortMode(current string) string {
+	for i, mode := range SortModes {
+		if mode == current {
+			return SortModes[(i+1)%len(SortModes)]
+		}
+	}
+	return SortModes[0]
 }
 
 func DefaultConfig() Config {
@@ -24,9 +154,16 @@ func DefaultConfig() Config {
 		Paths:           []string{},
 		Editor:          getDefaultEditor(),
 		EditorArgs:      []string{"--profile", "Minimalist"},
+		EditorLineArgs:  []string{},
 		RefreshInterval: 30,
 		ShowClean:       true,
 		ScanDepth:       1,
+		PullStrategy:    PullDefault,
+		PullAutostash:   false,
+		ShellCommand:    "",
+		ShellArgs:       []string{},
+		SortMode:        SortPath,
+		Theme:           Theme{Name: ThemeDark},
 	}
 }
 
@@ -37,7 +174,26 @@ func getDefaultEditor() string {
 	return "code"
 }
 
+// pathOverride is set by --config and wins over every other location.
+var pathOverride string
+
+// SetPath makes Load and Save use path (the --config flag).
+func SetPath(path string) {
+	pathOverride = NormalizePath(path)
+}
+
+// configPath resolves the config file: --config, then $RTUI_CONFIG, then
+// $XDG_CONFIG_HOME/rtui/config.toml, then ~/.config/rtui/config.toml.
 func configPath() string {
+	if pathOverride != "" {
+		return pathOverride
+	}
+	if env := os.Getenv("RTUI_CONFIG"); env != "" {
+		return NormalizePath(env)
+	}
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); filepath.IsAbs(xdg) {
+		return filepath.Join(xdg, "rtui", "config.toml")
+	}
 	home, _ := os.UserHomeDir()
 	return filepath.Join(home, ".config", "rtui", "config.toml")
 }
@@ -47,53 +203,257 @@ func ConfigPath() string {
 	return configPath()
 }
 
-// Load reads config from file, returns defaults if not found.
+// SnapshotsDir holds saved branch snapshots as <name>.toml next to the
+// config file.
+func SnapshotsDir() string {
+	return filepath.Join(filepath.Dir(configPath()), "snapshots")
+}
+
+// Load reads config from file, returns defaults if not found. Inside a
+// workspace (see WorkspacePath) the workspace file is layered on top.
+// Problems in the files are recorded in Config.Problems. A file that
+// cannot be read or parsed yields defaults and an error, which is
+// recorded as a problem too.
 func Load() (Config, error) {
 	cfg := DefaultConfig()
 
 	path := configPath()
-	if _, err := os.Stat(path); os.IsNotExist(err) {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		cfg.Problems = []Problem{parseProblem(err)}
+		return cfg, err
+	}
+	if ws := WorkspacePath(); ws != "" {
+		wsData, werr := os.ReadFile(ws)
+		if werr != nil {
+			p := parseProblem(werr)
+			p.File = ws
+			cfg.Problems = []Problem{p}
+			return cfg, werr
+		}
+		return parseWorkspace(path, string(data), ws, string(wsData))
+	}
+	if err != nil {
 		return cfg, nil
 	}
+	return parse(path, string(data))
+}
 
-	if _, err := toml.DecodeFile(path, &cfg); err != nil {
-		return cfg, err
-	}
+// parse layers the main config text and its includes over the defaults,
+// normalizes paths and validates values.
+func parse(path, text string) (Config, error) {
+	return newLoader().load(source{text: text}, path, "")
+}
 
+// normalizeConfig expands and cleans every configured path.
+func normalizeConfig(cfg *Config) {
 	for i, p := range cfg.Paths {
 		cfg.Paths[i] = NormalizePath(p)
 	}
+	if len(cfg.PathDepths) > 0 {
+		depths := make(map[string]int, len(cfg.PathDepths))
+		for p, d := range cfg.PathDepths {
+			depths[NormalizePath(p)] = d
+		}
+		cfg.PathDepths = depths
+	}
+	for i := range cfg.Groups {
+		for j, p := range cfg.Groups[i].Paths {
+			cfg.Groups[i].Paths[j] = NormalizePath(p)
+		}
+	}
+	cfg.Manifest = NormalizePath(cfg.Manifest)
+}
 
-	return cfg, nil
+// ScanPaths returns top-level paths followed by group paths, without
+// duplicates.
+func (c Config) ScanPaths() []string {
+	var out []string
+	seen := map[string]bool{}
+	add := func(p string) {
+		p = NormalizePath(p)
+		if p == "" || seen[p] {
+			return
+		}
+		seen[p] = true
+		out = append(out, p)
+	}
+	for _, p := range c.Paths {
+		add(p)
+	}
+	for _, g := range c.Groups {
+		for _, p := range g.Paths {
+			add(p)
+		}
+	}
+	return out
+}
+
+// ScanRoots returns the roots a repo scan covers: ScanPaths, else the
+// workspace's own directory, else the current directory, which is then
+// also returned as cwd.
+func (c Config) ScanRoots() (roots []string, cwd string) {
+	if roots = c.ScanPaths(); len(roots) > 0 {
+		return roots, ""
+	}
+	if c.workspace != "" {
+		return []string{filepath.Dir(c.workspace)}, ""
+	}
+	if wd, err := os.Getwd(); err == nil {
+		return []string{wd}, wd
+	}
+	return nil, ""
+}
+
+// DepthFor returns the scan depth for a root: its path_depths entry, or
+// scan_depth. Negative depths (reported by check) scan the root only.
+func (c Config) DepthFor(path string) int {
+	if d, ok := c.PathDepths[NormalizePath(path)]; ok {
+		return max(d, 0)
+	}
+	return max(c.ScanDepth, 0)
 }
 
-// Save writes config to disk.
+// Equal reports whether two configs hold the same settings, ignoring the
+// file they were read from and its problems.
+func Equal(a, b Config) bool {
+	return slices.Equal(entries(a), entries(b)) &&
+		formatGroups(a.Groups) == formatGroups(b.Groups) &&
+		formatStyles(a.Theme.Styles) == formatStyles(b.Theme.Styles) &&
+		slices.EqualFunc(a.Repos, b.Repos, RepoOverride.equal) &&
+		a.Manifest == b.Manifest && a.DefaultBranch == b.DefaultBranch
+}
+
+// Save writes config to disk. A new file is written in full; an existing
+// one is updated in place so only changed keys are touched (see
+// updateConfig).
 func Save(cfg Config) error {
-	path := configPath()
+	path := cfg.File()
 	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
 		return err
 	}
 	content := formatConfig(cfg)
+	existing, err := os.ReadFile(path)
+	if err == nil {
+		if content, err = updateConfig(string(existing), cfg); err != nil {
+			return err
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
 	return os.WriteFile(path, []byte(content), 0o644)
 }
 
 // AppendPath normalizes and appends a path, then saves config.
-// Requires existing path and ignores duplicates.
+// Requires an existing directory and ignores duplicates. cfg is only
+// changed when the save succeeds.
 func AppendPath(cfg *Config, path string) error {
 	p := NormalizePath(path)
 	if p == "" {
 		return fmt.Errorf("empty path")
 	}
-	if _, err := os.Stat(p); err != nil {
+	info, err := os.Stat(p)
+	if err != nil {
 		return err
 	}
+	if !info.IsDir() {
+		return fmt.Errorf("not a directory: %s", p)
+	}
 	for _, existing := range cfg.Paths {
 		if NormalizePath(existing) == p {
 			return nil
 		}
 	}
-	cfg.Paths = append(cfg.Paths, p)
-	return Save(*cfg)
+	next := *cfg
+	next.Paths = append(slices.Clone(cfg.Paths), p)
+	return saveInto(cfg, next)
+}
+
+// RemovePath drops a top-level path and its depth override, then saves
+// config. cfg is only changed when the save succeeds.
+func RemovePath(cfg *Config, path string) error {
+	p := NormalizePath(path)
+	index := pathIndex(cfg.Paths, p)
+	if index < 0 {
+		return fmt.Errorf("path not configured: %s", p)
+	}
+	if cfg.Included(p) {
+		return fmt.Errorf("%s comes from an included config; remove it there", p)
+	}
+	next := *cfg
+	next.Paths = append(cfg.Paths[:index:index], cfg.Paths[index+1:]...)
+	if _, ok := cfg.PathDepths[p]; ok {
+		depths := make(map[string]int, len(cfg.PathDepths))
+		for k, v := range cfg.PathDepths {
+			if k != p {
+				depths[k] = v
+			}
+		}
+		next.PathDepths = depths
+	}
+	return saveInto(cfg, next)
+}
+
+// MovePath swaps a top-level path with its neighbour delta places away,
+// then saves config. Moving past either end is a no-op. cfg is only
+// changed when the save succeeds.
+func MovePath(cfg *Config, path string, delta int) error {
+	index := pathIndex(cfg.Paths, NormalizePath(path))
+	if index < 0 {
+		return fmt.Errorf("path not configured: %s", path)
+	}
+	target := index + delta
+	if target < 0 || target >= len(cfg.Paths) {
+		return nil
+	}
+	if cfg.Included(cfg.Paths[index]) || cfg.Included(cfg.Paths[target]) {
+		return fmt.Errorf("included paths keep the order of their file")
+	}
+	next := *cfg
+	next.Paths = append([]string(nil), cfg.Paths...)
+	next.Paths[index], next.Paths[target] = next.Paths[target], next.Paths[index]
+	return saveInto(cfg, next)
+}
+
+// SetPathDepth sets the scan depth for one path, then saves config. A
+// depth equal to scan_depth removes the override. cfg is only changed
+// when the save succeeds.
+func SetPathDepth(cfg *Config, path string, depth int) error {
+	if depth < 0 {
+		return fmt.Errorf("scan depth must be >= 0")
+	}
+	p := NormalizePath(path)
+	depths := make(map[string]int, len(cfg.PathDepths)+1)
+	for k, v := range cfg.PathDepths {
+		depths[k] = v
+	}
+	if depth == cfg.ScanDepth {
+		delete(depths, p)
+	} else {
+		depths[p] = depth
+	}
+	next := *cfg
+	next.PathDepths = depths
+	return saveInto(cfg, next)
+}
+
+// saveInto saves next and, only if that worked, makes it *cfg, so a
+// failed save leaves the running config matching the file.
+func saveInto(cfg *Config, next Config) error {
+	if err := Save(next); err != nil {
+		return err
+	}
+	*cfg = next
+	return nil
+}
+
+func pathIndex(paths []string, p string) int {
+	for i, existing := range paths {
+		if NormalizePath(existing) == p {
+			return i
+		}
+	}
+	return -1
 }
 
 // NormalizePath expands ~ and cleans path.
@@ -111,34 +471,18 @@ func NormalizePath(path string) string {
 
 func formatConfig(cfg Config) string {
 	var b strings.Builder
-	if len(cfg.Paths) == 0 {
-		b.WriteString("paths = []\n\n")
-	} else {
-		b.WriteString("paths = [\n")
-		for _, p := range cfg.Paths {
-			b.WriteString("  ")
-			b.WriteString(strconv.Quote(p))
-			b.WriteString(",\n")
-		}
-		b.WriteString("]\n\n")
-	}
+	b.WriteString(formatPaths(cfg.Paths))
+	b.WriteString("\n")
 
 	b.WriteString("editor = ")
 	b.WriteString(strconv.Quote(cfg.Editor))
 	b.WriteString("\n")
 	b.WriteString("editor_args = ")
-	if len(cfg.EditorArgs) == 0 {
-		b.WriteString("[]\n")
-	} else {
-		b.WriteString("[")
-		for i, arg := range cfg.EditorArgs {
-			if i > 0 {
-				b.WriteString(", ")
-			}
-			b.WriteString(strconv.Quote(arg))
-		}
-		b.WriteString("]\n")
-	}
+	b.WriteString(formatStringArray(cfg.EditorArgs))
+	b.WriteString("\n")
+	b.WriteString("editor_line_args = ")
+	b.WriteString(formatStringArray(cfg.EditorLineArgs))
+	b.WriteString("\n")
 	b.WriteString("refresh_interval = ")
 	b.WriteString(strconv.Itoa(cfg.RefreshInterval))
 	b.WriteString("\n")
@@ -148,5 +492,159 @@ func formatConfig(cfg Config) string {
 	b.WriteString("scan_depth = ")
 	b.WriteString(strconv.Itoa(cfg.ScanDepth))
 	b.WriteString("\n")
+	b.WriteString("pull_strategy = ")
+	b.WriteString(strconv.Quote(cfg.PullStrategy))
+	b.WriteString("\n")
+	b.WriteString("pull_autostash = ")
+	b.WriteString(strconv.FormatBool(cfg.PullAutostash))
+	b.WriteString("\n")
+	b.WriteString("shell_command = ")
+	b.WriteString(strconv.Quote(cfg.ShellCommand))
+	b.WriteString("\n")
+	b.WriteString("shell_args = ")
+	b.WriteString(formatStringArray(cfg.ShellArgs))
+	b.WriteString("\n")
+	b.WriteString("sort_mode = ")
+	b.WriteString(strconv.Quote(cfg.SortMode))
+	b.WriteString("\n")
+	if len(cfg.PathDepths) > 0 {
+		b.WriteString("\n[path_depths]\n")
+		paths := make([]string, 0, len(cfg.PathDepths))
+		for p := range cfg.PathDepths {
+			paths = append(paths, p)
+		}
+		sort.Strings(paths)
+		for _, p := range paths {
+			b.WriteString(strconv.Quote(p))
+			b.WriteString(" = ")
+			b.WriteString(strconv.Itoa(cfg.PathDepths[p]))
+			b.WriteString("\n")
+		}
+	}
+	if len(cfg.Keys) > 0 {
+		b.WriteString("\n[keys]\n")
+		ids := make([]string, 0, len(cfg.Keys))
+		for id := range cfg.Keys {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			b.WriteString(id)
+			b.WriteString(" = ")
+			b.WriteString(formatKeyList(cfg.Keys[id]))
+			b.WriteString("\n")
+		}
+	}
+	b.WriteString(formatTheme(cfg.Theme))
+	b.WriteString(formatGroups(cfg.Groups))
+	return b.String()
+}
+
+// formatGroups renders every [[groups]] table.
+func formatGroups(groups []Group) string {
+	var b strings.Builder
+	for _, g := range groups {
+		b.WriteString("\n[[groups]]\n")
+		b.WriteString("name = ")
+		b.WriteString(strconv.Quote(g.Name))
+		b.WriteString("\n")
+		b.WriteString(formatPaths(g.Paths))
+		if g.ShellCommand != "" {
+			b.WriteString("shell_command = ")
+			b.WriteString(strconv.Quote(g.ShellCommand))
+			b.WriteString("\n")
+		}
+		if len(g.ShellArgs) > 0 {
+			b.WriteString("shell_args = ")
+			b.WriteString(formatStringArray(g.ShellArgs))
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+func formatTheme(theme Theme) string {
+	var b strings.Builder
+	b.WriteString("\n[theme]\n")
+	b.WriteString("name = ")
+	b.WriteString(strconv.Quote(theme.Name))
+	b.WriteString("\n")
+	b.WriteString(formatStyles(theme.Styles))
+	return b.String()
+}
+
+// formatStyles renders one [theme.styles.<name>] table per override.
+func formatStyles(styles map[string]StyleOverride) string {
+	var b strings.Builder
+	names := make([]string, 0, len(styles))
+	for name := range styles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		o := styles[name]
+		b.WriteString("\n[theme.styles.")
+		b.WriteString(name)
+		b.WriteString("]\n")
+		if o.Fg != "" {
+			b.WriteString("fg = " + strconv.Quote(o.Fg) + "\n")
+		}
+		if o.Bg != "" {
+			b.WriteString("bg = " + strconv.Quote(o.Bg) + "\n")
+		}
+		if o.Bold != nil {
+			b.WriteString("bold = " + strconv.FormatBool(*o.Bold) + "\n")
+		}
+		if o.Underline != nil {
+			b.WriteString("underline = " + strconv.FormatBool(*o.Underline) + "\n")
+		}
+		if o.Reverse != nil {
+			b.WriteString("reverse = " + strconv.FormatBool(*o.Reverse) + "\n")
+		}
+	}
+	return b.String()
+}
+
+func formatPaths(paths []string) string {
+	return "paths = " + formatPathArray(paths) + "\n"
+}
+
+// formatPathArray renders paths as a multi-line array, one per line.
+func formatPathArray(paths []string) string {
+	if len(paths) == 0 {
+		return "[]"
+	}
+	var b strings.Builder
+	b.WriteString("[\n")
+	for _, p := range paths {
+		b.WriteString("  ")
+		b.WriteString(strconv.Quote(p))
+		b.WriteString(",\n")
+	}
+	b.WriteString("]")
+	return b.String()
+}
+
+// formatKeyList renders a single key as a string, several as an array.
+func formatKeyList(keys KeyList) string {
+	if len(keys) == 1 {
+		return strconv.Quote(keys[0])
+	}
+	return formatStringArray(keys)
+}
+
+func formatStringArray(items []string) string {
+	if len(items) == 0 {
+		return "[]"
+	}
+	var b strings.Builder
+	b.WriteString("[")
+	for i, item := range items {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(strconv.Quote(item))
+	}
+	b.WriteString("]")
 	return b.String()
 }
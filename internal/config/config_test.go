@@ -86,6 +86,20 @@ func TestAppendPathMissing(t *testing.T) {
 	}
 }
 
+func TestAppendPathRejectsFile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	file := filepath.Join(home, "notes.txt")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := DefaultConfig()
+	if err := AppendPath(&cfg, file); err == nil || len(cfg.Paths) != 0 {
+		t.Fatalf("expected not-a-directory error, got %v (paths %v)", err, cfg.Paths)
+	}
+}
+
 func TestLoadNormalizesPaths(t *testing.T) {
 	home := t.TempDir()
 	t.Setenv("HOME", home)
@@ -149,3 +163,191 @@ func TestSaveWritesMultilinePaths(t *testing.T) {
 		t.Fatalf("expected repo paths in config, got:\n%s", got)
 	}
 }
+
+func TestSaveRoundTripsPullSettings(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	cfg := DefaultConfig()
+	cfg.PullStrategy = PullFFOnly
+	cfg.PullAutostash = true
+	if err := Save(cfg); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	loaded, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if loaded.PullStrategy != PullFFOnly || !loaded.PullAutostash {
+		t.Fatalf("unexpected pull settings: %q %v", loaded.PullStrategy, loaded.PullAutostash)
+	}
+}
+
+func TestGroupsRoundTripAndScanPaths(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	cfg := DefaultConfig()
+	cfg.Paths = []string{filepath.Join(home, "work")}
+	cfg.Groups = []Group{{
+		Name:         "payments",
+		Paths:        []string{"~/work/pay", filepath.Join(home, "work")},
+		ShellCommand: "lazygit",
+	}}
+	if err := Save(cfg); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	loaded, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(loaded.Groups) != 1 || loaded.Groups[0].Name != "payments" || loaded.Groups[0].ShellCommand != "lazygit" {
+		t.Fatalf("unexpected groups: %#v", loaded.Groups)
+	}
+	if loaded.Groups[0].Paths[0] != filepath.Join(home, "work", "pay") {
+		t.Fatalf("expected normalized group path, got %q", loaded.Groups[0].Paths[0])
+	}
+	paths := loaded.ScanPaths()
+	if len(paths) != 2 || paths[0] != filepath.Join(home, "work") || paths[1] != filepath.Join(home, "work", "pay") {
+		t.Fatalf("unexpected scan paths: %v", paths)
+	}
+}
+
+func TestNextSortModeCycles(t *testing.T) {
+	mode := SortPath
+	for range SortModes {
+		mode = NextSortMode(mode)
+	}
+	if mode != SortPath {
+		t.Fatalf("expected cycle back to %q, got %q", SortPath, mode)
+	}
+	if NextSortMode("bogus") != SortModes[0] {
+		t.Fatal("expected unknown mode to restart the cycle")
+	}
+}
+
+func TestSaveRoundTripsSortMode(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	cfg := DefaultConfig()
+	cfg.SortMode = SortBehind
+	if err := Save(cfg); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	loaded, err := Load()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if loaded.SortMode != SortBehind {
+		t.Fatalf("expected sort_mode %q, got %q", SortBehind, loaded.SortMode)
+	}
+}
+
+func TestKeysAcceptStringOrArrayAndRoundTrip(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	path := filepath.Join(home, ".config", "rtui", "config.toml")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	content := "[keys]\npull = \"U\"\npalette = [\":\", \"ctrl+k\"]\nundo-discard = []\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if got := cfg.Keys["pull"]; len(got) != 1 || got[0] != "U" {
+		t.Fatalf("pull = %q", got)
+	}
+	if got := cfg.Keys["palette"]; len(got) != 2 || got[1] != "ctrl+k" {
+		t.Fatalf("palette = %q", got)
+	}
+
+	if err := Save(cfg); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	reloaded, err := Load()
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if got, ok := reloaded.Keys["undo-discard"]; !ok || len(got) != 0 {
+		t.Fatalf("expected unbound undo-discard to survive, got %q (%v)", got, ok)
+	}
+	if got := reloaded.Keys["palette"]; len(got) != 2 {
+		t.Fatalf("palette after round trip = %q", got)
+	}
+}
+
+func TestThemeRoundTrip(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	bold := true
+	cfg := DefaultConfig()
+	cfg.Theme = Theme{
+		Name:   ThemeLight,
+		Styles: map[string]StyleOverride{"dirty_repo": {Fg: "#000000", Bold: &bold}},
+	}
+	if err := Save(cfg); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	loaded, err := Load()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if loaded.Theme.Name != ThemeLight {
+		t.Fatalf("theme name = %q", loaded.Theme.Name)
+	}
+	o := loaded.Theme.Styles["dirty_repo"]
+	if o.Fg != "#000000" || o.Bold == nil || !*o.Bold || o.Underline != nil {
+		t.Fatalf("unexpected override %+v", o)
+	}
+}
+
+func TestEditPathsRoundTrip(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	a, b, c := filepath.Join(home, "a"), filepath.Join(home, "b"), filepath.Join(home, "c")
+	cfg := DefaultConfig()
+	cfg.Paths = []string{a, b, c}
+	if err := SetPathDepth(&cfg, "~/b", 3); err != nil {
+		t.Fatalf("SetPathDepth: %v", err)
+	}
+	if err := MovePath(&cfg, c, -1); err != nil {
+		t.Fatalf("MovePath: %v", err)
+	}
+	if err := MovePath(&cfg, a, -1); err != nil {
+		t.Fatalf("MovePath at top: %v", err)
+	}
+	if err := RemovePath(&cfg, a); err != nil {
+		t.Fatalf("RemovePath: %v", err)
+	}
+
+	loaded, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if strings.Join(loaded.Paths, ",") != c+","+b {
+		t.Fatalf("unexpected paths: %v", loaded.Paths)
+	}
+	if loaded.DepthFor(b) != 3 || loaded.DepthFor(c) != loaded.ScanDepth {
+		t.Fatalf("unexpected depths: %v", loaded.PathDepths)
+	}
+
+	if err := SetPathDepth(&cfg, b, cfg.ScanDepth); err != nil {
+		t.Fatalf("SetPathDepth reset: %v", err)
+	}
+	if len(cfg.PathDepths) != 0 {
+		t.Fatalf("expected override removed, got %v", cfg.PathDepths)
+	}
+	if err := RemovePath(&cfg, a); err == nil {
+		t.Fatal("expected error removing an unknown path")
+	}
+}
@@ -0,0 +1,423 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// entry is one `key = value` rtui manages, addressed by its table ("" for
+// top-level keys).
+type entry struct {
+	table string
+	key   string
+	value string
+}
+
+// entries lists the keys formatConfig writes, with formatted values, so
+// Save can patch only the ones that changed.
+func entries(cfg Config) []entry {
+	out := []entry{
+		{"", "paths", formatPathArray(cfg.Paths)},
+		{"", "editor", strconv.Quote(cfg.Editor)},
+		{"", "editor_args", formatStringArray(cfg.EditorArgs)},
+		{"", "editor_line_args", formatStringArray(cfg.EditorLineArgs)},
+		{"", "refresh_interval", strconv.Itoa(cfg.RefreshInterval)},
+		{"", "show_clean", strconv.FormatBool(cfg.ShowClean)},
+		{"", "scan_depth", strconv.Itoa(cfg.ScanDepth)},
+		{"", "pull_strategy", strconv.Quote(cfg.PullStrategy)},
+		{"", "pull_autostash", strconv.FormatBool(cfg.PullAutostash)},
+		{"", "shell_command", strconv.Quote(cfg.ShellCommand)},
+		{"", "shell_args", formatStringArray(cfg.ShellArgs)},
+		{"", "sort_mode", strconv.Quote(cfg.SortMode)},
+	}
+	for _, p := range sortedKeys(cfg.PathDepths) {
+		out = append(out, entry{"path_depths", p, strconv.Itoa(cfg.PathDepths[p])})
+	}
+	for _, id := range sortedKeys(cfg.Keys) {
+		out = append(out, entry{"keys", id, formatKeyList(cfg.Keys[id])})
+	}
+	out = append(out, entry{"theme", "name", strconv.Quote(cfg.Theme.Name)})
+	return out
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// updateConfig rewrites only the parts of an existing config file whose
+// values differ from cfg. Comments, ordering, formatting and keys rtui does
+// not know are kept. [[groups]] and [theme.styles.*] are replaced as whole
+// tables, and only when they changed. With includes, the file is compared
+// as layered over them and receives only what it holds itself (see own).
+func updateConfig(text string, cfg Config) (string, error) {
+	old := DefaultConfig()
+	if cfg.base != nil {
+		old = cfg.base.clone()
+	}
+	layer, meta, err := decodeLayer(text)
+	if err != nil {
+		return "", fmt.Errorf("cannot update config in place: %w", err)
+	}
+	l := &loader{cfg: old, rawPaths: map[string]string{}}
+	l.apply(layer, meta, cfg.workspace)
+	old = l.cfg
+	normalizeConfig(&old)
+	if cfg.project {
+		// Only projectKeys were taken from the file; leave the rest of it,
+		// group shells included, as written.
+		old = restrict(old, old)
+		cfg = restrict(old, cfg)
+	}
+
+	own := cfg.own()
+	values := map[[2]string]string{}
+	for _, e := range entries(own) {
+		values[[2]string{e.table, e.key}] = e.value
+	}
+	doc := parseDocument(text)
+	current := map[[2]string]string{}
+	for _, e := range entries(old) {
+		current[[2]string{e.table, e.key}] = e.value
+	}
+	wanted := map[[2]string]bool{}
+	for _, e := range entries(cfg) {
+		id := [2]string{e.table, e.key}
+		wanted[id] = true
+		if value, ok := current[id]; !ok || value != e.value {
+			doc.set(entry{e.table, e.key, values[id]})
+		}
+	}
+	for _, e := range entries(old) {
+		if !wanted[[2]string{e.table, e.key}] {
+			doc.remove(e.table, e.key)
+		}
+	}
+	if formatGroups(old.Groups) != formatGroups(cfg.Groups) {
+		doc.replaceTables(func(name string, array bool) bool {
+			return array && name == "groups"
+		}, formatGroups(own.Groups))
+	}
+	if formatStyles(old.Theme.Styles) != formatStyles(cfg.Theme.Styles) {
+		doc.replaceTables(func(name string, array bool) bool {
+			return !array && strings.HasPrefix(name, "theme.styles.")
+		}, formatStyles(own.Theme.Styles))
+	}
+
+	out := doc.String()
+	var check Config
+	if _, err := toml.Decode(out, &check); err != nil {
+		return "", fmt.Errorf("cannot update config in place: %w", err)
+	}
+	return out, nil
+}
+
+// document is a config file as lines, with enough structure to find
+// tables and key/value spans.
+type document struct {
+	lines []string
+}
+
+type tableHeader struct {
+	line  int
+	name  string
+	array bool
+}
+
+// keySpan is a key/value over lines [start, end). comment is the byte
+// offset of a trailing comment on the last line, or -1.
+type keySpan struct {
+	table   string
+	array   bool
+	key     string
+	start   int
+	end     int
+	eq      int
+	comment int
+}
+
+func parseDocument(text string) *document {
+	return &document{lines: strings.Split(text, "\n")}
+}
+
+func (d *document) String() string {
+	return strings.Join(d.lines, "\n")
+}
+
+var headerPattern = regexp.MustCompile(`^\s*(\[\[?)\s*([^\]]+?)\s*\]\]?\s*(#.*)?$`)
+
+// scan finds table headers and key spans.
+func (d *document) scan() ([]tableHeader, []keySpan) {
+	var headers []tableHeader
+	var keys []keySpan
+	table, array := "", false
+	for i := 0; i < len(d.lines); i++ {
+		trimmed := strings.TrimSpace(d.lines[i])
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if match := headerPattern.FindStringSubmatch(d.lines[i]); match != nil {
+			table, array = unquoteKey(match[2]), match[1] == "[["
+			headers = append(headers, tableHeader{line: i, name: table, array: array})
+			continue
+		}
+		key, eq, ok := parseKey(d.lines[i])
+		if !ok {
+			continue
+		}
+		end, comment := valueEnd(d.lines, i, eq+1)
+		keys = append(keys, keySpan{table: table, array: array, key: key, start: i, end: end, eq: eq, comment: comment})
+		i = end - 1
+	}
+	return headers, keys
+}
+
+// parseKey returns the unquoted key of a `key = value` line and the
+// offset of its '='.
+func parseKey(line string) (string, int, bool) {
+	inBasic, inLiteral := false, false
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case inBasic:
+			if c == '\\' {
+				i++
+			} else if c == '"' {
+				inBasic = false
+			}
+		case inLiteral:
+			if c == '\'' {
+				inLiteral = false
+			}
+		case c == '"':
+			inBasic = true
+		case c == '\'':
+			inLiteral = true
+		case c == '#':
+			return "", 0, false
+		case c == '=':
+			return unquoteKey(strings.TrimSpace(line[:i])), i, true
+		}
+	}
+	return "", 0, false
+}
+
+func unquoteKey(key string) string {
+	if len(key) >= 2 && key[0] == '"' {
+		if s, err := strconv.Unquote(key); err == nil {
+			return s
+		}
+	}
+	if len(key) >= 2 && key[0] == '\'' && key[len(key)-1] == '\'' {
+		return key[1 : len(key)-1]
+	}
+	return key
+}
+
+// valueEnd follows a value that starts at lines[start][from:] across
+// lines until its brackets and strings close. It returns the line after
+// the value and the offset of a trailing comment on the last line.
+func valueEnd(lines []string, start, from int) (int, int) {
+	depth := 0
+	inBasic, inLiteral, inMultiBasic, inMultiLiteral := false, false, false, false
+	for i := start; i < len(lines); i++ {
+		line := lines[i]
+		j := 0
+		if i == start {
+			j = from
+		}
+		comment := -1
+		for ; j < len(line); j++ {
+			c := line[j]
+			switch {
+			case inMultiBasic:
+				if c == '\\' {
+					j++
+				} else if strings.HasPrefix(line[j:], `"""`) {
+					inMultiBasic = false
+					j += 2
+				}
+			case inMultiLiteral:
+				if strings.HasPrefix(line[j:], `'''`) {
+					inMultiLiteral = false
+					j += 2
+				}
+			case inBasic:
+				if c == '\\' {
+					j++
+				} else if c == '"' {
+					inBasic = false
+				}
+			case inLiteral:
+				if c == '\'' {
+					inLiteral = false
+				}
+			case strings.HasPrefix(line[j:], `"""`):
+				inMultiBasic = true
+				j += 2
+			case strings.HasPrefix(line[j:], `'''`):
+				inMultiLiteral = true
+				j += 2
+			case c == '"':
+				inBasic = true
+			case c == '\'':
+				inLiteral = true
+			case c == '[' || c == '{':
+				depth++
+			case c == ']' || c == '}':
+				depth--
+			case c == '#':
+				comment = j
+				j = len(line)
+			}
+		}
+		if depth <= 0 && !inMultiBasic && !inMultiLiteral {
+			return i + 1, comment
+		}
+	}
+	return len(lines), -1
+}
+
+func (s keySpan) matches(table, key string) bool {
+	if s.array || s.table != table {
+		return false
+	}
+	if table == "path_depths" {
+		return NormalizePath(s.key) == NormalizePath(key)
+	}
+	return s.key == key
+}
+
+// set replaces the value of e's key in place, keeping the key spelling,
+// indentation and trailing comment, or inserts the key into its table.
+func (d *document) set(e entry) {
+	headers, keys := d.scan()
+	for _, span := range keys {
+		if !span.matches(e.table, e.key) {
+			continue
+		}
+		last := d.lines[span.end-1]
+		suffix := ""
+		if span.comment >= 0 {
+			gap := len(strings.TrimRight(last[:span.comment], " \t"))
+			suffix = last[gap:]
+		}
+		text := d.lines[span.start][:span.eq+1] + " " + e.value + suffix
+		d.splice(span.start, span.end, strings.Split(text, "\n"))
+		return
+	}
+
+	line := formatKey(e.key) + " = " + e.value
+	insert := -1
+	for _, span := range keys {
+		if !span.array && span.table == e.table {
+			insert = span.end
+		}
+	}
+	if insert < 0 {
+		for _, h := range headers {
+			if e.table != "" && !h.array && h.name == e.table {
+				insert = h.line + 1
+				break
+			}
+		}
+	}
+	if insert < 0 && e.table == "" && len(headers) > 0 {
+		insert = headers[0].line
+		for insert > 0 && strings.HasPrefix(strings.TrimSpace(d.lines[insert-1]), "#") {
+			insert--
+		}
+	}
+	if insert < 0 {
+		block := strings.Split(line, "\n")
+		if e.table != "" {
+			block = append([]string{"[" + formatKey(e.table) + "]"}, block...)
+		}
+		d.appendBlock(block)
+		return
+	}
+	d.splice(insert, insert, strings.Split(line, "\n"))
+}
+
+// remove deletes a key and its value lines.
+func (d *document) remove(table, key string) {
+	_, keys := d.scan()
+	for _, span := range keys {
+		if span.matches(table, key) {
+			d.splice(span.start, span.end, nil)
+			return
+		}
+	}
+}
+
+// replaceTables removes every table the predicate selects and puts text
+// where the first one was, or at the end of the file.
+func (d *document) replaceTables(match func(name string, array bool) bool, text string) {
+	headers, _ := d.scan()
+	block := strings.Split(strings.TrimLeft(text, "\n"), "\n")
+	if text == "" {
+		block = nil
+	}
+	insert := -1
+	for i := len(headers) - 1; i >= 0; i-- {
+		h := headers[i]
+		if !match(h.name, h.array) {
+			continue
+		}
+		end := len(d.lines)
+		if i+1 < len(headers) {
+			end = headers[i+1].line
+		}
+		d.splice(h.line, end, nil)
+		insert = h.line
+	}
+	if insert < 0 {
+		d.appendBlock(block)
+		return
+	}
+	d.splice(insert, insert, block)
+}
+
+// appendBlock adds lines at the end, separated by a blank line.
+func (d *document) appendBlock(block []string) {
+	if len(block) == 0 {
+		return
+	}
+	for len(d.lines) > 0 && strings.TrimSpace(d.lines[len(d.lines)-1]) == "" {
+		d.lines = d.lines[:len(d.lines)-1]
+	}
+	if len(d.lines) > 0 {
+		d.lines = append(d.lines, "")
+	}
+	d.lines = append(d.lines, block...)
+	if d.lines[len(d.lines)-1] != "" {
+		d.lines = append(d.lines, "")
+	}
+}
+
+func (d *document) splice(start, end int, with []string) {
+	lines := make([]string, 0, len(d.lines)-(end-start)+len(with))
+	lines = append(lines, d.lines[:start]...)
+	lines = append(lines, with...)
+	lines = append(lines, d.lines[end:]...)
+	d.lines = lines
+}
+
+var bareKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// formatKey quotes keys that are not valid bare TOML keys.
+func formatKey(key string) string {
+	if bareKey.MatchString(key) {
+		return key
+	}
+	return strconv.Quote(key)
+}
@@ -0,0 +1,178 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const handWritten = `# my rtui config
+sort_mode = "path"          # how the list is ordered
+paths = [
+  "~/work", # day job
+]
+colour = "blue"
+editor = "vim"
+
+[keys]
+pull = "U"   # muscle memory
+
+[path_depths]
+"~/work" = 2
+
+[plugins]
+enabled = true
+
+# payments team
+[[groups]]
+name = "payments"
+paths = ["~/work/pay"]
+`
+
+func writeHandWritten(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	if err := os.MkdirAll(filepath.Join(home, "work"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(home, "oss"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	path := ConfigPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(handWritten), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return home
+}
+
+func readConfigFile(t *testing.T) string {
+	t.Helper()
+	data, err := os.ReadFile(ConfigPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestLoadWarnsAboutUnknownKeys(t *testing.T) {
+	writeHandWritten(t)
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	var unknown []string
+	for _, p := range cfg.Problems {
+		if strings.Contains(p.Message, "unknown key") {
+			unknown = append(unknown, p.String())
+		}
+	}
+	got := strings.Join(unknown, "\n")
+	if len(unknown) != 2 || !strings.Contains(got, `6:1: warning: unknown key "colour"`) || !strings.Contains(got, `"plugins"`) {
+		t.Errorf("expected one warning each for colour and plugins, got %q", got)
+	}
+	if strings.Contains(got, "keys") || strings.Contains(got, "path_depths") {
+		t.Errorf("known tables reported as unknown: %q", got)
+	}
+}
+
+func TestSaveOnlyTouchesChangedKeys(t *testing.T) {
+	home := writeHandWritten(t)
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if err := AppendPath(&cfg, filepath.Join(home, "oss")); err != nil {
+		t.Fatalf("AppendPath: %v", err)
+	}
+	cfg.SortMode = SortName
+	if err := Save(cfg); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	want := strings.Replace(handWritten, `sort_mode = "path"          #`, `sort_mode = "name"          #`, 1)
+	want = strings.Replace(want, "paths = [\n  \"~/work\", # day job\n]", "paths = [\n  \""+filepath.Join(home, "work")+"\",\n  \""+filepath.Join(home, "oss")+"\",\n]", 1)
+	if got := readConfigFile(t); got != want {
+		t.Fatalf("unexpected file:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestSaveEditsTablesInPlace(t *testing.T) {
+	home := writeHandWritten(t)
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if err := SetPathDepth(&cfg, filepath.Join(home, "work"), 3); err != nil {
+		t.Fatalf("SetPathDepth: %v", err)
+	}
+	cfg.Keys["palette"] = KeyList{":", "ctrl+k"}
+	cfg.Groups[0].Name = "billing"
+	if err := Save(cfg); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	got := readConfigFile(t)
+	for _, line := range []string{`"~/work" = 3`, `pull = "U"   # muscle memory`, `palette = [":", "ctrl+k"]`, "# payments team\n[[groups]]\nname = \"billing\"", "[plugins]\nenabled = true"} {
+		if !strings.Contains(got, line) {
+			t.Errorf("missing %q in:\n%s", line, got)
+		}
+	}
+
+	if err := RemovePath(&cfg, filepath.Join(home, "work")); err != nil {
+		t.Fatalf("RemovePath: %v", err)
+	}
+	got = readConfigFile(t)
+	if strings.Contains(got, `"~/work" = 3`) || !strings.Contains(got, "[path_depths]") {
+		t.Fatalf("expected depth line removed, table kept:\n%s", got)
+	}
+	if _, err := Load(); err != nil {
+		t.Fatalf("result does not load: %v", err)
+	}
+}
+
+func TestSaveAddsMissingKeysAndTables(t *testing.T) {
+	writeHandWritten(t)
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	cfg.PullStrategy = PullRebase
+	cfg.Theme.Name = ThemeLight
+	if err := Save(cfg); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	got := readConfigFile(t)
+	if !strings.Contains(got, "editor = \"vim\"\npull_strategy = \"rebase\"\n\n[keys]") {
+		t.Fatalf("expected new top-level key after the last one:\n%s", got)
+	}
+	loaded, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if loaded.PullStrategy != PullRebase || loaded.Theme.Name != ThemeLight || loaded.Groups[0].Name != "payments" {
+		t.Fatalf("unexpected round trip: %+v", loaded)
+	}
+}
+
+func TestSaveRefusesToOverwriteBrokenFile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	path := ConfigPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	broken := "paths = [\n"
+	if err := os.WriteFile(path, []byte(broken), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Save(DefaultConfig()); err == nil {
+		t.Fatal("expected an error for an unparsable file")
+	}
+	if readConfigFile(t) != broken {
+		t.Fatal("broken file should be left alone")
+	}
+}
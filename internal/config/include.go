@@ -0,0 +1,386 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/BurntSushi/toml"
+)
+
+// maxIncludeDepth bounds include chains; cycles are reported before that.
+const maxIncludeDepth = 8
+
+// source is one file that contributed to a config. file is empty for the
+// main config file and the include's path otherwise.
+type source struct {
+	file string
+	text string
+}
+
+// loader reads a config file and, depth first, the files it includes.
+type loader struct {
+	cfg      Config
+	stack    []string
+	includes []source
+	rawPaths map[string]string
+	problems []Problem
+	// project marks a .rtui.toml found from the current directory: it
+	// comes with the repo, so only projectKeys apply (see restrict).
+	project bool
+}
+
+func newLoader() *loader {
+	return &loader{cfg: DefaultConfig(), rawPaths: map[string]string{}}
+}
+
+// load applies the main file and its includes over what is loaded so far
+// and validates the result. from is passed to apply: "" keeps relative
+// paths as written, a file path makes them relative to that file. A main
+// file that does not decode leaves what was loaded before it.
+func (l *loader) load(main source, path, from string) (Config, error) {
+	layer, meta, err := l.decode(main)
+	if err != nil {
+		cfg := l.cfg
+		normalizeConfig(&cfg)
+		cfg.source = main.text
+		cfg.includes = l.includes
+		cfg.Problems = l.problems
+		return cfg, err
+	}
+	if l.project {
+		l.problems = append(l.problems, main.projectProblems(meta)...)
+		layer.Include = nil
+	}
+	l.includeAll(path, main, layer.Include)
+	var base *Config
+	if len(l.includes) > 0 {
+		b := l.cfg.clone()
+		normalizeConfig(&b)
+		base = &b
+	}
+	if l.project {
+		user := l.cfg.clone()
+		l.apply(layer, meta, from)
+		l.cfg = restrict(user, l.cfg)
+	} else {
+		l.apply(layer, meta, from)
+	}
+
+	cfg := l.cfg
+	cfg.source = main.text
+	cfg.includes = l.includes
+	cfg.base = base
+	cfg.project = l.project
+	cfg.Problems = l.problems
+	normalizeConfig(&cfg)
+	cfg.Problems = append(cfg.Problems, check(cfg, l.rawPaths)...)
+	return cfg, nil
+}
+
+// under loads the user config beneath a workspace file, which brings its
+// own repo set: the user's paths, groups and path_depths are dropped.
+func (l *loader) under(src source) {
+	layer, meta, err := l.decode(src)
+	if err != nil {
+		return
+	}
+	l.includeAll(src.file, src, layer.Include)
+	l.apply(layer, meta, "")
+	l.includes = append(l.includes, src)
+	l.cfg.Paths, l.cfg.Groups, l.cfg.PathDepths = []string{}, nil, nil
+	clear(l.rawPaths)
+}
+
+// decodeLayer decodes one file on its own, without defaults, so merge can
+// tell which keys it sets.
+func decodeLayer(text string) (Config, toml.MetaData, error) {
+	var layer Config
+	meta, err := toml.Decode(text, &layer)
+	return layer, meta, err
+}
+
+// decode reads one file, reporting syntax errors and unknown keys.
+func (l *loader) decode(src source) (Config, toml.MetaData, error) {
+	layer, meta, err := decodeLayer(src.text)
+	if err != nil {
+		p := parseProblem(err)
+		if p.File = src.file; p.File != "" && p.Fix == syntaxFix {
+			p.Fix = "fix the TOML syntax; the file is skipped until then"
+		}
+		l.problems = append(l.problems, p)
+		return layer, meta, err
+	}
+	reported := map[string]bool{}
+	for _, key := range meta.Undecoded() {
+		if len(key) > 1 && reported[key[:len(key)-1].String()] {
+			reported[key.String()] = true
+			continue
+		}
+		reported[key.String()] = true
+		l.problems = append(l.problems, src.unknownKeyProblem(key))
+	}
+	return layer, meta, nil
+}
+
+// includeAll loads the files a layer includes, in order, into l.cfg.
+func (l *loader) includeAll(path string, src source, entries []string) {
+	l.stack = append(l.stack, path)
+	for _, entry := range entries {
+		l.include(path, src, entry)
+	}
+	l.stack = l.stack[:len(l.stack)-1]
+}
+
+// apply merges a decoded layer over what is loaded so far. Relative paths
+// in an included file (from != "") are relative to that file.
+func (l *loader) apply(layer Config, meta toml.MetaData, from string) {
+	rebase := func(p string) string {
+		if from == "" {
+			return p
+		}
+		return resolveInclude(from, p)
+	}
+	for i, p := range layer.Paths {
+		layer.Paths[i] = rebase(p)
+		if n := NormalizePath(layer.Paths[i]); l.rawPaths[n] == "" {
+			l.rawPaths[n] = p
+		}
+	}
+	for i := range layer.Groups {
+		for j, p := range layer.Groups[i].Paths {
+			layer.Groups[i].Paths[j] = rebase(p)
+		}
+	}
+	for i := range layer.Repos {
+		layer.Repos[i].Path = rebase(layer.Repos[i].Path)
+	}
+	if layer.Manifest != "" {
+		layer.Manifest = rebase(layer.Manifest)
+	}
+	if from != "" && len(layer.PathDepths) > 0 {
+		depths := make(map[string]int, len(layer.PathDepths))
+		for p, d := range layer.PathDepths {
+			depths[rebase(p)] = d
+		}
+		layer.PathDepths = depths
+	}
+	merge(&l.cfg, layer, meta)
+}
+
+// include loads one entry of an include list. Problems are reported at
+// the entry; a broken include is skipped and the rest still load.
+func (l *loader) include(from string, src source, entry string) {
+	path := resolveInclude(from, entry)
+	line, col := src.locateValue(entry)
+	fail := func(msg, fix string) {
+		l.problems = append(l.problems, Problem{Severity: SeverityError, Key: "include", File: src.file, Line: line, Col: col, Message: msg, Fix: fix})
+	}
+	if slices.Contains(l.stack, path) {
+		fail(fmt.Sprintf("include %s is a cycle", entry), "remove the include from one of the files")
+		return
+	}
+	if len(l.stack) >= maxIncludeDepth {
+		fail(fmt.Sprintf("include %s is nested too deeply", entry), fmt.Sprintf("keep include chains under %d files", maxIncludeDepth))
+		return
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fail(fmt.Sprintf("include %s: %v", entry, err), "fix the path; relative paths are relative to the including file")
+		return
+	}
+	inc := source{file: path, text: string(data)}
+	layer, meta, err := l.decode(inc)
+	if err != nil {
+		return
+	}
+	l.includes = append(l.includes, inc)
+	l.includeAll(path, inc, layer.Include)
+	l.apply(layer, meta, path)
+}
+
+// resolveInclude expands ~ and makes entry relative to the including file.
+func resolveInclude(from, entry string) string {
+	p := NormalizePath(entry)
+	if p != "" && !filepath.IsAbs(p) {
+		p = filepath.Join(filepath.Dir(from), p)
+	}
+	return p
+}
+
+// merge applies the keys a layer sets over dst. Later layers win:
+//   - scalars and option arrays (editor_args, shell_args, ...) replace;
+//   - paths are appended, skipping ones already present;
+//   - [[groups]] replace a group of the same name in place, others append;
+//   - [[repo]] tables append (all matching tables apply, later ones win);
+//   - [keys], [path_depths] and [theme.styles] merge entry by entry.
+func merge(dst *Config, layer Config, meta toml.MetaData) {
+	set := meta.IsDefined
+	if set("editor") {
+		dst.Editor = layer.Editor
+	}
+	if set("editor_args") {
+		dst.EditorArgs = layer.EditorArgs
+	}
+	if set("editor_line_args") {
+		dst.EditorLineArgs = layer.EditorLineArgs
+	}
+	if set("refresh_interval") {
+		dst.RefreshInterval = layer.RefreshInterval
+	}
+	if set("show_clean") {
+		dst.ShowClean = layer.ShowClean
+	}
+	if set("scan_depth") {
+		dst.ScanDepth = layer.ScanDepth
+	}
+	if set("pull_strategy") {
+		dst.PullStrategy = layer.PullStrategy
+	}
+	if set("pull_autostash") {
+		dst.PullAutostash = layer.PullAutostash
+	}
+	if set("shell_command") {
+		dst.ShellCommand = layer.ShellCommand
+	}
+	if set("shell_args") {
+		dst.ShellArgs = layer.ShellArgs
+	}
+	if set("sort_mode") {
+		dst.SortMode = layer.SortMode
+	}
+	if set("manifest") {
+		dst.Manifest = layer.Manifest
+	}
+	if set("default_branch") {
+		dst.DefaultBranch = layer.DefaultBranch
+	}
+	if set("theme", "name") {
+		dst.Theme.Name = layer.Theme.Name
+	}
+
+	paths := slices.Clone(dst.Paths)
+	for _, p := range layer.Paths {
+		if n := NormalizePath(p); pathIndex(paths, n) < 0 {
+			paths = append(paths, n)
+		}
+	}
+	dst.Paths = paths
+
+	groups := slices.Clone(dst.Groups)
+	for _, g := range layer.Groups {
+		if i := groupIndex(groups, g.Name); i >= 0 && g.Name != "" {
+			groups[i] = g
+		} else {
+			groups = append(groups, g)
+		}
+	}
+	dst.Groups = groups
+	dst.Repos = append(slices.Clone(dst.Repos), layer.Repos...)
+
+	if len(layer.PathDepths) > 0 {
+		depths := maps.Clone(dst.PathDepths)
+		if depths == nil {
+			depths = map[string]int{}
+		}
+		for p, d := range layer.PathDepths {
+			depths[NormalizePath(p)] = d
+		}
+		dst.PathDepths = depths
+	}
+	if len(layer.Keys) > 0 {
+		keys := maps.Clone(dst.Keys)
+		if keys == nil {
+			keys = map[string]KeyList{}
+		}
+		maps.Copy(keys, layer.Keys)
+		dst.Keys = keys
+	}
+	if len(layer.Theme.Styles) > 0 {
+		styles := maps.Clone(dst.Theme.Styles)
+		if styles == nil {
+			styles = map[string]StyleOverride{}
+		}
+		maps.Copy(styles, layer.Theme.Styles)
+		dst.Theme.Styles = styles
+	}
+}
+
+func groupIndex(groups []Group, name string) int {
+	for i, g := range groups {
+		if g.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// clone copies c deeply enough that normalizing or editing the copy
+// leaves c alone.
+func (c Config) clone() Config {
+	c.Paths = slices.Clone(c.Paths)
+	c.EditorArgs = slices.Clone(c.EditorArgs)
+	c.EditorLineArgs = slices.Clone(c.EditorLineArgs)
+	c.ShellArgs = slices.Clone(c.ShellArgs)
+	c.PathDepths = maps.Clone(c.PathDepths)
+	c.Keys = maps.Clone(c.Keys)
+	c.Theme.Styles = maps.Clone(c.Theme.Styles)
+	groups := make([]Group, len(c.Groups))
+	for i, g := range c.Groups {
+		g.Paths = slices.Clone(g.Paths)
+		groups[i] = g
+	}
+	c.Groups = groups
+	c.Repos = slices.Clone(c.Repos)
+	return c
+}
+
+// own is what the main file itself holds: c without the paths, groups and
+// style overrides its includes already provide unchanged. Save writes
+// these so shared settings are never copied into the personal file.
+func (c Config) own() Config {
+	if c.base == nil {
+		return c
+	}
+	own := c
+	own.Paths = nil
+	for _, p := range c.Paths {
+		if pathIndex(c.base.Paths, p) < 0 {
+			own.Paths = append(own.Paths, p)
+		}
+	}
+	own.Groups = nil
+	for _, g := range c.Groups {
+		if i := groupIndex(c.base.Groups, g.Name); i < 0 || formatGroups([]Group{g}) != formatGroups([]Group{c.base.Groups[i]}) {
+			own.Groups = append(own.Groups, g)
+		}
+	}
+	own.Theme.Styles = nil
+	for name, s := range c.Theme.Styles {
+		if base, ok := c.base.Theme.Styles[name]; !ok || formatStyles(map[string]StyleOverride{name: s}) != formatStyles(map[string]StyleOverride{name: base}) {
+			if own.Theme.Styles == nil {
+				own.Theme.Styles = map[string]StyleOverride{}
+			}
+			own.Theme.Styles[name] = s
+		}
+	}
+	return own
+}
+
+// Included reports whether a top-level path comes from an included file
+// rather than the main one.
+func (c Config) Included(path string) bool {
+	return c.base != nil && pathIndex(c.base.Paths, path) >= 0
+}
+
+// IncludedFiles lists the files pulled in by include, in load order, so
+// they can be watched alongside the main file.
+func (c Config) IncludedFiles() []string {
+	files := make([]string, len(c.includes))
+	for i, s := range c.includes {
+		files[i] = s.file
+	}
+	return files
+}
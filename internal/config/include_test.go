@@ -0,0 +1,189 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigPathPrecedence(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Cleanup(func() { pathOverride = "" })
+
+	if got, want := ConfigPath(), filepath.Join(home, ".config", "rtui", "config.toml"); got != want {
+		t.Errorf("default: got %s, want %s", got, want)
+	}
+	t.Setenv("XDG_CONFIG_HOME", "relative/ignored")
+	if got := ConfigPath(); !strings.HasPrefix(got, home) {
+		t.Errorf("relative XDG_CONFIG_HOME should be ignored, got %s", got)
+	}
+	t.Setenv("XDG_CONFIG_HOME", "/xdg")
+	if got := ConfigPath(); got != "/xdg/rtui/config.toml" {
+		t.Errorf("xdg: got %s", got)
+	}
+	t.Setenv("RTUI_CONFIG", "~/rtui.toml")
+	if got := ConfigPath(); got != filepath.Join(home, "rtui.toml") {
+		t.Errorf("RTUI_CONFIG: got %s", got)
+	}
+	SetPath("/etc/rtui.toml")
+	if got := ConfigPath(); got != "/etc/rtui.toml" {
+		t.Errorf("--config: got %s", got)
+	}
+}
+
+const teamConfig = `paths = ["work"]
+scan_depth = 3
+editor_args = ["--new-window"]
+
+[keys]
+pull = "U"
+fetch = "F"
+
+[path_depths]
+"work" = 2
+
+[[groups]]
+name = "payments"
+paths = ["work/pay"]
+
+[[groups]]
+name = "infra"
+paths = ["work/infra"]
+`
+
+// writeLayered writes a team config under dir/team and a personal config
+// that includes it, and points --config at the personal one.
+func writeLayered(t *testing.T, personal string) (dir string) {
+	t.Helper()
+	dir = t.TempDir()
+	t.Setenv("HOME", dir)
+	for _, d := range []string{"team/work", "mine"} {
+		if err := os.MkdirAll(filepath.Join(dir, d), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "team", "team.toml"), []byte(teamConfig), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "config.toml")
+	if err := os.WriteFile(path, []byte(personal), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	SetPath(path)
+	t.Cleanup(func() { pathOverride = "" })
+	return dir
+}
+
+const personalConfig = `include = ["team/team.toml"]
+paths = ["~/mine"]
+scan_depth = 1
+
+[keys]
+pull = "p"
+
+[[groups]]
+name = "payments"
+paths = ["~/mine/pay"]
+`
+
+func TestIncludeMergeSemantics(t *testing.T) {
+	dir := writeLayered(t, personalConfig)
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	work := filepath.Join(dir, "team", "work")
+	if got := strings.Join(cfg.Paths, ","); got != work+","+filepath.Join(dir, "mine") {
+		t.Errorf("paths: included first, then own; got %s", got)
+	}
+	if cfg.ScanDepth != 1 {
+		t.Errorf("scalar: personal should win, got %d", cfg.ScanDepth)
+	}
+	if strings.Join(cfg.EditorArgs, " ") != "--new-window" {
+		t.Errorf("unset keys come from the include, got %v", cfg.EditorArgs)
+	}
+	if cfg.Keys["pull"][0] != "p" || cfg.Keys["fetch"][0] != "F" {
+		t.Errorf("keys merge per action, got %v", cfg.Keys)
+	}
+	if cfg.PathDepths[work] != 2 {
+		t.Errorf("path_depths from an include are relative to it, got %v", cfg.PathDepths)
+	}
+	if len(cfg.Groups) != 2 || cfg.Groups[0].Name != "payments" || cfg.Groups[0].Paths[0] != filepath.Join(dir, "mine", "pay") || cfg.Groups[1].Name != "infra" {
+		t.Errorf("groups replace by name in place, got %+v", cfg.Groups)
+	}
+	if files := cfg.IncludedFiles(); len(files) != 1 || files[0] != filepath.Join(dir, "team", "team.toml") {
+		t.Errorf("unexpected included files %v", files)
+	}
+	if !cfg.Included(work) || cfg.Included(filepath.Join(dir, "mine")) {
+		t.Error("Included should tell team paths from own ones")
+	}
+}
+
+func TestSaveWritesOnlyThePersonalLayer(t *testing.T) {
+	dir := writeLayered(t, personalConfig)
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	extra := filepath.Join(dir, "extra")
+	if err := os.MkdirAll(extra, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := AppendPath(&cfg, extra); err != nil {
+		t.Fatalf("AppendPath: %v", err)
+	}
+	data, err := os.ReadFile(ConfigPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := string(data)
+	if strings.Contains(text, "team/work") || strings.Contains(text, "infra") || strings.Contains(text, "fetch") {
+		t.Errorf("included settings copied into the personal file:\n%s", text)
+	}
+	if !strings.Contains(text, `"`+extra+`"`) || !strings.HasPrefix(text, `include = ["team/team.toml"]`) {
+		t.Errorf("expected the new path added in place:\n%s", text)
+	}
+	team, _ := os.ReadFile(filepath.Join(dir, "team", "team.toml"))
+	if string(team) != teamConfig {
+		t.Error("included file must never be written")
+	}
+
+	if err := RemovePath(&cfg, filepath.Join(dir, "team", "work")); err == nil || !strings.Contains(err.Error(), "included") {
+		t.Errorf("expected removing an included path to fail, got %v", err)
+	}
+}
+
+func TestIncludeCycleAndProblemsInIncludes(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	a := filepath.Join(dir, "a.toml")
+	b := filepath.Join(dir, "b.toml")
+	if err := os.WriteFile(a, []byte("include = [\"b.toml\"]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(b, []byte("include = [\"a.toml\"]\nsort_mode = \"size\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	SetPath(a)
+	t.Cleanup(func() { pathOverride = "" })
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	var got []string
+	for _, p := range cfg.Problems {
+		got = append(got, p.String())
+	}
+	joined := strings.Join(got, "\n")
+	for _, want := range []string{
+		`b.toml:1:12: error: include a.toml is a cycle`,
+		`b.toml:2:1: error: unknown sort_mode "size"`,
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("missing %q in:\n%s", want, joined)
+		}
+	}
+}
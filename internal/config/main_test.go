@@ -0,0 +1,24 @@
+package config
+
+import (
+	"os"
+	"testing"
+)
+
+// TestMain clears config location overrides so tests that set HOME read
+// and write the config under it, and runs from an empty directory so no
+// .rtui.toml above the checkout turns into a workspace.
+func TestMain(m *testing.M) {
+	os.Unsetenv("RTUI_CONFIG")
+	os.Unsetenv("XDG_CONFIG_HOME")
+	dir, err := os.MkdirTemp("", "rtui-test")
+	if err == nil {
+		err = os.Chdir(dir)
+	}
+	if err != nil {
+		panic(err)
+	}
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
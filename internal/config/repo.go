@@ -0,0 +1,130 @@
+package config
+
+import (
+	"path/filepath"
+	"slices"
+)
+
+// RepoOverride is a [[repo]] table: settings for the repos whose path
+// matches Path, a directory or a glob such as "~/src/work/*". Every
+// matching table applies, later ones winning for the fields they set.
+type RepoOverride struct {
+	Path           string   `toml:"path"`
+	Alias          string   `toml:"alias"`
+	Editor         string   `toml:"editor"`
+	EditorArgs     []string `toml:"editor_args"`
+	EditorLineArgs []string `toml:"editor_line_args"`
+	PullStrategy   string   `toml:"pull_strategy"`
+	Remote         string   `toml:"remote"`
+	Protected      []string `toml:"protected_branches"`
+	Hidden         bool     `toml:"hidden"`
+	NoAutoFetch    bool     `toml:"no_auto_fetch"`
+	DefaultBranch  string   `toml:"default_branch"`
+}
+
+// Matches reports whether the table applies to a repo path. A Path that
+// is not a valid glob matches nothing (check reports it).
+func (o RepoOverride) Matches(path string) bool {
+	pattern := NormalizePath(o.Path)
+	if pattern == "" {
+		return false
+	}
+	ok, err := filepath.Match(pattern, filepath.Clean(path))
+	return err == nil && ok
+}
+
+// RepoSettings merges the [[repo]] tables matching path, in file order.
+// The result's Path is the repo path.
+func (c Config) RepoSettings(path string) RepoOverride {
+	out := RepoOverride{Path: path}
+	for _, o := range c.Repos {
+		if !o.Matches(path) {
+			continue
+		}
+		if o.Alias != "" {
+			out.Alias = o.Alias
+		}
+		if o.Editor != "" {
+			// Another editor's flags rarely fit, so a new editor starts
+			// from its own args.
+			out.Editor = o.Editor
+			out.EditorArgs = o.EditorArgs
+			out.EditorLineArgs = o.EditorLineArgs
+		}
+		if o.EditorArgs != nil {
+			out.EditorArgs = o.EditorArgs
+		}
+		if o.EditorLineArgs != nil {
+			out.EditorLineArgs = o.EditorLineArgs
+		}
+		if o.PullStrategy != "" {
+			out.PullStrategy = o.PullStrategy
+		}
+		if o.Remote != "" {
+			out.Remote = o.Remote
+		}
+		if o.Protected != nil {
+			out.Protected = o.Protected
+		}
+		if o.DefaultBranch != "" {
+			out.DefaultBranch = o.DefaultBranch
+		}
+		out.Hidden = out.Hidden || o.Hidden
+		out.NoAutoFetch = out.NoAutoFetch || o.NoAutoFetch
+	}
+	return out
+}
+
+// ForRepo returns c with the global settings a [[repo]] table can
+// override (editor, editor_args, editor_line_args, pull_strategy)
+// replaced by the repo's values.
+func (c Config) ForRepo(path string) Config {
+	o := c.RepoSettings(path)
+	if o.Editor != "" {
+		c.Editor = o.Editor
+		c.EditorArgs = o.EditorArgs
+		c.EditorLineArgs = o.EditorLineArgs
+	} else {
+		if o.EditorArgs != nil {
+			c.EditorArgs = o.EditorArgs
+		}
+		if o.EditorLineArgs != nil {
+			c.EditorLineArgs = o.EditorLineArgs
+		}
+	}
+	if o.PullStrategy != "" {
+		c.PullStrategy = o.PullStrategy
+	}
+	return c
+}
+
+// DefaultBranchFor returns the configured default branch of a repo: its
+// [[repo]] default_branch, else default_branch. Empty means detect it.
+func (c Config) DefaultBranchFor(path string) string {
+	if b := c.RepoSettings(path).DefaultBranch; b != "" {
+		return b
+	}
+	return c.DefaultBranch
+}
+
+// IsProtected reports whether branch is listed in protected_branches.
+// Entries may be globs such as "release/*".
+func (o RepoOverride) IsProtected(branch string) bool {
+	if branch == "" {
+		return false
+	}
+	return slices.ContainsFunc(o.Protected, func(pattern string) bool {
+		ok, err := filepath.Match(pattern, branch)
+		return err == nil && ok
+	})
+}
+
+func (o RepoOverride) equal(p RepoOverride) bool {
+	return o.Path == p.Path && o.Alias == p.Alias && o.Editor == p.Editor &&
+		slices.Equal(o.EditorArgs, p.EditorArgs) &&
+		slices.Equal(o.EditorLineArgs, p.EditorLineArgs) &&
+		o.PullStrategy == p.PullStrategy && o.Remote == p.Remote &&
+		slices.Equal(o.Protected, p.Protected) &&
+		o.Hidden == p.Hidden && o.NoAutoFetch == p.NoAutoFetch &&
+		o.DefaultBranch == p.DefaultBranch
+}
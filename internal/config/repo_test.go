@@ -0,0 +1,106 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRepoSettingsMergeMatchingTables(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Repos = []RepoOverride{
+		{Path: "/src/work/*", PullStrategy: PullRebase, Protected: []string{"main", "release/*"}, NoAutoFetch: true},
+		{Path: "/src/work/pay-api", Alias: "payments", Editor: "nvim", Remote: "upstream"},
+		{Path: "/src/personal/*", Hidden: true},
+	}
+
+	got := cfg.RepoSettings("/src/work/pay-api")
+	if got.Alias != "payments" || got.PullStrategy != PullRebase || got.Remote != "upstream" || !got.NoAutoFetch || got.Hidden {
+		t.Errorf("unexpected settings %+v", got)
+	}
+	if !got.IsProtected("release/1.2") || !got.IsProtected("main") || got.IsProtected("feature") {
+		t.Errorf("protected globs not applied: %v", got.Protected)
+	}
+	if !cfg.RepoSettings("/src/personal/blog").Hidden {
+		t.Error("expected glob to hide /src/personal/blog")
+	}
+	if o := cfg.RepoSettings("/elsewhere/x"); o.Alias != "" || o.Hidden || o.PullStrategy != "" {
+		t.Errorf("expected no overrides outside the globs, got %+v", o)
+	}
+
+	repo := cfg.ForRepo("/src/work/pay-api")
+	if repo.Editor != "nvim" || len(repo.EditorArgs) != 0 || repo.PullStrategy != PullRebase {
+		t.Errorf("ForRepo: editor %q args %v pull %q", repo.Editor, repo.EditorArgs, repo.PullStrategy)
+	}
+	if other := cfg.ForRepo("/src/work/infra"); other.Editor != cfg.Editor || len(other.EditorArgs) != 2 {
+		t.Errorf("a repo without an editor override keeps the global editor, got %q %v", other.Editor, other.EditorArgs)
+	}
+}
+
+func TestDefaultBranchFor(t *testing.T) {
+	cfg := DefaultConfig()
+	if got := cfg.DefaultBranchFor("/src/work/api"); got != "" {
+		t.Fatalf("expected detection by default, got %q", got)
+	}
+	cfg.DefaultBranch = "main"
+	cfg.Repos = []RepoOverride{{Path: "/src/work/*", DefaultBranch: "origin/develop"}}
+	if got := cfg.DefaultBranchFor("/src/work/api"); got != "origin/develop" {
+		t.Errorf("[[repo]] default_branch = %q, want origin/develop", got)
+	}
+	if got := cfg.DefaultBranchFor("/src/blog"); got != "main" {
+		t.Errorf("default_branch = %q, want main", got)
+	}
+}
+
+func TestRepoTablesLoadCheckAndSurviveSave(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	text := `paths = ["~"]
+editor = "sh"
+
+# per-repo settings
+[[repo]]
+path = "~/src/*"
+pull_strategy = "squash"
+
+[[repo]]
+alias = "nameless"
+`
+	path := filepath.Join(home, "config.toml")
+	if err := os.WriteFile(path, []byte(text), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	SetPath(path)
+	t.Cleanup(func() { pathOverride = "" })
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(cfg.Repos) != 2 || cfg.RepoSettings(filepath.Join(home, "src", "api")).PullStrategy != "squash" {
+		t.Fatalf("unexpected repo tables %+v", cfg.Repos)
+	}
+	var got []string
+	for _, p := range cfg.Problems {
+		got = append(got, p.String())
+	}
+	joined := strings.Join(got, "\n")
+	for _, want := range []string{
+		`7:17: error: repo ~/src/*: unknown pull_strategy "squash"`,
+		`9:1: error: [[repo]] without a path`,
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("missing %q in:\n%s", want, joined)
+		}
+	}
+
+	cfg.ScanDepth = 2
+	if err := Save(cfg); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "# per-repo settings\n[[repo]]\npath = \"~/src/*\"") || !strings.Contains(string(data), "scan_depth = 2") {
+		t.Errorf("[[repo]] tables should be kept as written:\n%s", data)
+	}
+}
@@ -0,0 +1,189 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// WorkspaceFile is the name of a project-local workspace file, looked up
+// in the current directory and its ancestors.
+const WorkspaceFile = ".rtui.toml"
+
+// workspaceName is set by --workspace and wins over a .rtui.toml found
+// from the current directory.
+var workspaceName string
+
+// SetWorkspace selects a named workspace from WorkspacesDir (the
+// --workspace flag).
+func SetWorkspace(name string) {
+	workspaceName = name
+}
+
+// WorkspacesDir holds named workspaces as <name>.toml next to the config
+// file.
+func WorkspacesDir() string {
+	return filepath.Join(filepath.Dir(configPath()), "workspaces")
+}
+
+// Workspaces lists the named workspaces, sorted.
+func Workspaces() []string {
+	entries, err := os.ReadDir(WorkspacesDir())
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, e := range entries {
+		if name, ok := strings.CutSuffix(e.Name(), ".toml"); ok && !e.IsDir() && name != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WorkspacePath returns the active workspace file: the named one, else
+// the nearest .rtui.toml from the current directory up, else "".
+func WorkspacePath() string {
+	if workspaceName != "" {
+		return filepath.Join(WorkspacesDir(), workspaceName+".toml")
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	if ws := FindWorkspace(wd); ws != configPath() {
+		return ws
+	}
+	return ""
+}
+
+// FindWorkspace returns the .rtui.toml in dir or its closest ancestor
+// that has one, or "".
+func FindWorkspace(dir string) string {
+	for dir = filepath.Clean(dir); ; {
+		path := filepath.Join(dir, WorkspaceFile)
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+			return path
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// projectKeys are the keys a .rtui.toml found from the current directory
+// may set: the repo set and how it is listed. Anything that runs a
+// command (editor, shell, [[repo]] editors, group shells) or pulls in
+// other files comes only from the user's own files, so cloning a repo and
+// starting rtui in it cannot choose what o or t run.
+var projectKeys = map[string]bool{
+	"paths": true, "groups": true, "path_depths": true, "scan_depth": true,
+	"show_clean": true, "sort_mode": true, "theme": true, "manifest": true,
+	"default_branch": true,
+}
+
+// restrict keeps user and takes only the projectKeys settings from ws.
+func restrict(user, ws Config) Config {
+	user.Paths, user.PathDepths = ws.Paths, ws.PathDepths
+	user.ScanDepth, user.ShowClean, user.SortMode = ws.ScanDepth, ws.ShowClean, ws.SortMode
+	user.Theme, user.Manifest, user.DefaultBranch = ws.Theme, ws.Manifest, ws.DefaultBranch
+	user.Groups = make([]Group, len(ws.Groups))
+	for i, g := range ws.Groups {
+		user.Groups[i] = Group{Name: g.Name, Paths: g.Paths}
+	}
+	return user
+}
+
+// projectProblems warns about each key of a project workspace that
+// restrict ignores.
+func (s source) projectProblems(meta toml.MetaData) []Problem {
+	var problems []Problem
+	seen := map[string]bool{}
+	for _, key := range meta.Keys() {
+		name, table := key[0], ""
+		if name == "groups" && len(key) == 2 && (key[1] == "shell_command" || key[1] == "shell_args") {
+			name, table = key[1], key[0]
+		} else if projectKeys[name] {
+			continue
+		}
+		if id := table + "." + name; !seen[id] {
+			seen[id] = true
+			line, col := s.locate(table, name)
+			if table != "" {
+				line, col = s.locateArrayKey(table, name)
+			} else if line == 0 {
+				line, col = s.locate(name, "")
+			}
+			problems = append(problems, Problem{
+				Severity: SeverityWarning, Key: key[0], File: s.file, Line: line, Col: col,
+				Message: fmt.Sprintf("%s is ignored in %s: a project workspace only sets the repo list", strings.TrimPrefix(id, "."), WorkspaceFile),
+				Fix:     "set it in your own config.toml, or use a named workspace (--workspace)",
+			})
+		}
+	}
+	return problems
+}
+
+// locateArrayKey finds the first key in a [[table]] entry; locate only
+// looks at plain tables.
+func (s source) locateArrayKey(table, key string) (int, int) {
+	doc := parseDocument(s.text)
+	_, keys := doc.scan()
+	for _, span := range keys {
+		if span.array && span.table == table && span.key == key {
+			return span.start + 1, indexOf(doc.lines[span.start], strings.TrimSpace(doc.lines[span.start])) + 1
+		}
+	}
+	return s.locate(table, "")
+}
+
+// parseWorkspace layers a workspace file over the user config. The
+// workspace brings its own repo set, so the user's paths, groups and
+// path_depths are dropped; every other setting is inherited and can be
+// overridden, except that a .rtui.toml found from the current directory
+// only sets projectKeys. Relative paths in the workspace file are
+// relative to it.
+func parseWorkspace(userPath, userText, path, text string) (Config, error) {
+	l := newLoader()
+	l.project = workspaceName == ""
+	if userText != "" {
+		l.under(source{file: userPath, text: userText})
+	}
+	cfg, err := l.load(source{text: text}, path, path)
+	cfg.workspace = path
+	return cfg, err
+}
+
+// Workspace returns the workspace file this config was loaded with, or
+// "" outside a workspace.
+func (c Config) Workspace() string {
+	return c.workspace
+}
+
+// WorkspaceName names the active workspace: the name of a named one, the
+// directory holding a .rtui.toml otherwise.
+func (c Config) WorkspaceName() string {
+	if c.workspace == "" {
+		return ""
+	}
+	if filepath.Base(c.workspace) == WorkspaceFile {
+		return filepath.Base(filepath.Dir(c.workspace))
+	}
+	return strings.TrimSuffix(filepath.Base(c.workspace), ".toml")
+}
+
+// File is the file UI edits are saved to: the workspace file when one is
+// active, the config file otherwise.
+func (c Config) File() string {
+	if c.workspace != "" {
+		return c.workspace
+	}
+	return configPath()
+}
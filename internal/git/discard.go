@@ -0,0 +1,48 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// IndexEntry is a file's staged blob, enough to put it back in the index.
+type IndexEntry struct {
+	Mode string
+	SHA  string
+}
+
+// ReadIndexEntry returns the staged blob of file; ok is false when the
+// file is not in the index.
+func ReadIndexEntry(path, file string) (IndexEntry, bool, error) {
+	out, err := gitOutput(path, "ls-files", "--stage", "--", file)
+	if err != nil {
+		return IndexEntry{}, false, err
+	}
+	fields := strings.Fields(out)
+	if len(fields) < 3 {
+		return IndexEntry{}, false, nil
+	}
+	return IndexEntry{Mode: fields[0], SHA: fields[1]}, true, nil
+}
+
+// WriteIndexEntry stages a previously read blob for file.
+func WriteIndexEntry(path, file string, entry IndexEntry) error {
+	info := fmt.Sprintf("%s,%s,%s", entry.Mode, entry.SHA, file)
+	return gitRun(path, "update-index", "--add", "--cacheinfo", info)
+}
+
+// RestoreWorktree discards unstaged changes to file.
+func RestoreWorktree(path, file string) error {
+	return gitRun(path, "restore", "--", file)
+}
+
+// Unstage removes file's staged changes, keeping the worktree.
+func Unstage(path, file string) error {
+	return gitRun(path, "restore", "--staged", "--", file)
+}
+
+// RemoveUntracked deletes an untracked file or directory. git clean never
+// touches tracked or ignored files.
+func RemoveUntracked(path, file string) error {
+	return gitRun(path, "clean", "-f", "-d", "--", file)
+}
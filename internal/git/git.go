@@ -3,11 +3,14 @@ package git
 import (
 	"bufio"
 	"bytes"
+	"errors"
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type FileStatus int
@@ -27,47 +30,88 @@ type ChangedFile struct {
 type Repo struct {
 	Name         string
 	Path         string
+	Root         string
 	Branch       string
 	Staged       int
 	Modified     int
 	Untracked    int
+	Upstream     string
 	Ahead        int
 	Behind       int
 	HasConflict  bool
+	State        RepoState
 	ChangedFiles []ChangedFile
+	LastCommit   time.Time
+	LastModified time.Time
+
+	// DefaultBranch is the ref HEAD is compared with, e.g. origin/main;
+	// empty when unknown. DefaultAhead and DefaultBehind count the
+	// commits only on HEAD and only on it (see CompareDefault).
+	DefaultBranch string
+	DefaultAhead  int
+	DefaultBehind int
 }
 
 func (r Repo) IsDirty() bool {
 	return r.Staged > 0 || r.Modified > 0 || r.Untracked > 0
 }
 
-// ScanRepos finds all git repos in given paths up to depth.
+// ScanRepos finds all git repos in given paths up to depth. Each repo
+// records the scan root it was found under; overlapping roots do not
+// produce duplicates.
 func ScanRepos(paths []string, depth int) []Repo {
+	return ScanReposDepths(paths, func(string) int { return depth }, nil)
+}
+
+// ScanReposDepths is ScanRepos with a depth chosen per root, comparing
+// each repo with the default branch defaultFor names for it. A nil
+// defaultFor, or an empty name, detects the default branch.
+func ScanReposDepths(paths []string, depthFor func(root string) int, defaultFor func(path string) string) []Repo {
 	var repos []Repo
+	seen := map[string]bool{}
 
 	for _, basePath := range paths {
-		filepath.WalkDir(basePath, func(path string, d os.DirEntry, err error) error {
-			if err != nil {
-				return nil
+		for _, path := range FindRepos(basePath, depthFor(basePath)) {
+			if seen[path] {
+				continue
 			}
-
-			relPath, _ := filepath.Rel(basePath, path)
-			currentDepth := strings.Count(relPath, string(os.PathSeparator))
-			if currentDepth > depth {
-				return filepath.SkipDir
+			seen[path] = true
+			ref := ""
+			if defaultFor != nil {
+				ref = defaultFor(path)
 			}
+			repo := GetRepoStatusAgainst(path, ref)
+			repo.Root = basePath
+			repos = append(repos, repo)
+		}
+	}
 
-			if d.IsDir() && isGitRepo(path) {
-				repo := GetRepoStatus(path)
-				repos = append(repos, repo)
-				return filepath.SkipDir
-			}
+	return repos
+}
 
+// FindRepos lists git repo directories under root up to depth without
+// reading their status.
+func FindRepos(root string, depth int) []string {
+	var found []string
+	filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
 			return nil
-		})
-	}
+		}
 
-	return repos
+		relPath, _ := filepath.Rel(root, path)
+		currentDepth := strings.Count(relPath, string(os.PathSeparator))
+		if currentDepth > depth {
+			return filepath.SkipDir
+		}
+
+		if d.IsDir() && isGitRepo(path) {
+			found = append(found, path)
+			return filepath.SkipDir
+		}
+
+		return nil
+	})
+	return found
 }
 
 func isGitRepo(path string) bool {
@@ -76,8 +120,15 @@ func isGitRepo(path string) bool {
 	return err == nil
 }
 
-// GetRepoStatus gets full status for a repo.
+// GetRepoStatus gets full status for a repo, compared with its detected
+// default branch.
 func GetRepoStatus(path string) Repo {
+	return GetRepoStatusAgainst(path, "")
+}
+
+// GetRepoStatusAgainst is GetRepoStatus compared with defaultRef, the
+// configured default branch; "" detects it (see DefaultBranch).
+func GetRepoStatusAgainst(path, defaultRef string) Repo {
 	repo := Repo{
 		Name: filepath.Base(path),
 		Path: path,
@@ -85,15 +136,81 @@ func GetRepoStatus(path string) Repo {
 
 	repo.Branch = getBranch(path)
 
-	if out, err := gitOutput(path, "status", "--porcelain=v1"); err == nil {
+	if out, err := gitOutput(path, "status", "--porcelain=v1", "-z"); err == nil {
 		parsePorcelain(&repo, out)
 	}
 
+	repo.State = getRepoState(path)
+	repo.Upstream = getUpstream(path)
 	repo.Ahead, repo.Behind = getAheadBehind(path)
+	if defaultRef == "" {
+		defaultRef = DefaultBranch(path)
+	}
+	repo.CompareDefault(defaultRef)
+	repo.LastCommit = getLastCommit(path)
+	repo.LastModified = getLastModified(path, repo.ChangedFiles)
 
 	return repo
 }
 
+// ConflictFiles returns the files with unresolved conflicts.
+func (r Repo) ConflictFiles() []ChangedFile {
+	var files []ChangedFile
+	for _, f := range r.ChangedFiles {
+		if f.Status == StatusConflict {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+// IsDetached reports whether the repo HEAD is not on a branch.
+func (r Repo) IsDetached() bool {
+	return r.Branch == "detached" || strings.HasPrefix(r.Branch, "detached@")
+}
+
+// HasDiverged reports whether local and upstream both have unique commits,
+// which is the usual state after a rebase or amend of pushed work.
+func (r Repo) HasDiverged() bool {
+	return r.Ahead > 0 && r.Behind > 0
+}
+
+// OnDefaultBranch reports whether HEAD is the default branch itself, or
+// its local counterpart (main for origin/main).
+func (r Repo) OnDefaultBranch() bool {
+	return r.DefaultBranch != "" && (r.Branch == r.DefaultBranch || strings.HasSuffix(r.DefaultBranch, "/"+r.Branch))
+}
+
+// IsMerged reports whether the checked out branch has landed in the
+// default branch: it is clean, has no commits the default branch lacks
+// and its tip is behind the default tip. A branch still at the default
+// tip (just created, nothing committed) is new, not merged.
+func (r Repo) IsMerged() bool {
+	return r.DefaultBranch != "" && !r.IsDetached() && !r.OnDefaultBranch() && !r.IsDirty() &&
+		r.DefaultAhead == 0 && r.DefaultBehind > 0
+}
+
+// IsNew reports whether the checked out branch is still at the default
+// tip, with nothing of its own committed yet.
+func (r Repo) IsNew() bool {
+	return r.DefaultBranch != "" && !r.IsDetached() && !r.OnDefaultBranch() &&
+		r.DefaultAhead == 0 && r.DefaultBehind == 0
+}
+
+// CompareDefault sets DefaultBranch to ref and counts how far HEAD has
+// diverged from it. An empty or unknown ref clears the comparison.
+func (r *Repo) CompareDefault(ref string) {
+	r.DefaultBranch, r.DefaultAhead, r.DefaultBehind = "", 0, 0
+	if ref == "" {
+		return
+	}
+	ahead, behind, err := CommitsBetween(r.Path, "HEAD", ref)
+	if err != nil {
+		return
+	}
+	r.DefaultBranch, r.DefaultAhead, r.DefaultBehind = ref, ahead, behind
+}
+
 func getBranch(path string) string {
 	out, err := gitOutput(path, "rev-parse", "--abbrev-ref", "HEAD")
 	if err != nil {
@@ -111,6 +228,14 @@ func getBranch(path string) string {
 	return branch
 }
 
+func getUpstream(path string) string {
+	out, err := gitOutput(path, "rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{upstream}")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(out)
+}
+
 // getAheadBehind uses git CLI for reliable remote comparison.
 func getAheadBehind(path string) (ahead, behind int) {
 	out, err := gitOutput(path, "rev-list", "--left-right", "--count", "HEAD...@{upstream}")
@@ -127,6 +252,35 @@ func getAheadBehind(path string) (ahead, behind int) {
 	return
 }
 
+func getLastCommit(path string) time.Time {
+	out, err := gitOutput(path, "log", "-1", "--format=%ct")
+	if err != nil {
+		return time.Time{}
+	}
+	secs, err := strconv.ParseInt(strings.TrimSpace(out), 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(secs, 0)
+}
+
+// getLastModified returns the newest mtime among changed files. A clean
+// tree falls back to the index, which git touches on commit and checkout.
+func getLastModified(path string, files []ChangedFile) time.Time {
+	var latest time.Time
+	for _, f := range files {
+		if info, err := os.Lstat(filepath.Join(path, f.Path)); err == nil && info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	if latest.IsZero() {
+		if info, err := os.Stat(filepath.Join(gitDir(path), "index")); err == nil {
+			latest = info.ModTime()
+		}
+	}
+	return latest
+}
+
 // CommitAll stages all changes (including untracked) and commits.
 func CommitAll(path, message string) error {
 	addCmd := exec.Command("git", "add", "-A")
@@ -146,15 +300,157 @@ func CommitAll(path, message string) error {
 
 // Push runs git push.
 func Push(path string) error {
-	cmd := exec.Command("git", "push")
-	cmd.Dir = path
-	return cmd.Run()
+	return gitRun(path, "push")
 }
 
-func Pull(path string) error {
-	cmd := exec.Command("git", "pull")
-	cmd.Dir = path
-	return cmd.Run()
+// PushSetUpstream pushes branch to remote and records it as upstream.
+func PushSetUpstream(path, remote, branch string) error {
+	return gitRun(path, "push", "-u", remote, branch)
+}
+
+// PushForceWithLease force-pushes only if the remote still matches the
+// last fetched upstream. Commits pushed by others since the last fetch
+// make it fail; fetched upstream commits missing locally are discarded,
+// so callers check RewroteUpstream first.
+func PushForceWithLease(path string) error {
+	return gitRun(path, "push", "--force-with-lease")
+}
+
+// RewroteUpstream reports whether an earlier tip of branch (from its
+// reflog) contained the current upstream tip, i.e. the upstream commits
+// HEAD lacks were rewritten locally by a rebase, amend or reset. A branch
+// that is behind because others pushed never contained them.
+func RewroteUpstream(path, branch string) bool {
+	tip, err := gitOutput(path, "rev-parse", "--verify", "--quiet", "@{upstream}")
+	if err != nil {
+		return false
+	}
+	tip = strings.TrimSpace(tip)
+	out, err := gitOutput(path, "reflog", "--format=%H", "-n", "50", "refs/heads/"+branch)
+	if err != nil {
+		return false
+	}
+	for _, sha := range strings.Fields(out) {
+		if gitRun(path, "merge-base", "--is-ancestor", tip, sha) == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// RemoteURL returns the fetch URL of a remote.
+func RemoteURL(path, remote string) (string, error) {
+	out, err := gitOutput(path, "remote", "get-url", remote)
+	if err != nil {
+		return "", fmt.Errorf("no remote %s", remote)
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// Clone clones url into path, checking out branch when it is set. The
+// parent directory is created; path itself must not exist yet.
+func Clone(url, path, branch string) error {
+	parent := filepath.Dir(path)
+	if err := os.MkdirAll(parent, 0o755); err != nil {
+		return err
+	}
+	args := []string{"clone"}
+	if branch != "" {
+		args = append(args, "--branch", branch)
+	}
+	return gitRun(parent, append(args, "--", url, path)...)
+}
+
+// ListRemotes returns configured remote names.
+func ListRemotes(path string) ([]string, error) {
+	out, err := gitOutput(path, "remote")
+	if err != nil {
+		return nil, err
+	}
+	var remotes []string
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" {
+			remotes = append(remotes, line)
+		}
+	}
+	return remotes, nil
+}
+
+// PullOptions controls how Pull integrates upstream changes.
+type PullOptions struct {
+	// Strategy is "ff-only", "rebase", "merge" or empty for git's default.
+	Strategy  string
+	Autostash bool
+}
+
+// PullResult summarizes what a pull brought in.
+type PullResult struct {
+	Commits int
+	Files   []string
+}
+
+// Pull runs git pull with the given options and reports the incoming
+// commits (upstream commits HEAD lacked before, so neither a merge commit
+// nor rebased local commits count) and the files changed between HEAD
+// before and after.
+func Pull(path string, opts PullOptions) (PullResult, error) {
+	args, err := pullArgs(opts)
+	if err != nil {
+		return PullResult{}, err
+	}
+	before := headSHA(path)
+	if err := gitRun(path, args...); err != nil {
+		return PullResult{}, err
+	}
+	after := headSHA(path)
+	if before == "" || after == "" || before == after {
+		return PullResult{}, nil
+	}
+
+	var result PullResult
+	incoming := before + "..@{upstream}"
+	if _, err := gitOutput(path, "rev-parse", "--verify", "--quiet", "@{upstream}"); err != nil {
+		incoming = before + ".." + after
+	}
+	if out, err := gitOutput(path, "rev-list", "--count", incoming); err == nil {
+		result.Commits, _ = strconv.Atoi(strings.TrimSpace(out))
+	}
+	if out, err := gitOutput(path, "diff", "--name-only", before, after); err == nil {
+		for _, line := range strings.Split(out, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				result.Files = append(result.Files, line)
+			}
+		}
+	}
+	return result, nil
+}
+
+func pullArgs(opts PullOptions) ([]string, error) {
+	args := []string{"pull"}
+	switch opts.Strategy {
+	case "":
+	case "ff-only":
+		args = append(args, "--ff-only")
+	case "rebase":
+		args = append(args, "--rebase")
+	case "merge":
+		args = append(args, "--no-rebase")
+	default:
+		return nil, fmt.Errorf("unknown pull strategy %q", opts.Strategy)
+	}
+	if opts.Autostash {
+		args = append(args, "--autostash")
+	}
+	return args, nil
+}
+
+func headSHA(path string) string {
+	out, err := gitOutput(path, "rev-parse", "HEAD")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(out)
 }
 
 func FetchAll(path string) error {
@@ -215,23 +511,161 @@ func StashPush(path string) error {
 	return cmd.Run()
 }
 
-func OpenInEditor(path, editor string, editorArgs []string) error {
-	args := append([]string{}, editorArgs...)
-	args = append(args, path)
-	cmd := exec.Command(editor, args...)
-	return cmd.Start()
+// StashPop reapplies and drops the latest stash.
+func StashPop(path string) error {
+	return gitRun(path, "stash", "pop")
+}
+
+// SwitchBranch checks out a local branch, or a tracking branch created
+// from a remote one such as origin/feature. With stash set, local changes
+// (untracked included) are stashed first.
+func SwitchBranch(path, branch string, remote, stash bool) error {
+	if stash {
+		if err := StashPush(path); err != nil {
+			return err
+		}
+	}
+	if remote {
+		return CheckoutRemoteBranch(path, branch)
+	}
+	return CheckoutBranch(path, branch)
+}
+
+// CreateBranch creates branch from start and checks it out, stashing
+// local changes first when stash is set. The new branch does not track
+// start, so a later push offers to set its upstream.
+func CreateBranch(path, branch, start string, stash bool) error {
+	if stash {
+		if err := StashPush(path); err != nil {
+			return err
+		}
+	}
+	return gitRun(path, "checkout", "--no-track", "-b", branch, start)
+}
+
+// DefaultBranch returns the branch new work starts from: origin/HEAD
+// (e.g. "origin/main") when the remote has one, else a local main or
+// master, else "".
+func DefaultBranch(path string) string {
+	if out, err := gitOutput(path, "symbolic-ref", "--quiet", "--short", "refs/remotes/origin/HEAD"); err == nil {
+		if ref := strings.TrimSpace(out); ref != "" {
+			return ref
+		}
+	}
+	for _, b := range []string{"main", "master"} {
+		if _, err := gitOutput(path, "rev-parse", "--verify", "--quiet", "refs/heads/"+b); err == nil {
+			return b
+		}
+	}
+	return ""
+}
+
+// SwitchDetached checks out rev without a branch, stashing local changes
+// first when stash is set.
+func SwitchDetached(path, rev string, stash bool) error {
+	if stash {
+		if err := StashPush(path); err != nil {
+			return err
+		}
+	}
+	return gitRun(path, "checkout", "--detach", rev)
+}
+
+// HeadSHA returns the full sha of HEAD.
+func HeadSHA(path string) (string, error) {
+	out, err := gitOutput(path, "rev-parse", "--verify", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("no commit checked out")
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// CommitsBetween counts the commits only reachable from from and only
+// reachable from to.
+func CommitsBetween(path, from, to string) (onlyFrom, onlyTo int, err error) {
+	out, err := gitOutput(path, "rev-list", "--left-right", "--count", from+"..."+to)
+	if err != nil {
+		return 0, 0, fmt.Errorf("cannot compare %s and %s", from, to)
+	}
+	if _, err := fmt.Sscan(out, &onlyFrom, &onlyTo); err != nil {
+		return 0, 0, err
+	}
+	return onlyFrom, onlyTo, nil
+}
+
+// FirstChangedLine returns the 1-based line of the first change in a file:
+// the first hunk of its diff, or the first conflict marker. Untracked and
+// unknown files start at line 1.
+func FirstChangedLine(path string, file ChangedFile) int {
+	switch file.Status {
+	case StatusConflict:
+		return firstConflictMarker(filepath.Join(path, file.Path))
+	case StatusUntracked:
+		return 1
+	}
+	args := []string{"diff", "-U0", "--no-color"}
+	if file.Status == StatusStaged {
+		args = append(args, "--cached")
+	}
+	args = append(args, "--", file.Path)
+	out, err := gitOutput(path, args...)
+	if err != nil {
+		return 1
+	}
+	return firstHunkLine(out)
+}
+
+// firstHunkLine parses the new-file start from the first "@@ -a,b +c,d @@".
+func firstHunkLine(diff string) int {
+	for _, line := range strings.Split(diff, "\n") {
+		if !strings.HasPrefix(line, "@@ ") {
+			continue
+		}
+		for _, field := range strings.Fields(line) {
+			if !strings.HasPrefix(field, "+") {
+				continue
+			}
+			start, _, _ := strings.Cut(strings.TrimPrefix(field, "+"), ",")
+			if n, err := strconv.Atoi(start); err == nil && n > 0 {
+				return n
+			}
+			return 1
+		}
+	}
+	return 1
+}
+
+func firstConflictMarker(file string) int {
+	f, err := os.Open(file)
+	if err != nil {
+		return 1
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		if strings.HasPrefix(scanner.Text(), "<<<<<<<") {
+			return n
+		}
+	}
+	return 1
 }
 
+// parsePorcelain reads `git status --porcelain=v1 -z`: NUL-terminated
+// entries with paths as-is (no C quoting), where a rename or copy is
+// followed by its original path. ChangedFile.Path is the new path, so it
+// can be passed straight back to git.
 func parsePorcelain(repo *Repo, out string) {
-	scanner := bufio.NewScanner(strings.NewReader(out))
-	for scanner.Scan() {
-		line := scanner.Text()
-		if len(line) < 3 {
+	entries := strings.Split(out, "\x00")
+	for i := 0; i < len(entries); i++ {
+		line := entries[i]
+		if len(line) < 4 {
 			continue
 		}
 		code := line[:2]
-		path := strings.TrimSpace(line[2:])
-		cf := ChangedFile{Path: path}
+		if code[0] == 'R' || code[0] == 'C' || code[1] == 'R' || code[1] == 'C' {
+			i++
+		}
+		cf := ChangedFile{Path: line[3:]}
 
 		if code == "??" {
 			repo.Untracked++
@@ -277,6 +711,40 @@ func gitOutput(path string, args ...string) (string, error) {
 	return out.String(), err
 }
 
+// gitRun runs a git command and folds stderr into the returned error,
+// so callers see git's own message instead of a bare exit status.
+func gitRun(path string, args ...string) error {
+	cmd := gitCmd(path, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := errorLine(stderr.String()); msg != "" {
+			return errors.New(msg)
+		}
+		return err
+	}
+	return nil
+}
+
+// errorLine picks the most useful line of git stderr: the first
+// fatal/error line if any, otherwise the last non-empty line.
+func errorLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "fatal:") || strings.HasPrefix(line, "error:") {
+			return line
+		}
+	}
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := strings.TrimSpace(lines[i])
+		if line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
 func gitCmd(path string, args ...string) *exec.Cmd {
 	cmd := exec.Command("git", args...)
 	cmd.Dir = path
@@ -3,6 +3,7 @@ package git
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -17,6 +18,9 @@ func TestGetRepoStatusClean(t *testing.T) {
 	if status.Branch == "" {
 		t.Fatal("expected branch name")
 	}
+	if status.LastCommit.IsZero() || status.LastModified.IsZero() {
+		t.Fatalf("expected commit and modified times, got %v / %v", status.LastCommit, status.LastModified)
+	}
 }
 
 func TestGetRepoStatusDirtyAndStaged(t *testing.T) {
@@ -39,6 +43,19 @@ func TestGetRepoStatusDirtyAndStaged(t *testing.T) {
 	}
 }
 
+func TestScanReposSetsRootAndDedupes(t *testing.T) {
+	dir := t.TempDir()
+	repo := createRepo(t, dir, "one")
+
+	repos := ScanRepos([]string{dir, repo}, 1)
+	if len(repos) != 1 {
+		t.Fatalf("expected 1 repo, got %d", len(repos))
+	}
+	if repos[0].Root != dir {
+		t.Fatalf("expected root %q, got %q", dir, repos[0].Root)
+	}
+}
+
 func TestGetGraph(t *testing.T) {
 	dir := t.TempDir()
 	repo := createRepo(t, dir, "graph")
@@ -56,6 +73,270 @@ func TestGetGraph(t *testing.T) {
 	}
 }
 
+func TestPushSetUpstream(t *testing.T) {
+	dir := t.TempDir()
+	remote := filepath.Join(dir, "remote.git")
+	runGit(t, dir, "init", "--bare", remote)
+	repo := createRepo(t, dir, "local")
+	runGit(t, repo, "remote", "add", "origin", remote)
+	runGit(t, repo, "checkout", "-b", "feat")
+
+	status := GetRepoStatus(repo)
+	if status.Upstream != "" {
+		t.Fatalf("expected no upstream, got %q", status.Upstream)
+	}
+	if err := Push(repo); err == nil || err.Error() == "exit status 128" {
+		t.Fatalf("expected git message for missing upstream, got %v", err)
+	}
+
+	remotes, err := ListRemotes(repo)
+	if err != nil || len(remotes) != 1 || remotes[0] != "origin" {
+		t.Fatalf("ListRemotes() = %v, %v", remotes, err)
+	}
+	if err := PushSetUpstream(repo, "origin", "feat"); err != nil {
+		t.Fatalf("PushSetUpstream: %v", err)
+	}
+	status = GetRepoStatus(repo)
+	if status.Upstream != "origin/feat" {
+		t.Fatalf("expected upstream origin/feat, got %q", status.Upstream)
+	}
+}
+
+func TestPullReportsIncoming(t *testing.T) {
+	dir := t.TempDir()
+	remote := filepath.Join(dir, "remote.git")
+	runGit(t, dir, "init", "--bare", remote)
+	upstream := createRepo(t, dir, "upstream")
+	runGit(t, upstream, "remote", "add", "origin", remote)
+	runGit(t, upstream, "push", "-u", "origin", "HEAD")
+	runGit(t, dir, "clone", remote, "local")
+	local := filepath.Join(dir, "local")
+
+	writeFile(t, filepath.Join(upstream, "b.txt"), "b")
+	runGit(t, upstream, "add", "b.txt")
+	runGit(t, upstream, "commit", "-m", "b")
+	runGit(t, upstream, "push")
+
+	// dirty worktree only pulls with autostash
+	writeFile(t, filepath.Join(local, "a.txt"), "local edit")
+	result, err := Pull(local, PullOptions{Strategy: "rebase", Autostash: true})
+	if err != nil {
+		t.Fatalf("Pull: %v", err)
+	}
+	if result.Commits != 1 || len(result.Files) != 1 || result.Files[0] != "b.txt" {
+		t.Fatalf("unexpected pull result: %#v", result)
+	}
+	if status := GetRepoStatus(local); status.Modified != 1 {
+		t.Fatalf("expected local edit restored, got M=%d", status.Modified)
+	}
+
+	if _, err := Pull(local, PullOptions{Strategy: "sideways"}); err == nil {
+		t.Fatal("expected error for unknown strategy")
+	}
+}
+
+func TestPullCountsOnlyIncomingCommits(t *testing.T) {
+	for _, strategy := range []string{"rebase", "merge"} {
+		t.Run(strategy, func(t *testing.T) {
+			dir := t.TempDir()
+			remote := filepath.Join(dir, "remote.git")
+			runGit(t, dir, "init", "--bare", remote)
+			upstream := createRepo(t, dir, "upstream")
+			runGit(t, upstream, "remote", "add", "origin", remote)
+			runGit(t, upstream, "push", "-u", "origin", "HEAD")
+			runGit(t, dir, "clone", remote, "local")
+			local := filepath.Join(dir, "local")
+
+			for _, name := range []string{"b.txt", "c.txt"} {
+				writeFile(t, filepath.Join(upstream, name), name)
+				runGit(t, upstream, "add", name)
+				runGit(t, upstream, "commit", "-m", name)
+			}
+			runGit(t, upstream, "push")
+			writeFile(t, filepath.Join(local, "d.txt"), "d")
+			runGit(t, local, "add", "d.txt")
+			runGit(t, local, "commit", "-m", "d")
+
+			result, err := Pull(local, PullOptions{Strategy: strategy})
+			if err != nil {
+				t.Fatalf("Pull: %v", err)
+			}
+			if result.Commits != 2 || len(result.Files) != 2 {
+				t.Fatalf("expected 2 incoming commits and files, got %#v", result)
+			}
+		})
+	}
+}
+
+func TestRewroteUpstream(t *testing.T) {
+	dir := t.TempDir()
+	remote := filepath.Join(dir, "remote.git")
+	runGit(t, dir, "init", "--bare", remote)
+	upstream := createRepo(t, dir, "upstream")
+	runGit(t, upstream, "remote", "add", "origin", remote)
+	runGit(t, upstream, "push", "-u", "origin", "HEAD")
+	runGit(t, dir, "clone", remote, "local")
+	local := filepath.Join(dir, "local")
+	branch := GetRepoStatus(local).Branch
+
+	// a teammate's push, fetched: diverged but nothing was rewritten
+	writeFile(t, filepath.Join(upstream, "b.txt"), "b")
+	runGit(t, upstream, "add", "b.txt")
+	runGit(t, upstream, "commit", "-m", "b")
+	runGit(t, upstream, "push")
+	writeFile(t, filepath.Join(local, "c.txt"), "c")
+	runGit(t, local, "add", "c.txt")
+	runGit(t, local, "commit", "-m", "c")
+	runGit(t, local, "fetch")
+	if status := GetRepoStatus(local); !status.HasDiverged() || RewroteUpstream(local, branch) {
+		t.Fatalf("expected a fetched teammate push not to count as a rewrite (diverged=%v)", status.HasDiverged())
+	}
+
+	// pulled, pushed, then amended: the upstream tip was in the branch
+	runGit(t, local, "pull", "--rebase")
+	runGit(t, local, "push")
+	runGit(t, local, "commit", "--amend", "-m", "c2")
+	if status := GetRepoStatus(local); !status.HasDiverged() || !RewroteUpstream(local, branch) {
+		t.Fatalf("expected an amend of pushed work to count as a rewrite (diverged=%v)", status.HasDiverged())
+	}
+}
+
+func TestMergeConflictResolveAndContinue(t *testing.T) {
+	dir := t.TempDir()
+	repo := createConflict(t, dir, "merge")
+
+	status := GetRepoStatus(repo)
+	if status.State != StateMerging {
+		t.Fatalf("expected StateMerging, got %v", status.State)
+	}
+	files := status.ConflictFiles()
+	if len(files) != 1 || files[0].Path != "a.txt" {
+		t.Fatalf("unexpected conflict files: %#v", files)
+	}
+
+	if err := TakeTheirs(repo, "a.txt"); err != nil {
+		t.Fatalf("TakeTheirs: %v", err)
+	}
+	if status := GetRepoStatus(repo); status.HasConflict {
+		t.Fatal("expected conflict resolved")
+	}
+	if err := ContinueOperation(repo, StateMerging); err != nil {
+		t.Fatalf("ContinueOperation: %v", err)
+	}
+	if status := GetRepoStatus(repo); status.State != StateNone {
+		t.Fatalf("expected StateNone after continue, got %v", status.State)
+	}
+}
+
+func TestMergeConflictAbort(t *testing.T) {
+	dir := t.TempDir()
+	repo := createConflict(t, dir, "abort")
+
+	if err := AbortOperation(repo, StateMerging); err != nil {
+		t.Fatalf("AbortOperation: %v", err)
+	}
+	status := GetRepoStatus(repo)
+	if status.State != StateNone || status.HasConflict {
+		t.Fatalf("expected clean state after abort, got state=%v conflict=%v", status.State, status.HasConflict)
+	}
+}
+
+func TestUnstageAndRestoreIndexEntry(t *testing.T) {
+	dir := t.TempDir()
+	repo := createRepo(t, dir, "unstage")
+	writeFile(t, filepath.Join(repo, "a.txt"), "staged")
+	runGit(t, repo, "add", "a.txt")
+	writeFile(t, filepath.Join(repo, "a.txt"), "staged plus worktree")
+
+	entry, ok, err := ReadIndexEntry(repo, "a.txt")
+	if err != nil || !ok {
+		t.Fatalf("ReadIndexEntry: ok=%v err=%v", ok, err)
+	}
+	if err := Unstage(repo, "a.txt"); err != nil {
+		t.Fatalf("Unstage: %v", err)
+	}
+	if status := GetRepoStatus(repo); status.Staged != 0 {
+		t.Fatalf("expected nothing staged, got %d", status.Staged)
+	}
+	if err := WriteIndexEntry(repo, "a.txt", entry); err != nil {
+		t.Fatalf("WriteIndexEntry: %v", err)
+	}
+	status := GetRepoStatus(repo)
+	if status.Staged != 1 || status.Modified != 1 {
+		t.Fatalf("expected partial staging restored, got S=%d M=%d", status.Staged, status.Modified)
+	}
+}
+
+func TestRestoreWorktreeAndRemoveUntracked(t *testing.T) {
+	dir := t.TempDir()
+	repo := createRepo(t, dir, "discard")
+	writeFile(t, filepath.Join(repo, "a.txt"), "changed")
+	writeFile(t, filepath.Join(repo, "new.txt"), "new")
+
+	if err := RestoreWorktree(repo, "a.txt"); err != nil {
+		t.Fatalf("RestoreWorktree: %v", err)
+	}
+	if err := RemoveUntracked(repo, "new.txt"); err != nil {
+		t.Fatalf("RemoveUntracked: %v", err)
+	}
+	if status := GetRepoStatus(repo); status.IsDirty() {
+		t.Fatalf("expected clean repo, got %#v", status.ChangedFiles)
+	}
+}
+
+func TestStatusPathsWorkWithDiscard(t *testing.T) {
+	dir := t.TempDir()
+	repo := createRepo(t, dir, "paths")
+	writeFile(t, filepath.Join(repo, "caf\u00e9.txt"), "v1")
+	runGit(t, repo, "add", ".")
+	runGit(t, repo, "commit", "-m", "accent")
+	runGit(t, repo, "mv", "a.txt", "renamed a.txt")
+	writeFile(t, filepath.Join(repo, "caf\u00e9.txt"), "v2")
+	writeFile(t, filepath.Join(repo, "new file.txt"), "new")
+
+	status := GetRepoStatus(repo)
+	got := map[string]FileStatus{}
+	for _, f := range status.ChangedFiles {
+		got[f.Path] = f.Status
+	}
+	want := map[string]FileStatus{"renamed a.txt": StatusStaged, "caf\u00e9.txt": StatusModified, "new file.txt": StatusUntracked}
+	if len(got) != len(want) {
+		t.Fatalf("changed files = %v, want %v", got, want)
+	}
+	for path, st := range want {
+		if got[path] != st {
+			t.Fatalf("changed files = %v, want %v", got, want)
+		}
+	}
+
+	if err := RestoreWorktree(repo, "caf\u00e9.txt"); err != nil {
+		t.Fatalf("RestoreWorktree: %v", err)
+	}
+	if err := RemoveUntracked(repo, "new file.txt"); err != nil {
+		t.Fatalf("RemoveUntracked: %v", err)
+	}
+	if err := Unstage(repo, "renamed a.txt"); err != nil {
+		t.Fatalf("Unstage: %v", err)
+	}
+	if status := GetRepoStatus(repo); status.Modified != 0 || status.Untracked != 1 {
+		t.Fatalf("expected the renamed file unstaged and the rest restored, got %#v", status.ChangedFiles)
+	}
+}
+
+// createConflict leaves a repo mid-merge with a.txt conflicted.
+func createConflict(t *testing.T, root, name string) string {
+	repo := createRepo(t, root, name)
+	runGit(t, repo, "checkout", "-b", "other")
+	writeFile(t, filepath.Join(repo, "a.txt"), "theirs")
+	runGit(t, repo, "commit", "-am", "theirs")
+	runGit(t, repo, "checkout", "-")
+	writeFile(t, filepath.Join(repo, "a.txt"), "ours")
+	runGit(t, repo, "commit", "-am", "ours")
+	cmd := gitCmd(repo, "merge", "other")
+	_ = cmd.Run()
+	return repo
+}
+
 func createRepo(t *testing.T, root, name string) string {
 	repo := filepath.Join(root, name)
 	if err := os.MkdirAll(repo, 0o755); err != nil {
@@ -80,3 +361,139 @@ func runGit(t *testing.T, dir string, args ...string) {
 		t.Fatalf("git %v failed: %v", args, err)
 	}
 }
+
+func TestCloneAndRemoteURL(t *testing.T) {
+	dir := t.TempDir()
+	src := createRepo(t, dir, "src")
+	runGit(t, src, "branch", "release")
+
+	dst := filepath.Join(dir, "nested", "dst")
+	if err := Clone(src, dst, "release"); err != nil {
+		t.Fatalf("Clone: %v", err)
+	}
+	if branch := GetRepoStatus(dst).Branch; branch != "release" {
+		t.Fatalf("expected release checked out, got %q", branch)
+	}
+	if url, err := RemoteURL(dst, "origin"); err != nil || url != src {
+		t.Fatalf("RemoteURL = %q, %v", url, err)
+	}
+	if _, err := RemoteURL(dst, "upstream"); err == nil {
+		t.Fatal("expected error for a missing remote")
+	}
+	if err := Clone(src, dst, ""); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("expected git's message when the path exists, got %v", err)
+	}
+}
+
+func TestHeadSHAAndCommitsBetween(t *testing.T) {
+	repo := createRepo(t, t.TempDir(), "repo")
+	base, err := HeadSHA(repo)
+	if err != nil || len(base) != 40 {
+		t.Fatalf("HeadSHA = %q, %v", base, err)
+	}
+	runGit(t, repo, "commit", "--allow-empty", "-m", "one")
+	runGit(t, repo, "commit", "--allow-empty", "-m", "two")
+	head, _ := HeadSHA(repo)
+
+	onlyFrom, onlyTo, err := CommitsBetween(repo, base, head)
+	if err != nil || onlyFrom != 0 || onlyTo != 2 {
+		t.Fatalf("CommitsBetween = %d, %d, %v", onlyFrom, onlyTo, err)
+	}
+	if _, _, err := CommitsBetween(repo, base, "nope"); err == nil {
+		t.Fatal("expected an error for an unknown revision")
+	}
+
+	if err := SwitchDetached(repo, base, false); err != nil {
+		t.Fatalf("SwitchDetached: %v", err)
+	}
+	if status := GetRepoStatus(repo); !status.IsDetached() {
+		t.Fatalf("expected detached HEAD, got %q", status.Branch)
+	}
+}
+
+func TestDefaultBranchAndCreateBranch(t *testing.T) {
+	dir := t.TempDir()
+	src := createRepo(t, dir, "src")
+	runGit(t, src, "branch", "-M", "main")
+	if got := DefaultBranch(src); got != "main" {
+		t.Fatalf("DefaultBranch without remote = %q, want main", got)
+	}
+	dst := filepath.Join(dir, "dst")
+	if err := Clone(src, dst, ""); err != nil {
+		t.Fatalf("Clone: %v", err)
+	}
+	if got := DefaultBranch(dst); got != "origin/main" {
+		t.Fatalf("DefaultBranch = %q, want origin/main", got)
+	}
+
+	writeFile(t, filepath.Join(dst, "a.txt"), "dirty")
+	if err := CreateBranch(dst, "feature", "origin/main", true); err != nil {
+		t.Fatalf("CreateBranch: %v", err)
+	}
+	status := GetRepoStatus(dst)
+	if status.Branch != "feature" || status.Upstream != "" || status.IsDirty() {
+		t.Fatalf("expected clean untracked feature, got %q upstream %q dirty %v", status.Branch, status.Upstream, status.IsDirty())
+	}
+	if err := CreateBranch(dst, "feature", "origin/main", false); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("expected git's message for an existing branch, got %v", err)
+	}
+}
+
+func TestDivergenceFromDefaultBranch(t *testing.T) {
+	dir := t.TempDir()
+	src := createRepo(t, dir, "src")
+	runGit(t, src, "branch", "-M", "main")
+	dst := filepath.Join(dir, "dst")
+	if err := Clone(src, dst, ""); err != nil {
+		t.Fatalf("Clone: %v", err)
+	}
+	if r := GetRepoStatus(dst); r.DefaultBranch != "origin/main" || !r.OnDefaultBranch() || r.IsMerged() {
+		t.Fatalf("on main: default %q on=%v merged=%v", r.DefaultBranch, r.OnDefaultBranch(), r.IsMerged())
+	}
+
+	runGit(t, dst, "checkout", "-q", "-b", "done")
+	if r := GetRepoStatus(dst); r.IsMerged() || r.DefaultAhead != 0 || r.DefaultBehind != 0 {
+		t.Fatalf("a new branch at the default tip is not merged, got %+v", r)
+	}
+	writeFile(t, filepath.Join(dst, "b.txt"), "b")
+	runGit(t, dst, "add", ".")
+	runGit(t, dst, "commit", "-m", "feature")
+	writeFile(t, filepath.Join(src, "c.txt"), "c")
+	runGit(t, src, "add", ".")
+	runGit(t, src, "commit", "-m", "upstream 1")
+	writeFile(t, filepath.Join(src, "c.txt"), "cc")
+	runGit(t, src, "commit", "-am", "upstream 2")
+	if err := FetchAll(dst); err != nil {
+		t.Fatalf("FetchAll: %v", err)
+	}
+	r := GetRepoStatus(dst)
+	if r.DefaultAhead != 1 || r.DefaultBehind != 2 || r.IsMerged() {
+		t.Fatalf("expected +1 -2 against origin/main, got +%d -%d merged=%v", r.DefaultAhead, r.DefaultBehind, r.IsMerged())
+	}
+
+	r.CompareDefault("main")
+	if r.DefaultBranch != "main" || r.DefaultAhead != 1 || r.DefaultBehind != 0 {
+		t.Fatalf("against local main: %q +%d -%d", r.DefaultBranch, r.DefaultAhead, r.DefaultBehind)
+	}
+	if r := GetRepoStatusAgainst(dst, "main"); r.DefaultBranch != "main" || r.DefaultAhead != 1 || r.DefaultBehind != 0 {
+		t.Fatalf("a configured ref replaces detection: %q +%d -%d", r.DefaultBranch, r.DefaultAhead, r.DefaultBehind)
+	}
+
+	runGit(t, src, "fetch", "-q", dst, "done")
+	runGit(t, src, "merge", "-q", "--no-edit", "FETCH_HEAD")
+	if err := FetchAll(dst); err != nil {
+		t.Fatalf("FetchAll: %v", err)
+	}
+	if r := GetRepoStatus(dst); !r.IsMerged() || r.DefaultBehind != 3 {
+		t.Fatalf("expected done to count as merged once it landed, got +%d -%d", r.DefaultAhead, r.DefaultBehind)
+	}
+	writeFile(t, filepath.Join(dst, "b.txt"), "work in progress")
+	if r := GetRepoStatus(dst); r.IsMerged() {
+		t.Fatal("a dirty branch is never merged")
+	}
+
+	r.CompareDefault("nope")
+	if r.DefaultBranch != "" || r.IsMerged() {
+		t.Fatalf("an unknown ref should clear the comparison, got %q", r.DefaultBranch)
+	}
+}
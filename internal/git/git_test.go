@@ -4,11 +4,11 @@ import "testing"
 
 func TestParsePorcelain(t *testing.T) {
 	repo := Repo{}
-	out := " M modified.txt\n" +
-		"M  staged.txt\n" +
-		"A  added.txt\n" +
-		"?? untracked.txt\n" +
-		"UU conflict.txt\n"
+	out := " M modified.txt\x00" +
+		"M  staged.txt\x00" +
+		"A  added.txt\x00" +
+		"?? untracked.txt\x00" +
+		"UU conflict.txt\x00"
 
 	parsePorcelain(&repo, out)
 
@@ -31,10 +31,52 @@ func TestParsePorcelain(t *testing.T) {
 
 func TestParsePorcelainBothStages(t *testing.T) {
 	repo := Repo{}
-	out := "MM both.txt\n"
+	out := "MM both.txt\x00"
 	parsePorcelain(&repo, out)
 
 	if repo.Staged != 1 || repo.Modified != 1 {
 		t.Fatalf("Staged=%d Modified=%d, want 1/1", repo.Staged, repo.Modified)
 	}
 }
+
+func TestParsePorcelainRenamesAndRawPaths(t *testing.T) {
+	repo := Repo{}
+	out := "R  new name.txt\x00old name.txt\x00" +
+		" M caf\u00e9.txt\x00" +
+		"?? dir/with space\x00"
+	parsePorcelain(&repo, out)
+
+	var paths []string
+	for _, f := range repo.ChangedFiles {
+		paths = append(paths, f.Path)
+	}
+	if len(paths) != 3 || paths[0] != "new name.txt" || paths[1] != "caf\u00e9.txt" || paths[2] != "dir/with space" {
+		t.Fatalf("paths = %q", paths)
+	}
+	if repo.Staged != 1 || repo.Modified != 1 || repo.Untracked != 1 {
+		t.Fatalf("Staged=%d Modified=%d Untracked=%d, want 1/1/1", repo.Staged, repo.Modified, repo.Untracked)
+	}
+}
+
+func TestErrorLinePrefersFatal(t *testing.T) {
+	stderr := "To push the current branch, use\n\nfatal: The current branch feat has no upstream branch.\nhint: more\n"
+	if got := errorLine(stderr); got != "fatal: The current branch feat has no upstream branch." {
+		t.Fatalf("errorLine() = %q", got)
+	}
+	if got := errorLine("first\nlast\n\n"); got != "last" {
+		t.Fatalf("errorLine() = %q, want last", got)
+	}
+}
+
+func TestFirstHunkLine(t *testing.T) {
+	diff := "diff --git a/a b/a\n--- a/a\n+++ b/a\n@@ -10,2 +12,3 @@ func x\n+new\n@@ -40 +50 @@\n"
+	if got := firstHunkLine(diff); got != 12 {
+		t.Fatalf("firstHunkLine() = %d, want 12", got)
+	}
+	if got := firstHunkLine("@@ -3 +0,0 @@\n"); got != 1 {
+		t.Fatalf("expected line 1 for deletion at top, got %d", got)
+	}
+	if got := firstHunkLine(""); got != 1 {
+		t.Fatalf("expected 1 for empty diff, got %d", got)
+	}
+}
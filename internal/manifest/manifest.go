@@ -0,0 +1,285 @@
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/BurntSushi/toml"
+
+	"rtui/internal/git"
+)
+
+// Manifest lists the repos a workspace is expected to have. In TOML:
+//
+//	root = "~/src/acme"   # optional; defaults to the manifest's directory
+//
+//	[[repos]]
+//	url = "git@github.com:acme/api.git"
+//	path = "api"          # optional; relative to root, defaults to the URL's last segment
+//	branch = "main"       # optional; defaults to the remote's HEAD
+type Manifest struct {
+	Root  string  `toml:"root"`
+	Repos []Entry `toml:"repos"`
+}
+
+// Entry is one repo of a manifest. Path is absolute after Load.
+type Entry struct {
+	URL    string `toml:"url"`
+	Path   string `toml:"path"`
+	Branch string `toml:"branch"`
+}
+
+// Name is the entry's directory name, used as the repo name.
+func (e Entry) Name() string {
+	return filepath.Base(e.Path)
+}
+
+// Load reads a manifest and resolves every path against its root.
+func Load(path string) (Manifest, error) {
+	var m Manifest
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return m, err
+	}
+	if _, err := toml.Decode(string(data), &m); err != nil {
+		return m, fmt.Errorf("%s: %w", path, err)
+	}
+	dir, err := filepath.Abs(filepath.Dir(path))
+	if err != nil {
+		return m, err
+	}
+	m.Root = resolve(dir, m.Root)
+	seen := map[string]bool{}
+	for i, e := range m.Repos {
+		if e.URL == "" {
+			return m, fmt.Errorf("%s: repos[%d] has no url", path, i)
+		}
+		if e.Path == "" {
+			e.Path = urlName(e.URL)
+		}
+		e.Path = resolve(m.Root, e.Path)
+		if seen[e.Path] {
+			return m, fmt.Errorf("%s: %s is listed twice", path, e.Path)
+		}
+		seen[e.Path] = true
+		m.Repos[i] = e
+	}
+	return m, nil
+}
+
+// resolve expands ~ and makes p relative to dir.
+func resolve(dir, p string) string {
+	if p == "" {
+		return filepath.Clean(dir)
+	}
+	if p[0] == '~' {
+		home, _ := os.UserHomeDir()
+		p = filepath.Join(home, p[1:])
+	}
+	if !filepath.IsAbs(p) {
+		p = filepath.Join(dir, p)
+	}
+	return filepath.Clean(p)
+}
+
+// urlName is the last path segment of a clone URL without ".git", e.g.
+// "api" for "git@github.com:acme/api.git".
+func urlName(url string) string {
+	url = strings.TrimSuffix(strings.TrimRight(url, "/"), ".git")
+	if i := strings.LastIndexAny(url, "/:"); i >= 0 {
+		url = url[i+1:]
+	}
+	return url
+}
+
+// Depth is the scan depth under Root that reaches every entry.
+func (m Manifest) Depth() int {
+	depth := 0
+	for _, e := range m.Repos {
+		if rel, err := filepath.Rel(m.Root, e.Path); err == nil && !escapes(rel) {
+			depth = max(depth, strings.Count(rel, string(filepath.Separator)))
+		}
+	}
+	return depth
+}
+
+// Scan scans paths plus the manifest root, deep enough under the root to
+// reach every entry, so Compare sees the repos it lists. defaultFor is
+// passed to git.ScanReposDepths.
+func (m Manifest) Scan(paths []string, depthFor func(root string) int, defaultFor func(path string) string) []git.Repo {
+	root := filepath.Clean(m.Root)
+	paths = append(slices.Clone(paths), root)
+	return git.ScanReposDepths(paths, func(p string) int {
+		if filepath.Clean(p) == root {
+			return max(depthFor(p), m.Depth())
+		}
+		return depthFor(p)
+	}, defaultFor)
+}
+
+// Uncloned returns the entries whose path does not exist yet. It only
+// stats, so it is cheap enough to run on every list refresh.
+func (m Manifest) Uncloned() []Entry {
+	var out []Entry
+	for _, e := range m.Repos {
+		if _, err := os.Stat(e.Path); errors.Is(err, os.ErrNotExist) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// State is how a manifest entry, or a repo found under the root,
+// compares with the disk.
+type State int
+
+const (
+	// StateOK is cloned with the manifest's URL as origin.
+	StateOK State = iota
+	// StateMissing does not exist yet and can be cloned.
+	StateMissing
+	// StateMismatch exists but is not a git repo or has another origin.
+	StateMismatch
+	// StateExtra is a repo under the root the manifest does not list.
+	StateExtra
+)
+
+func (s State) String() string {
+	switch s {
+	case StateMissing:
+		return "missing"
+	case StateMismatch:
+		return "mismatch"
+	case StateExtra:
+		return "extra"
+	default:
+		return "ok"
+	}
+}
+
+// Status is the comparison result for one path.
+type Status struct {
+	Entry  Entry
+	State  State
+	Detail string
+}
+
+// Compare checks every entry against the repos a scan found, then lists
+// found repos under the root that the manifest does not mention. Entries
+// the scan did not reach are checked on disk directly.
+func Compare(m Manifest, found []git.Repo) []Status {
+	byPath := map[string]bool{}
+	for _, r := range found {
+		byPath[filepath.Clean(r.Path)] = true
+	}
+	listed := map[string]bool{}
+	var out []Status
+	for _, e := range m.Repos {
+		listed[e.Path] = true
+		out = append(out, compareEntry(e, byPath[e.Path]))
+	}
+	for _, r := range found {
+		path := filepath.Clean(r.Path)
+		if listed[path] || !within(m.Root, path) {
+			continue
+		}
+		out = append(out, Status{Entry: Entry{Path: path}, State: StateExtra, Detail: "not in the manifest"})
+	}
+	return out
+}
+
+func compareEntry(e Entry, scanned bool) Status {
+	st := Status{Entry: e}
+	if !scanned {
+		info, err := os.Stat(e.Path)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			st.State = StateMissing
+			return st
+		case err != nil:
+			st.State, st.Detail = StateMismatch, err.Error()
+			return st
+		case !info.IsDir():
+			st.State, st.Detail = StateMismatch, "exists and is not a directory"
+			return st
+		}
+		if _, err := os.Stat(filepath.Join(e.Path, ".git")); err != nil {
+			st.State, st.Detail = StateMismatch, "exists and is not a git repo"
+			return st
+		}
+	}
+	url, err := git.RemoteURL(e.Path, "origin")
+	if err != nil {
+		st.State, st.Detail = StateMismatch, "has no origin remote"
+		return st
+	}
+	if !SameURL(url, e.URL) {
+		st.State, st.Detail = StateMismatch, "origin is "+url
+	}
+	return st
+}
+
+// SameURL compares clone URLs, ignoring a trailing slash or ".git".
+func SameURL(a, b string) bool {
+	norm := func(u string) string {
+		return strings.TrimSuffix(strings.TrimRight(u, "/"), ".git")
+	}
+	return norm(a) == norm(b)
+}
+
+func within(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	return err == nil && rel != "." && !escapes(rel)
+}
+
+// escapes reports whether a filepath.Rel result leaves its base: a child
+// named "..foo" does not.
+func escapes(rel string) bool {
+	return rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// Missing returns the entries that are not cloned yet.
+func Missing(statuses []Status) []Entry {
+	var out []Entry
+	for _, st := range statuses {
+		if st.State == StateMissing {
+			out = append(out, st.Entry)
+		}
+	}
+	return out
+}
+
+// CloneResult is the outcome of cloning one entry.
+type CloneResult struct {
+	Entry Entry
+	Err   error
+}
+
+// CloneAll clones entries with at most jobs clones running at once. done
+// is called from the cloning goroutines as each clone finishes; results
+// come back in entry order.
+func CloneAll(entries []Entry, jobs int, done func(CloneResult)) []CloneResult {
+	jobs = max(jobs, 1)
+	results := make([]CloneResult, len(entries))
+	sem := make(chan struct{}, jobs)
+	var wg sync.WaitGroup
+	for i, e := range entries {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = CloneResult{Entry: e, Err: git.Clone(e.URL, e.Path, e.Branch)}
+			if done != nil {
+				done(results[i])
+			}
+		}()
+	}
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,159 @@
+package manifest
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadResolvesPaths(t *testing.T) {
+	dir := t.TempDir()
+	path := writeManifest(t, dir, `
+root = "src"
+
+[[repos]]
+url = "git@github.com:acme/api.git"
+
+[[repos]]
+url = "https://github.com/acme/web/"
+path = "apps/web"
+branch = "develop"
+`)
+	m, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	root := filepath.Join(dir, "src")
+	if m.Root != root {
+		t.Fatalf("root = %q, want %q", m.Root, root)
+	}
+	if got := m.Repos[0].Path; got != filepath.Join(root, "api") {
+		t.Fatalf("default path = %q", got)
+	}
+	if got := m.Repos[1]; got.Path != filepath.Join(root, "apps", "web") || got.Branch != "develop" || got.Name() != "web" {
+		t.Fatalf("second entry = %+v", got)
+	}
+	if m.Depth() != 1 {
+		t.Fatalf("depth = %d, want 1", m.Depth())
+	}
+}
+
+func TestLoadRejectsBadEntries(t *testing.T) {
+	dir := t.TempDir()
+	for name, text := range map[string]string{
+		"no url":    "[[repos]]\npath = \"x\"\n",
+		"duplicate": "[[repos]]\nurl = \"a/x.git\"\n[[repos]]\nurl = \"b/x\"\n",
+	} {
+		if _, err := Load(writeManifest(t, dir, text)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestCompareAndCloneAll(t *testing.T) {
+	dir := t.TempDir()
+	upstream := filepath.Join(dir, "upstream")
+	api := createRepo(t, upstream, "api")
+	web := createRepo(t, upstream, "web")
+	root := filepath.Join(dir, "src")
+
+	// web is cloned from another URL, extra is not listed, api is missing.
+	runGit(t, dir, "clone", "-q", api, filepath.Join(root, "web"))
+	createRepo(t, root, "extra")
+	path := writeManifest(t, dir, `
+root = "src"
+[[repos]]
+url = "`+api+`"
+[[repos]]
+url = "`+web+`"
+`)
+	m, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if un := m.Uncloned(); len(un) != 1 || un[0].Name() != "api" {
+		t.Fatalf("Uncloned = %+v", un)
+	}
+
+	statuses := Compare(m, m.Scan(nil, func(string) int { return 0 }, nil))
+	got := map[string]State{}
+	for _, st := range statuses {
+		got[filepath.Base(st.Entry.Path)] = st.State
+	}
+	want := map[string]State{"api": StateMissing, "web": StateMismatch, "extra": StateExtra}
+	for name, state := range want {
+		if got[name] != state {
+			t.Errorf("%s: %v, want %v", name, got[name], state)
+		}
+	}
+
+	results := CloneAll(Missing(statuses), 2, nil)
+	if len(results) != 1 || results[0].Err != nil {
+		t.Fatalf("CloneAll = %+v", results)
+	}
+	if st := compareEntry(m.Repos[0], false); st.State != StateOK {
+		t.Fatalf("after clone: %v %s", st.State, st.Detail)
+	}
+	if len(m.Uncloned()) != 0 {
+		t.Fatal("expected nothing left to clone")
+	}
+}
+
+func TestSameURL(t *testing.T) {
+	if !SameURL("git@github.com:acme/api.git", "git@github.com:acme/api/") {
+		t.Fatal("expected .git and trailing slash to be ignored")
+	}
+	if SameURL("git@github.com:acme/api", "git@github.com:acme/web") {
+		t.Fatal("expected different repos to differ")
+	}
+}
+
+func TestWithinKeepsDotDotNamedChildren(t *testing.T) {
+	root := filepath.Join("/src", "team")
+	for path, want := range map[string]bool{
+		filepath.Join(root, "..api"):     true,
+		filepath.Join(root, "..", "api"): false,
+		root:                             false,
+		"/src":                           false,
+	} {
+		if got := within(root, path); got != want {
+			t.Errorf("within(%q, %q) = %v, want %v", root, path, got, want)
+		}
+	}
+	m := Manifest{Root: root, Repos: []Entry{{Path: filepath.Join(root, "..x", "api")}}}
+	if m.Depth() != 1 {
+		t.Fatalf("depth = %d, want 1", m.Depth())
+	}
+}
+
+func writeManifest(t *testing.T, dir, text string) string {
+	path := filepath.Join(dir, "manifest.toml")
+	if err := os.WriteFile(path, []byte(strings.TrimLeft(text, "\n")), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	return path
+}
+
+func createRepo(t *testing.T, root, name string) string {
+	repo := filepath.Join(root, name)
+	if err := os.MkdirAll(repo, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	runGit(t, repo, "init", "-q")
+	if err := os.WriteFile(filepath.Join(repo, "a.txt"), []byte("init"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	runGit(t, repo, "add", ".")
+	runGit(t, repo, "commit", "-q", "-m", "init")
+	return repo
+}
+
+func runGit(t *testing.T, dir string, args ...string) {
+	cmd := exec.Command("git", append([]string{"-c", "user.name=rtui", "-c", "user.email=rtui@example.com"}, args...)...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, out)
+	}
+}
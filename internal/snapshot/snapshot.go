@@ -0,0 +1,376 @@
+package snapshot
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+
+	"rtui/internal/git"
+)
+
+// Snapshot records which branch and commit each repo had checked out. On
+// disk it is <dir>/<name>.toml:
+//
+//	created = 2026-10-19T09:30:00Z
+//
+//	[[repos]]
+//	path = "/home/me/src/api"
+//	branch = "release/1.4"   # empty when HEAD was detached
+//	sha = "9f8e7d6..."
+type Snapshot struct {
+	Name    string    `toml:"-"`
+	Created time.Time `toml:"created"`
+	Repos   []Repo    `toml:"repos"`
+}
+
+// Repo is one repo of a snapshot.
+type Repo struct {
+	Path   string `toml:"path"`
+	Branch string `toml:"branch"`
+	SHA    string `toml:"sha"`
+}
+
+// Name is the repo's directory name.
+func (r Repo) Name() string {
+	return filepath.Base(r.Path)
+}
+
+// Ref is the short form used in reports: branch@sha, or @sha when
+// detached.
+func (r Repo) Ref() string {
+	return r.Branch + "@" + shortSHA(r.SHA)
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// Take records the current branch and HEAD of repos. Repos without a
+// commit are left out.
+func Take(name string, repos []git.Repo) Snapshot {
+	s := Snapshot{Name: name, Created: time.Now().UTC().Truncate(time.Second)}
+	for _, r := range repos {
+		sha, err := git.HeadSHA(r.Path)
+		if err != nil {
+			continue
+		}
+		branch := r.Branch
+		if r.IsDetached() {
+			branch = ""
+		}
+		s.Repos = append(s.Repos, Repo{Path: r.Path, Branch: branch, SHA: sha})
+	}
+	sort.Slice(s.Repos, func(i, j int) bool { return s.Repos[i].Path < s.Repos[j].Path })
+	return s
+}
+
+// ValidName reports whether name can be used as a snapshot file name.
+func ValidName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("invalid snapshot name %q", name)
+	}
+	return nil
+}
+
+func file(dir, name string) string {
+	return filepath.Join(dir, name+".toml")
+}
+
+// Save writes s to dir. An existing snapshot of the same name is only
+// replaced when overwrite is set.
+func Save(dir string, s Snapshot, overwrite bool) error {
+	if err := ValidName(s.Name); err != nil {
+		return err
+	}
+	path := file(dir, s.Name)
+	if _, err := os.Stat(path); err == nil && !overwrite {
+		return fmt.Errorf("snapshot %s already exists", s.Name)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(s); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
+
+// Load reads the snapshot name from dir.
+func Load(dir, name string) (Snapshot, error) {
+	s := Snapshot{Name: name}
+	if err := ValidName(name); err != nil {
+		return s, err
+	}
+	data, err := os.ReadFile(file(dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return s, fmt.Errorf("no snapshot %s", name)
+	} else if err != nil {
+		return s, err
+	}
+	if _, err := toml.Decode(string(data), &s); err != nil {
+		return s, fmt.Errorf("snapshot %s: %w", name, err)
+	}
+	return s, nil
+}
+
+// List returns the saved snapshot names, sorted.
+func List(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, e := range entries {
+		if name, ok := strings.CutSuffix(e.Name(), ".toml"); ok && !e.IsDir() && ValidName(name) == nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Outcome is what restore did to one repo.
+type Outcome int
+
+const (
+	// Unchanged was already on the recorded branch.
+	Unchanged Outcome = iota
+	// Switched was checked out.
+	Switched
+	// Stashed had local changes stashed, then was checked out.
+	Stashed
+	// Skipped was left alone: missing, dirty without stashing, or in the
+	// middle of a merge or rebase.
+	Skipped
+	// Failed could not be checked out.
+	Failed
+)
+
+func (o Outcome) String() string {
+	switch o {
+	case Switched:
+		return "switched"
+	case Stashed:
+		return "stashed"
+	case Skipped:
+		return "skipped"
+	case Failed:
+		return "failed"
+	default:
+		return "ok"
+	}
+}
+
+// Result is the restore report line for one repo. Detail explains a skip
+// or failure, or notes that the branch has moved since the snapshot.
+type Result struct {
+	Repo    Repo
+	Outcome Outcome
+	Detail  string
+}
+
+// Options control Restore.
+type Options struct {
+	// NoStash skips dirty repos instead of stashing their changes.
+	NoStash bool
+	// Exact checks out the recorded commits detached, instead of the
+	// recorded branches at whatever they point to now.
+	Exact bool
+}
+
+// Restore checks out every repo of s. Dirty repos are stashed first, as
+// the branch picker does. A branch that only exists on a remote gets a
+// tracking branch. done, if set, is called after each repo.
+func Restore(s Snapshot, opts Options, done func(Result)) []Result {
+	results := make([]Result, 0, len(s.Repos))
+	for _, r := range s.Repos {
+		res := restoreRepo(r, opts)
+		if done != nil {
+			done(res)
+		}
+		results = append(results, res)
+	}
+	return results
+}
+
+func restoreRepo(r Repo, opts Options) Result {
+	res := Result{Repo: r}
+	if _, err := os.Stat(filepath.Join(r.Path, ".git")); err != nil {
+		res.Outcome, res.Detail = Skipped, "not a git repo"
+		return res
+	}
+	cur := git.GetRepoStatus(r.Path)
+	head, _ := git.HeadSHA(r.Path)
+	detached := r.Branch == "" || opts.Exact
+	if (detached && cur.IsDetached() && head == r.SHA) || (!detached && cur.Branch == r.Branch) {
+		res.Outcome = Unchanged
+		res.Detail = moved(r, head)
+		return res
+	}
+	if cur.HasConflict || cur.State != git.StateNone {
+		res.Outcome, res.Detail = Skipped, "merge or rebase in progress"
+		return res
+	}
+	stash := cur.IsDirty()
+	if stash && opts.NoStash {
+		res.Outcome, res.Detail = Skipped, "has local changes"
+		return res
+	}
+
+	branch, remote := "", false
+	if !detached {
+		var err error
+		if branch, remote, err = findBranch(r.Path, r.Branch); err != nil {
+			res.Outcome, res.Detail = Failed, err.Error()
+			return res
+		}
+	}
+	if stash {
+		if err := git.StashPush(r.Path); err != nil {
+			res.Outcome, res.Detail = Failed, "stash: "+err.Error()
+			return res
+		}
+	}
+	var err error
+	if detached {
+		err = git.SwitchDetached(r.Path, r.SHA, false)
+	} else {
+		err = git.SwitchBranch(r.Path, branch, remote, false)
+	}
+	if err != nil {
+		res.Outcome, res.Detail = Failed, err.Error()
+		// The checkout did not happen: put the stashed changes back
+		// where they were, or say where they are.
+		if stash {
+			if err := git.StashPop(r.Path); err != nil {
+				res.Detail += "; local changes stashed (git stash pop to get them back)"
+			} else {
+				res.Detail += "; local changes kept"
+			}
+		}
+		return res
+	}
+	res.Outcome = Switched
+	if stash {
+		res.Outcome = Stashed
+	}
+	head, _ = git.HeadSHA(r.Path)
+	res.Detail = moved(r, head)
+	return res
+}
+
+// findBranch returns branch if it exists locally, else the remote branch
+// to track (origin preferred).
+func findBranch(path, branch string) (string, bool, error) {
+	locals, remotes, _, err := git.ListBranches(path)
+	if err != nil {
+		return "", false, err
+	}
+	if slices.Contains(locals, branch) {
+		return branch, false, nil
+	}
+	if slices.Contains(remotes, "origin/"+branch) {
+		return "origin/" + branch, true, nil
+	}
+	for _, rb := range remotes {
+		if _, name, ok := strings.Cut(rb, "/"); ok && name == branch {
+			return rb, true, nil
+		}
+	}
+	return "", false, fmt.Errorf("branch %s not found", branch)
+}
+
+// moved notes that the checked out commit differs from the snapshot.
+func moved(r Repo, head string) string {
+	if head == "" || head == r.SHA {
+		return ""
+	}
+	return fmt.Sprintf("HEAD is %s, snapshot had %s", shortSHA(head), shortSHA(r.SHA))
+}
+
+// ChangeKind classifies a repo in a snapshot diff.
+type ChangeKind int
+
+const (
+	// Same branch and commit in both.
+	Same ChangeKind = iota
+	// Branch differs.
+	BranchChanged
+	// Same branch, another commit.
+	Moved
+	// Only in the second snapshot.
+	Added
+	// Only in the first snapshot.
+	Removed
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case BranchChanged:
+		return "branch"
+	case Moved:
+		return "moved"
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	default:
+		return "same"
+	}
+}
+
+// Change is one repo of a diff. From or To is the zero Repo when the repo
+// is only in the other snapshot.
+type Change struct {
+	Path     string
+	Kind     ChangeKind
+	From, To Repo
+}
+
+// Diff compares two snapshots repo by repo, in path order.
+func Diff(a, b Snapshot) []Change {
+	from := map[string]Repo{}
+	to := map[string]Repo{}
+	var paths []string
+	for _, r := range a.Repos {
+		from[r.Path] = r
+		paths = append(paths, r.Path)
+	}
+	for _, r := range b.Repos {
+		to[r.Path] = r
+		if _, ok := from[r.Path]; !ok {
+			paths = append(paths, r.Path)
+		}
+	}
+	sort.Strings(paths)
+	changes := make([]Change, 0, len(paths))
+	for _, p := range paths {
+		f, inA := from[p]
+		t, inB := to[p]
+		c := Change{Path: p, From: f, To: t}
+		switch {
+		case !inA:
+			c.Kind = Added
+		case !inB:
+			c.Kind = Removed
+		case f.Branch != t.Branch:
+			c.Kind = BranchChanged
+		case f.SHA != t.SHA:
+			c.Kind = Moved
+		}
+		changes = append(changes, c)
+	}
+	return changes
+}
@@ -0,0 +1,202 @@
+package snapshot
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"rtui/internal/git"
+)
+
+func TestSaveLoadList(t *testing.T) {
+	dir := t.TempDir()
+	s := Snapshot{Name: "rel", Repos: []Repo{{Path: "/src/api", Branch: "main", SHA: "0123456789abcdef"}}}
+	if err := Save(dir, s, false); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if err := Save(dir, s, false); err == nil {
+		t.Fatal("expected an existing snapshot to be kept without overwrite")
+	}
+	got, err := Load(dir, "rel")
+	if err != nil || len(got.Repos) != 1 || got.Repos[0] != s.Repos[0] {
+		t.Fatalf("Load = %+v, %v", got, err)
+	}
+	if got.Repos[0].Ref() != "main@0123456" {
+		t.Fatalf("Ref = %q", got.Repos[0].Ref())
+	}
+	if names := List(dir); len(names) != 1 || names[0] != "rel" {
+		t.Fatalf("List = %v", names)
+	}
+	if _, err := Load(dir, "../rel"); err == nil {
+		t.Fatal("expected a path to be rejected as a name")
+	}
+}
+
+func TestTakeAndRestore(t *testing.T) {
+	dir := t.TempDir()
+	upstream := createRepo(t, dir, "upstream")
+	runGit(t, upstream, "branch", "release")
+	api := filepath.Join(dir, "api")
+	runGit(t, dir, "clone", "-q", upstream, api)
+	runGit(t, api, "checkout", "-q", "-t", "origin/release")
+	web := createRepo(t, dir, "web")
+	runGit(t, web, "checkout", "-q", "-b", "feature")
+	old := createRepo(t, dir, "old")
+	runGit(t, old, "checkout", "-q", "--detach")
+
+	s := Take("rel", scan(api, web, old))
+	if len(s.Repos) != 3 {
+		t.Fatalf("expected 3 repos, got %+v", s.Repos)
+	}
+	byName := map[string]Repo{}
+	for _, r := range s.Repos {
+		byName[r.Name()] = r
+	}
+	if byName["web"].Branch != "feature" || byName["old"].Branch != "" || len(byName["api"].SHA) != 40 {
+		t.Fatalf("unexpected snapshot: %+v", s.Repos)
+	}
+
+	// api loses its local branch, web moves on and gets dirty, old is
+	// checked out on a branch.
+	runGit(t, api, "checkout", "-q", "main")
+	runGit(t, api, "branch", "-q", "-D", "release")
+	runGit(t, web, "checkout", "-q", "-b", "other")
+	writeFile(t, filepath.Join(web, "a.txt"), "dirty")
+	runGit(t, old, "checkout", "-q", "-")
+
+	var reported []Result
+	results := Restore(s, Options{}, func(r Result) { reported = append(reported, r) })
+	if len(reported) != len(results) {
+		t.Fatalf("done called %d times for %d results", len(reported), len(results))
+	}
+	outcomes := map[string]Outcome{}
+	for _, r := range results {
+		outcomes[r.Repo.Name()] = r.Outcome
+		if r.Outcome == Failed || r.Outcome == Skipped {
+			t.Errorf("%s: %v %s", r.Repo.Name(), r.Outcome, r.Detail)
+		}
+	}
+	if outcomes["api"] != Switched || outcomes["web"] != Stashed || outcomes["old"] != Switched {
+		t.Fatalf("outcomes = %v", outcomes)
+	}
+	if b := git.GetRepoStatus(api).Branch; b != "release" {
+		t.Fatalf("api on %q, want release tracked from origin", b)
+	}
+	if st := git.GetRepoStatus(web); st.Branch != "feature" || st.IsDirty() {
+		t.Fatalf("web on %q dirty=%v, want feature with changes stashed", st.Branch, st.IsDirty())
+	}
+	if st := git.GetRepoStatus(old); !st.IsDetached() {
+		t.Fatalf("old on %q, want detached", st.Branch)
+	}
+
+	again := Restore(s, Options{}, nil)
+	for _, r := range again {
+		if r.Outcome != Unchanged {
+			t.Errorf("%s: second restore %v %s", r.Repo.Name(), r.Outcome, r.Detail)
+		}
+	}
+}
+
+func TestRestoreSkipsAndFails(t *testing.T) {
+	dir := t.TempDir()
+	repo := createRepo(t, dir, "api")
+	branch := git.GetRepoStatus(repo).Branch
+	sha, _ := git.HeadSHA(repo)
+	runGit(t, repo, "checkout", "-q", "-b", "other")
+	writeFile(t, filepath.Join(repo, "a.txt"), "dirty")
+
+	s := Snapshot{Name: "rel", Repos: []Repo{
+		{Path: repo, Branch: branch, SHA: sha},
+		{Path: repo, Branch: "gone", SHA: sha},
+		{Path: filepath.Join(dir, "missing"), Branch: "main", SHA: sha},
+	}}
+	results := Restore(s, Options{NoStash: true}, nil)
+	if results[0].Outcome != Skipped || results[2].Outcome != Skipped {
+		t.Fatalf("expected dirty and missing repos to be skipped, got %+v", results)
+	}
+	runGit(t, repo, "checkout", "--", "a.txt")
+	results = Restore(s, Options{}, nil)
+	if results[1].Outcome != Failed || !strings.Contains(results[1].Detail, "gone") {
+		t.Fatalf("expected a missing branch to fail, got %+v", results[1])
+	}
+	if git.GetRepoStatus(repo).Branch != branch {
+		t.Fatal("expected the first entry to be restored")
+	}
+}
+
+func TestRestoreFailureGivesBackStashedChanges(t *testing.T) {
+	dir := t.TempDir()
+	repo := createRepo(t, dir, "api")
+	writeFile(t, filepath.Join(repo, "a.txt"), "dirty")
+
+	s := Snapshot{Name: "rel", Repos: []Repo{{Path: repo, SHA: strings.Repeat("d", 40)}}}
+	res := Restore(s, Options{}, nil)[0]
+	if res.Outcome != Failed || !strings.HasSuffix(res.Detail, "; local changes kept") {
+		t.Fatalf("expected a failed checkout that keeps the changes, got %v %q", res.Outcome, res.Detail)
+	}
+	if !git.GetRepoStatus(repo).IsDirty() {
+		t.Fatal("the stashed changes should be back in the worktree")
+	}
+	if out, _ := exec.Command("git", "-C", repo, "stash", "list").Output(); len(out) != 0 {
+		t.Fatalf("nothing should be left in the stash, got %s", out)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	a := Snapshot{Repos: []Repo{
+		{Path: "/a", Branch: "main", SHA: "1"},
+		{Path: "/b", Branch: "main", SHA: "1"},
+		{Path: "/c", Branch: "main", SHA: "1"},
+		{Path: "/d", Branch: "main", SHA: "1"},
+	}}
+	b := Snapshot{Repos: []Repo{
+		{Path: "/e", Branch: "main", SHA: "1"},
+		{Path: "/a", Branch: "main", SHA: "1"},
+		{Path: "/b", Branch: "release", SHA: "1"},
+		{Path: "/c", Branch: "main", SHA: "2"},
+	}}
+	var got []string
+	for _, c := range Diff(a, b) {
+		got = append(got, c.Path+" "+c.Kind.String())
+	}
+	want := "/a same,/b branch,/c moved,/d removed,/e added"
+	if strings.Join(got, ",") != want {
+		t.Fatalf("Diff = %v, want %s", got, want)
+	}
+}
+
+func scan(paths ...string) []git.Repo {
+	repos := make([]git.Repo, len(paths))
+	for i, p := range paths {
+		repos[i] = git.GetRepoStatus(p)
+	}
+	return repos
+}
+
+func createRepo(t *testing.T, root, name string) string {
+	repo := filepath.Join(root, name)
+	if err := os.MkdirAll(repo, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	runGit(t, repo, "init", "-q", "-b", "main")
+	writeFile(t, filepath.Join(repo, "a.txt"), "init")
+	runGit(t, repo, "add", ".")
+	runGit(t, repo, "commit", "-q", "-m", "init")
+	return repo
+}
+
+func writeFile(t *testing.T, path, content string) {
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+}
+
+func runGit(t *testing.T, dir string, args ...string) {
+	cmd := exec.Command("git", append([]string{"-c", "user.name=rtui", "-c", "user.email=rtui@example.com"}, args...)...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, out)
+	}
+}
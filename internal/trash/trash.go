@@ -0,0 +1,217 @@
+package trash
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// MaxAge is how long backups are kept: the undo history lives only as
+// long as rtui runs, so older entries are only there for manual recovery.
+const MaxAge = 7 * 24 * time.Hour
+
+// ErrChanged is returned by Restore when the file was edited after the
+// discard.
+var ErrChanged = errors.New("changed since it was discarded")
+
+// Entry is one backed-up file or directory from a repo worktree.
+type Entry struct {
+	Dir     string
+	Repo    string
+	File    string
+	Existed bool
+	// Settled is set once After records what the discard left at File.
+	Settled bool
+	After   string
+}
+
+// Root returns the trash directory, honoring XDG_STATE_HOME.
+func Root() string {
+	if state := os.Getenv("XDG_STATE_HOME"); state != "" {
+		return filepath.Join(state, "rtui", "trash")
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".local", "state", "rtui", "trash")
+}
+
+// Save copies repo/file into a new trash entry. A missing file is
+// recorded so Restore can remove it again.
+func Save(repo, file string) (Entry, error) {
+	rel, err := cleanRel(file)
+	if err != nil {
+		return Entry{}, err
+	}
+	stamp := time.Now().Format("20060102-150405.000000000")
+	entry := Entry{
+		Dir:  filepath.Join(Root(), stamp+"-"+filepath.Base(repo)),
+		Repo: repo,
+		File: rel,
+	}
+	src := filepath.Join(repo, rel)
+	if _, err := os.Lstat(src); os.IsNotExist(err) {
+		return entry, os.MkdirAll(entry.Dir, 0o755)
+	} else if err != nil {
+		return Entry{}, err
+	}
+	entry.Existed = true
+	if err := copyTree(src, filepath.Join(entry.Dir, rel)); err != nil {
+		return Entry{}, err
+	}
+	return entry, nil
+}
+
+// Settle records what the discard left at the path, so Restore can tell
+// whether it was edited since.
+func (e Entry) Settle() (Entry, error) {
+	sum, err := digest(filepath.Join(e.Repo, e.File))
+	if err != nil {
+		return e, err
+	}
+	e.Settled, e.After = true, sum
+	return e, nil
+}
+
+// Restore puts the backed-up content back into the worktree. A settled
+// entry refuses, with ErrChanged, when the path no longer holds what the
+// discard left, rather than overwrite the newer content.
+func (e Entry) Restore() error {
+	dst := filepath.Join(e.Repo, e.File)
+	if e.Settled {
+		sum, err := digest(dst)
+		if err != nil {
+			return err
+		}
+		if sum != e.After {
+			return fmt.Errorf("%s %w; the discarded version is in %s", e.File, ErrChanged, filepath.Join(e.Dir, e.File))
+		}
+	}
+	if err := os.RemoveAll(dst); err != nil {
+		return err
+	}
+	if !e.Existed {
+		return nil
+	}
+	return copyTree(filepath.Join(e.Dir, e.File), dst)
+}
+
+// Remove deletes the entry's backup.
+func (e Entry) Remove() error {
+	return os.RemoveAll(e.Dir)
+}
+
+// Prune deletes entries older than maxAge.
+func Prune(maxAge time.Duration) error {
+	dirs, err := os.ReadDir(Root())
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	cutoff := time.Now().Add(-maxAge)
+	var errs []error
+	for _, d := range dirs {
+		info, err := d.Info()
+		if err != nil || !info.ModTime().Before(cutoff) {
+			continue
+		}
+		errs = append(errs, os.RemoveAll(filepath.Join(Root(), d.Name())))
+	}
+	return errors.Join(errs...)
+}
+
+// digest hashes the names, modes and content under path; "" when it does
+// not exist.
+func digest(path string) (string, error) {
+	if _, err := os.Lstat(path); os.IsNotExist(err) {
+		return "", nil
+	}
+	h := sha256.New()
+	err := filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(path, p)
+		fmt.Fprintf(h, "%s\x00%v\x00", rel, info.Mode())
+		switch {
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(p)
+			if err != nil {
+				return err
+			}
+			io.WriteString(h, link)
+		case info.Mode().IsRegular():
+			f, err := os.Open(p)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			if _, err := io.Copy(h, f); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func cleanRel(file string) (string, error) {
+	rel := filepath.Clean(strings.TrimSuffix(file, "/"))
+	if rel == "." || filepath.IsAbs(rel) || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid path %q", file)
+	}
+	return rel, nil
+}
+
+func copyTree(src, dst string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(src, path)
+		target := filepath.Join(dst, rel)
+		switch {
+		case info.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm()|0o700)
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		default:
+			return copyFile(path, target, info.Mode().Perm())
+		}
+	})
+}
+
+func copyFile(src, dst string, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,149 @@
+package trash
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSaveAndRestoreFile(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	repo := t.TempDir()
+	file := filepath.Join(repo, "dir", "a.txt")
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(file, []byte("keep me"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	entry, err := Save(repo, "dir/a.txt")
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if !entry.Existed {
+		t.Fatal("expected Existed true")
+	}
+	if err := os.WriteFile(file, []byte("discarded"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	if err := entry.Restore(); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	got, _ := os.ReadFile(file)
+	if string(got) != "keep me" {
+		t.Fatalf("expected restored content, got %q", got)
+	}
+}
+
+func TestSaveMissingRestoresAbsence(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	repo := t.TempDir()
+
+	entry, err := Save(repo, "gone.txt")
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if entry.Existed {
+		t.Fatal("expected Existed false")
+	}
+	path := filepath.Join(repo, "gone.txt")
+	if err := os.WriteFile(path, []byte("restored by git"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := entry.Restore(); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected file removed, got %v", err)
+	}
+}
+
+func TestSaveRejectsEscapingPath(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	if _, err := Save(t.TempDir(), "../outside"); err == nil {
+		t.Fatal("expected error for path outside repo")
+	}
+}
+
+func TestRootHonorsXDGStateHome(t *testing.T) {
+	state := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", state)
+	if got := Root(); got != filepath.Join(state, "rtui", "trash") {
+		t.Fatalf("Root() = %q", got)
+	}
+}
+
+func TestRestoreRefusesEditsMadeAfterDiscard(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	repo := t.TempDir()
+	file := filepath.Join(repo, "a.txt")
+	if err := os.WriteFile(file, []byte("discarded work"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	entry, err := Save(repo, "a.txt")
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if err := os.WriteFile(file, []byte("from HEAD"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if entry, err = entry.Settle(); err != nil {
+		t.Fatalf("Settle: %v", err)
+	}
+	if err := os.WriteFile(file, []byte("written after the discard"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	if err := entry.Restore(); !errors.Is(err, ErrChanged) {
+		t.Fatalf("expected ErrChanged, got %v", err)
+	}
+	if got, _ := os.ReadFile(file); string(got) != "written after the discard" {
+		t.Fatalf("newer content must survive, got %q", got)
+	}
+	if err := os.WriteFile(file, []byte("from HEAD"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := entry.Restore(); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if got, _ := os.ReadFile(file); string(got) != "discarded work" {
+		t.Fatalf("expected restored content, got %q", got)
+	}
+}
+
+func TestPruneDropsOldEntries(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	repo := t.TempDir()
+	old, err := Save(repo, "old.txt")
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	recent, err := Save(repo, "recent.txt")
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	past := time.Now().Add(-2 * MaxAge)
+	if err := os.Chtimes(old.Dir, past, past); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+
+	if err := Prune(MaxAge); err != nil {
+		t.Fatalf("Prune: %v", err)
+	}
+	if _, err := os.Stat(old.Dir); !os.IsNotExist(err) {
+		t.Fatalf("expected the old entry pruned, got %v", err)
+	}
+	if _, err := os.Stat(recent.Dir); err != nil {
+		t.Fatalf("expected the recent entry kept: %v", err)
+	}
+	if err := recent.Remove(); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if _, err := os.Stat(recent.Dir); !os.IsNotExist(err) {
+		t.Fatalf("expected the entry removed, got %v", err)
+	}
+}
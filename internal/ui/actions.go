@@ -0,0 +1,567 @@
+package ui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"rtui/internal/git"
+)
+
+// action is one normal-mode command. The registry drives key dispatch,
+// the help screen, the footer and the command palette.
+type action struct {
+	id      string
+	keys    []string
+	section string
+	desc    string
+	// footer is the short footer label; empty keeps the action out of the
+	// footer.
+	footer string
+	// when reports whether the action applies to the current selection and
+	// focus. nil means always.
+	when func(Model) bool
+	run  func(Model) (tea.Model, tea.Cmd)
+}
+
+// Help sections, in display order.
+const (
+	sectionNavigation = "Navigation"
+	sectionActions    = "Actions"
+	sectionSync       = "Sync"
+	sectionGroups     = "Groups (cursor on header)"
+	sectionView       = "View"
+	sectionOther      = "Other"
+)
+
+var helpSections = []string{sectionNavigation, sectionActions, sectionSync, sectionGroups, sectionView, sectionOther}
+
+var actionRegistry []action
+
+func init() {
+	actionRegistry = defaultActions()
+}
+
+// defaultActions lists every normal-mode action. When several actions
+// share a key, the first whose when() passes handles it, so context-specific
+// actions come before general ones.
+func defaultActions() []action {
+	return []action{
+		{id: "down", keys: []string{"j", "down"}, section: sectionNavigation, desc: "Next repo / scroll panel", run: Model.actDown},
+		{id: "up", keys: []string{"k", "up"}, section: sectionNavigation, desc: "Previous repo / scroll panel", run: Model.actUp},
+		{id: "page-down", keys: []string{"pgdown"}, section: sectionNavigation, desc: "Scroll panel down", when: bottomFocused, run: Model.actPageDown},
+		{id: "page-up", keys: []string{"pgup"}, section: sectionNavigation, desc: "Scroll panel up", when: bottomFocused, run: Model.actPageUp},
+		{id: "focus-repos", keys: []string{"1"}, section: sectionNavigation, desc: "Focus repo list", run: Model.actFocusRepos},
+		{id: "focus-bottom", keys: []string{"2"}, section: sectionNavigation, desc: "Focus bottom panel", run: Model.actFocusBottom},
+		{id: "toggle-panel", keys: []string{"tab"}, section: sectionNavigation, desc: "Toggle Changes/Graph", when: bottomFocused, run: Model.actTogglePanel},
+		{id: "search", keys: []string{"/"}, section: sectionNavigation, desc: "Search name/branch/path", footer: "search", run: Model.actSearch},
+		{id: "search-next", keys: []string{"n"}, section: sectionNavigation, desc: "Next search match", when: hasSearch, run: Model.actSearchNext},
+		{id: "search-prev", keys: []string{"N"}, section: sectionNavigation, desc: "Previous search match", when: hasSearch, run: Model.actSearchPrev},
+		{id: "palette", keys: []string{":", "ctrl+p"}, section: sectionNavigation, desc: "Command palette", footer: "palette", run: Model.actPalette},
+
+		{id: "add-path", keys: []string{"a"}, section: sectionActions, desc: "Add path", footer: "add path", run: Model.actAddPath},
+		{id: "paths", keys: []string{"A"}, section: sectionActions, desc: "Manage scan paths", run: Model.actPaths},
+		{id: "commit", keys: []string{"c"}, section: sectionActions, desc: "Commit (stages all)", footer: "commit", when: canCommit, run: Model.actCommit},
+		{id: "clone", keys: []string{"c"}, section: sectionActions, desc: "Clone missing manifest repo", when: onPlaceholder, run: Model.actClone},
+		{id: "branch", keys: []string{"b"}, section: sectionActions, desc: "Switch branch", footer: "branch", when: onRepo, run: Model.actBranch},
+		{id: "branch-all", keys: []string{"B"}, section: sectionActions, desc: "Switch branch across all listed repos", run: Model.actBranchAll},
+		{id: "conflicts", keys: []string{"m"}, section: sectionActions, desc: "Resolve conflicts / merge state", when: inMergeState, run: Model.actConflicts},
+		{id: "open-file", keys: []string{"o", "enter"}, section: sectionActions, desc: "Open selected file at change", when: onChange, run: Model.actOpenFile},
+		{id: "open", keys: []string{"o"}, section: sectionActions, desc: "Open repo in editor", footer: "open", when: onRepo, run: Model.actOpen},
+		{id: "discard", keys: []string{"x"}, section: sectionActions, desc: "Discard selected change", when: onChange, run: Model.actDiscard},
+		{id: "undo-discard", keys: []string{"u"}, section: sectionActions, desc: "Undo last discard", when: canUndo, run: Model.actUndoDiscard},
+		{id: "shell", keys: []string{"t"}, section: sectionActions, desc: "Shell / git TUI in repo", when: onRepo, run: Model.actShell},
+		{id: "settings", keys: []string{"s"}, section: sectionActions, desc: "Open config in editor", run: Model.actSettings},
+
+		{id: "pull", keys: []string{"p"}, section: sectionSync, desc: "Pull", footer: "pull", when: onRepo, run: Model.actPull},
+		{id: "push", keys: []string{"P"}, section: sectionSync, desc: "Push (offers -u / force)", footer: "push", when: onRepo, run: Model.actPush},
+		{id: "fetch", keys: []string{"f"}, section: sectionSync, desc: "Fetch all remotes", when: onRepo, run: Model.actFetch},
+		{id: "refresh", keys: []string{"r"}, section: sectionSync, desc: "Rescan all paths", footer: "refresh", run: Model.actRefresh},
+
+		{id: "group-toggle", keys: []string{"enter", " "}, section: sectionGroups, desc: "Collapse/expand group", when: onGroupRow, run: Model.actGroupToggle},
+		{id: "group-branch", keys: []string{"b"}, section: sectionGroups, desc: "Switch branch across group", when: onGroup, run: Model.actGroupBranch},
+		{id: "group-fetch", keys: []string{"f"}, section: sectionGroups, desc: "Fetch group", when: onGroup, run: Model.actGroupFetch},
+		{id: "group-pull", keys: []string{"p"}, section: sectionGroups, desc: "Pull group", when: onGroup, run: Model.actGroupPull},
+		{id: "group-push", keys: []string{"P"}, section: sectionGroups, desc: "Push group", when: onGroup, run: Model.actGroupPush},
+
+		{id: "filter-dirty", keys: []string{"d"}, section: sectionView, desc: "Toggle dirty-only", run: Model.actFilterDirty},
+		{id: "sort", keys: []string{"S"}, section: sectionView, desc: "Cycle sort mode", run: Model.actSort},
+
+		{id: "dismiss-banner", keys: []string{"esc"}, section: sectionOther, desc: "Hide config problems banner", when: hasBanner, run: Model.actDismissBanner},
+		{id: "help", keys: []string{"?"}, section: sectionOther, desc: "Help", footer: "?", run: Model.actHelp},
+		{id: "quit", keys: []string{"q", "ctrl+c"}, section: sectionOther, desc: "Quit", run: Model.actQuit},
+	}
+}
+
+// actionKeys returns the keys bound to a, after [keys] remapping.
+func (m Model) actionKeys(a action) []string {
+	return m.bindingKeys(a.id)
+}
+
+func (m Model) actionAvailable(a action) bool {
+	return a.when == nil || a.when(m)
+}
+
+// actionForKey picks the action bound to key. Without an applicable one
+// it falls back to the first bound action, whose own guard explains why
+// nothing happened.
+func (m Model) actionForKey(key string) (action, bool) {
+	var fallback action
+	found := false
+	for _, a := range actionRegistry {
+		if !containsString(m.actionKeys(a), key) {
+			continue
+		}
+		if m.actionAvailable(a) {
+			return a, true
+		}
+		if !found {
+			fallback, found = a, true
+		}
+	}
+	return fallback, found
+}
+
+// actionByID returns the registry action with the given id.
+func actionByID(id string) (action, bool) {
+	for _, a := range actionRegistry {
+		if a.id == id {
+			return a, true
+		}
+	}
+	return action{}, false
+}
+
+// availableActions lists actions valid in the current context.
+func (m Model) availableActions() []action {
+	var out []action
+	for _, a := range actionRegistry {
+		if m.actionAvailable(a) {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+func containsString(items []string, value string) bool {
+	for _, item := range items {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// keyLabel renders a bubbletea key name for display.
+func keyLabel(key string) string {
+	switch key {
+	case " ":
+		return "Space"
+	case "up":
+		return "↑"
+	case "down":
+		return "↓"
+	case "enter":
+		return "Enter"
+	case "tab":
+		return "Tab"
+	case "pgup":
+		return "PgUp"
+	case "pgdown":
+		return "PgDn"
+	case "esc":
+		return "Esc"
+	}
+	if rest, ok := strings.CutPrefix(key, "ctrl+"); ok {
+		return "Ctrl+" + strings.ToUpper(rest)
+	}
+	return key
+}
+
+func (m Model) keysLabel(a action) string {
+	keys := m.actionKeys(a)
+	labels := make([]string, len(keys))
+	for i, k := range keys {
+		labels[i] = keyLabel(k)
+	}
+	return strings.Join(labels, "/")
+}
+
+// Contexts.
+
+func onRepo(m Model) bool { return m.currentRepo() != nil }
+
+func onGroup(m Model) bool {
+	_, ok := m.currentGroup()
+	return ok
+}
+
+func onGroupRow(m Model) bool { return onGroup(m) && m.panelFocus == FocusRepos }
+
+func bottomFocused(m Model) bool { return m.panelFocus == FocusBottom }
+
+func onChange(m Model) bool {
+	if m.panelFocus != FocusBottom || m.bottomView != BottomChanges {
+		return false
+	}
+	_, ok := m.selectedChange()
+	return ok
+}
+
+func hasSearch(m Model) bool { return m.searchQuery != "" }
+
+func canCommit(m Model) bool {
+	repo := m.currentRepo()
+	return repo != nil && repo.IsDirty() && !repo.HasConflict
+}
+
+func inMergeState(m Model) bool {
+	repo := m.currentRepo()
+	return repo != nil && (repo.HasConflict || repo.State != git.StateNone)
+}
+
+func canUndo(m Model) bool { return len(m.discardHistory) > 0 }
+
+// Navigation.
+
+func (m Model) actDown() (tea.Model, tea.Cmd) {
+	if m.panelFocus == FocusBottom {
+		m.scrollBottom(1)
+		return m, nil
+	}
+	if m.cursor < len(m.repoRows())-1 {
+		m.cursor++
+		m.resetBottomScroll()
+		return m, m.maybeLoadGraph()
+	}
+	return m, nil
+}
+
+func (m Model) actUp() (tea.Model, tea.Cmd) {
+	if m.panelFocus == FocusBottom {
+		m.scrollBottom(-1)
+		return m, nil
+	}
+	if m.cursor > 0 {
+		m.cursor--
+		m.resetBottomScroll()
+		return m, m.maybeLoadGraph()
+	}
+	return m, nil
+}
+
+func (m Model) actPageDown() (tea.Model, tea.Cmd) {
+	if m.panelFocus == FocusBottom {
+		m.scrollBottom(5)
+	}
+	return m, nil
+}
+
+func (m Model) actPageUp() (tea.Model, tea.Cmd) {
+	if m.panelFocus == FocusBottom {
+		m.scrollBottom(-5)
+	}
+	return m, nil
+}
+
+func (m Model) actFocusRepos() (tea.Model, tea.Cmd) {
+	m.panelFocus = FocusRepos
+	return m, nil
+}
+
+func (m Model) actFocusBottom() (tea.Model, tea.Cmd) {
+	m.panelFocus = FocusBottom
+	return m, nil
+}
+
+func (m Model) actTogglePanel() (tea.Model, tea.Cmd) {
+	if m.panelFocus != FocusBottom {
+		return m, nil
+	}
+	m.toggleBottomView()
+	return m, m.maybeLoadGraph()
+}
+
+func (m Model) actSearch() (tea.Model, tea.Cmd) {
+	return m.startSearch(), nil
+}
+
+func (m Model) actSearchNext() (tea.Model, tea.Cmd) {
+	return m.cycleMatch(false)
+}
+
+func (m Model) actSearchPrev() (tea.Model, tea.Cmd) {
+	return m.cycleMatch(true)
+}
+
+func (m Model) actPalette() (tea.Model, tea.Cmd) {
+	return m.openPalette(), nil
+}
+
+// Actions.
+
+func (m Model) actAddPath() (tea.Model, tea.Cmd) {
+	m.mode = ModeAddPath
+	m.addPathInput = ""
+	m.addPathCandidates = nil
+	m.addPathChoice = -1
+	m.addPathPreview = pathPreview{}
+	return m, nil
+}
+
+func (m Model) actCommit() (tea.Model, tea.Cmd) {
+	repo := m.currentRepo()
+	if repo == nil {
+		return m, nil
+	}
+	if repo.HasConflict {
+		m = m.setStatusError("Cannot commit: repo has conflicts")
+		return m, nil
+	}
+	if !repo.IsDirty() {
+		m = m.setStatusInfo("Nothing to commit")
+		return m, nil
+	}
+	m.mode = ModeCommitInput
+	m.commitMsg = ""
+	return m, nil
+}
+
+func (m Model) actBranch() (tea.Model, tea.Cmd) {
+	repo := m.currentRepo()
+	if repo == nil {
+		return m, nil
+	}
+	m.mode = ModeBranchPicker
+	m = m.setStatusInfo("Loading branches...")
+	return m, m.loadBranchesCmd(repo.Path)
+}
+
+func (m Model) actConflicts() (tea.Model, tea.Cmd) {
+	repo := m.currentRepo()
+	if repo == nil {
+		return m, nil
+	}
+	if !repo.HasConflict && repo.State == git.StateNone {
+		m = m.setStatusInfo("No merge or conflicts in progress")
+		return m, nil
+	}
+	m.mode = ModeConflicts
+	m.conflictCursor = 0
+	m.confirmAbort = false
+	return m, nil
+}
+
+func (m Model) actOpenFile() (tea.Model, tea.Cmd) {
+	repo := m.currentRepo()
+	if repo == nil || m.panelFocus != FocusBottom || m.bottomView != BottomChanges {
+		return m, nil
+	}
+	file, ok := m.selectedChange()
+	if !ok {
+		return m, nil
+	}
+	m = m.setStatusInfo("Opening " + file.Path + "...")
+	return m, m.openChangeCmd(*repo, file)
+}
+
+func (m Model) actOpen() (tea.Model, tea.Cmd) {
+	repo := m.currentRepo()
+	if repo == nil {
+		return m, nil
+	}
+	m = m.setStatusInfo("Opening " + repo.Name + " in editor...")
+	return m, m.openPathCmd(m.config.ForRepo(repo.Path), repo.Path, "Opened "+repo.Name+" in editor")
+}
+
+func (m Model) actDiscard() (tea.Model, tea.Cmd) {
+	if m.panelFocus != FocusBottom || m.bottomView != BottomChanges {
+		return m, nil
+	}
+	file, ok := m.selectedChange()
+	if !ok {
+		return m, nil
+	}
+	if file.Status == git.StatusConflict {
+		m = m.setStatusError("Cannot discard conflict: use m to resolve")
+		return m, nil
+	}
+	m.pendingDiscard = file
+	m.mode = ModeConfirmDiscard
+	return m, nil
+}
+
+func (m Model) actUndoDiscard() (tea.Model, tea.Cmd) {
+	if len(m.discardHistory) == 0 {
+		m = m.setStatusInfo("Nothing to undo")
+		return m, nil
+	}
+	record := m.discardHistory[len(m.discardHistory)-1]
+	m.discardHistory = m.discardHistory[:len(m.discardHistory)-1]
+	m = m.setStatusInfo("Restoring " + record.file.Path + "...")
+	return m, m.undoDiscardCmd(record)
+}
+
+func (m Model) actShell() (tea.Model, tea.Cmd) {
+	repo := m.currentRepo()
+	if repo == nil {
+		return m, nil
+	}
+	return m, m.openShellCmd(*repo)
+}
+
+func (m Model) actSettings() (tea.Model, tea.Cmd) {
+	m = m.setStatusInfo("Opening settings in editor...")
+	return m, m.openPathCmd(m.config, m.config.File(), "Opened settings in editor")
+}
+
+// Sync.
+
+func (m Model) actPull() (tea.Model, tea.Cmd) {
+	repo := m.currentRepo()
+	if repo == nil {
+		return m, nil
+	}
+	if repo.HasConflict {
+		m = m.setStatusError("Cannot pull: repo has conflicts")
+		return m, nil
+	}
+	opts := m.pullOptions(*repo)
+	if repo.IsDirty() && !opts.Autostash {
+		m = m.setStatusError("Cannot pull: repo has uncommitted changes")
+		return m, nil
+	}
+	if opts.Strategy != "" {
+		m = m.setStatusInfo("Pulling (" + opts.Strategy + ")...")
+	} else {
+		m = m.setStatusInfo("Pulling...")
+	}
+	return m, func() tea.Msg {
+		result, err := git.Pull(repo.Path, opts)
+		if err != nil {
+			return errMsg(err)
+		}
+		return pullDoneMsg{name: repo.Name, result: result}
+	}
+}
+
+func (m Model) actPush() (tea.Model, tea.Cmd) {
+	repo := m.currentRepo()
+	if repo == nil {
+		return m, nil
+	}
+	if repo.HasConflict {
+		m = m.setStatusError("Cannot push: repo has conflicts")
+		return m, nil
+	}
+	if repo.IsDirty() {
+		m = m.setStatusError("Cannot push: repo has uncommitted changes")
+		return m, nil
+	}
+	if repo.IsDetached() {
+		m = m.setStatusError("Cannot push: detached HEAD")
+		return m, nil
+	}
+	if m.config.RepoSettings(repo.Path).IsProtected(repo.Branch) {
+		m = m.setStatusError("Cannot push: " + repo.Branch + " is protected in config")
+		return m, nil
+	}
+	if repo.HasDiverged() {
+		return m, m.checkRewriteCmd(*repo)
+	}
+	if repo.Behind > 0 {
+		m = m.setStatusError("Cannot push: behind remote (pull first)")
+		return m, nil
+	}
+	if repo.Upstream == "" {
+		m = m.setStatusInfo("No upstream, loading remotes...")
+		return m, m.loadRemotesCmd(repo.Path)
+	}
+	m = m.setStatusInfo("Pushing...")
+	return m, func() tea.Msg {
+		if err := git.Push(repo.Path); err != nil {
+			return errMsg(err)
+		}
+		return pushDoneMsg(repo.Name)
+	}
+}
+
+func (m Model) actFetch() (tea.Model, tea.Cmd) {
+	repo := m.currentRepo()
+	if repo == nil {
+		return m, nil
+	}
+	m = m.setStatusInfo("Fetching...")
+	return m, func() tea.Msg {
+		if err := git.FetchAll(repo.Path); err != nil {
+			return errMsg(err)
+		}
+		return statusMsg("Fetched " + repo.Name)
+	}
+}
+
+func (m Model) actRefresh() (tea.Model, tea.Cmd) {
+	m.loading = true
+	m = m.setStatusInfo("Refreshing...")
+	return m, m.loadRepos()
+}
+
+// Groups.
+
+func (m Model) actGroupToggle() (tea.Model, tea.Cmd) {
+	if group, ok := m.currentGroup(); ok {
+		m.toggleGroup(group)
+	}
+	return m, nil
+}
+
+func (m Model) actGroupFetch() (tea.Model, tea.Cmd) {
+	return m.groupBulk(bulkFetch)
+}
+
+func (m Model) actGroupPull() (tea.Model, tea.Cmd) {
+	return m.groupBulk(bulkPull)
+}
+
+func (m Model) actGroupPush() (tea.Model, tea.Cmd) {
+	return m.groupBulk(bulkPush)
+}
+
+func (m Model) groupBulk(op bulkOp) (tea.Model, tea.Cmd) {
+	group, ok := m.currentGroup()
+	if !ok {
+		return m, nil
+	}
+	return m.startBulk(op, group)
+}
+
+// View.
+
+func (m Model) actFilterDirty() (tea.Model, tea.Cmd) {
+	m.filterDirty = !m.filterDirty
+	m.cursor = 0
+	return m, nil
+}
+
+func (m Model) actSort() (tea.Model, tea.Cmd) {
+	next, err := m.cycleSort()
+	if err != nil {
+		return next, func() tea.Msg { return errMsg(err) }
+	}
+	return next, nil
+}
+
+// Other.
+
+func (m Model) actHelp() (tea.Model, tea.Cmd) {
+	m.mode = ModeHelp
+	m.helpScroll = 0
+	return m, nil
+}
+
+func (m Model) actQuit() (tea.Model, tea.Cmd) {
+	if m.watcher != nil {
+		_ = m.watcher.Close()
+	}
+	return m, tea.Quit
+}
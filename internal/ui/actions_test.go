@@ -0,0 +1,139 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"rtui/internal/config"
+	"rtui/internal/git"
+)
+
+func TestActionRegistryIsWellFormed(t *testing.T) {
+	ids := map[string]bool{}
+	for _, a := range actionRegistry {
+		if a.id == "" || a.desc == "" || a.run == nil || len(a.keys) == 0 {
+			t.Fatalf("incomplete action %+v", a)
+		}
+		if ids[a.id] {
+			t.Fatalf("duplicate action id %q", a.id)
+		}
+		ids[a.id] = true
+		if !containsString(helpSections, a.section) {
+			t.Fatalf("action %q has unknown section %q", a.id, a.section)
+		}
+	}
+}
+
+func TestActionForKeyPrefersContext(t *testing.T) {
+	m := groupedModel()
+	m.cursor = 0 // payments header
+	if a, _ := m.actionForKey("f"); a.id != "group-fetch" {
+		t.Fatalf("expected group-fetch on header, got %q", a.id)
+	}
+	m.cursor = 1
+	if a, _ := m.actionForKey("f"); a.id != "fetch" {
+		t.Fatalf("expected fetch on repo, got %q", a.id)
+	}
+
+	m.repos[0].ChangedFiles = []git.ChangedFile{{Path: "a.go", Status: git.StatusModified}}
+	m.panelFocus = FocusBottom
+	if a, _ := m.actionForKey("o"); a.id != "open-file" {
+		t.Fatalf("expected open-file in CHANGES, got %q", a.id)
+	}
+}
+
+func TestPaletteListsOnlyContextActions(t *testing.T) {
+	m := groupedModel()
+	m.cursor = 0
+	ids := paletteIDs(m.openPalette())
+	if !ids["group-pull"] || ids["pull"] || ids["commit"] {
+		t.Fatalf("unexpected header actions: %v", ids)
+	}
+
+	m.cursor = 1 // pay-api, dirty
+	ids = paletteIDs(m.openPalette())
+	if !ids["pull"] || !ids["commit"] || ids["group-pull"] || ids["undo-discard"] {
+		t.Fatalf("unexpected repo actions: %v", ids)
+	}
+}
+
+func paletteIDs(m Model) map[string]bool {
+	ids := map[string]bool{}
+	for _, item := range m.paletteItems() {
+		ids[item.action.id] = true
+	}
+	return ids
+}
+
+func TestPaletteFiltersAndRuns(t *testing.T) {
+	m := NewModel(config.DefaultConfig())
+	m.width = 80
+	m.height = 30
+	m.repos = []git.Repo{{Name: "api", Path: "/src/api", Modified: 1}}
+
+	m = typeKeys(t, m, ":", "c", "o", "m", "m")
+	if m.mode != ModePalette {
+		t.Fatalf("expected palette mode, got %v", m.mode)
+	}
+	items := m.paletteItems()
+	if len(items) == 0 || items[0].action.id != "commit" {
+		t.Fatalf("expected commit first, got %+v", items)
+	}
+	m = typeKeys(t, m, "enter")
+	if m.mode != ModeCommitInput {
+		t.Fatalf("expected commit input after running, got %v", m.mode)
+	}
+}
+
+func TestPaletteEscCloses(t *testing.T) {
+	m := typeKeys(t, searchModel(), ":", "x", "esc")
+	if m.mode != ModeNormal || m.paletteQuery != "" {
+		t.Fatalf("expected closed palette, got mode=%v query=%q", m.mode, m.paletteQuery)
+	}
+}
+
+func TestHelpScrollsWithinHeight(t *testing.T) {
+	m := searchModel()
+	m.height = 24
+	m2, _ := m.actHelp()
+	m = m2.(Model)
+	help := m.renderHelp()
+	if lipgloss.Height(help) > m.height || !strings.Contains(help, "↓ more") || strings.Contains(help, "↑ more") {
+		t.Fatalf("help should fit %d lines with a more marker, got %d:\n%s", m.height, lipgloss.Height(help), help)
+	}
+
+	m = typeKeys(t, m, "j", "pgdown")
+	if m.mode != ModeHelp || m.helpScroll != 1+m.helpRows() {
+		t.Fatalf("expected help scrolled to %d, got mode %v scroll %d", 1+m.helpRows(), m.mode, m.helpScroll)
+	}
+	for range 10 {
+		m = typeKeys(t, m, "pgdown")
+	}
+	last := m.helpLines()[len(m.helpLines())-1]
+	if help := m.renderHelp(); !strings.Contains(help, "↑ more") || strings.Contains(help, "↓ more") || !strings.Contains(help, strings.TrimSpace(last)) {
+		t.Fatalf("expected the end of the help:\n%s", help)
+	}
+	m = typeKeys(t, m, "x")
+	if m.mode != ModeNormal {
+		t.Fatalf("other keys should close the help, got mode %v", m.mode)
+	}
+}
+
+func TestHelpAndFooterRenderFromRegistry(t *testing.T) {
+	m := searchModel()
+	help := strings.Join(m.helpLines(), "\n")
+	for _, a := range actionRegistry {
+		if !strings.Contains(help, a.desc) {
+			t.Fatalf("help missing %q", a.desc)
+		}
+	}
+	var plain []string
+	for _, tok := range m.footerTokens() {
+		plain = append(plain, tok.plain)
+	}
+	if got := strings.Join(plain, " "); !strings.Contains(got, ":palette") || !strings.Contains(got, "commit") {
+		t.Fatalf("unexpected footer %q", got)
+	}
+}
@@ -0,0 +1,166 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"rtui/internal/config"
+	"rtui/internal/git"
+)
+
+// maxPathCandidates caps the completion dropdown.
+const maxPathCandidates = 6
+
+// pathPreview describes what adding a typed path would do.
+type pathPreview struct {
+	path    string
+	missing bool
+	notDir  bool
+	covered string
+	repos   int
+}
+
+type addPathPreviewMsg pathPreview
+
+// splitPathInput splits typed input into the directory part (kept as
+// typed, including a leading ~) and the partial name being completed.
+func splitPathInput(input string) (dir, base string) {
+	if input == "~" {
+		return "~/", ""
+	}
+	i := strings.LastIndex(input, string(os.PathSeparator))
+	if i < 0 {
+		return "", input
+	}
+	return input[:i+1], input[i+1:]
+}
+
+// pathCandidates lists subdirectories of the typed directory that start
+// with the partial name. Hidden directories are offered only once the
+// name starts with a dot.
+func pathCandidates(input string) []string {
+	if input == "" {
+		return nil
+	}
+	dir, base := splitPathInput(input)
+	readDir := config.NormalizePath(dir)
+	if dir == "" {
+		readDir = "."
+	}
+	entries, err := os.ReadDir(readDir)
+	if err != nil {
+		return nil
+	}
+	var out []string
+	for _, e := range entries {
+		name := e.Name()
+		if !strings.HasPrefix(name, base) || (strings.HasPrefix(name, ".") && !strings.HasPrefix(base, ".")) {
+			continue
+		}
+		if !e.IsDir() {
+			info, err := os.Stat(filepath.Join(readDir, name))
+			if err != nil || !info.IsDir() {
+				continue
+			}
+		}
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func commonPrefix(items []string) string {
+	if len(items) == 0 {
+		return ""
+	}
+	prefix := items[0]
+	for _, item := range items[1:] {
+		for !strings.HasPrefix(item, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	for !utf8.ValidString(prefix) {
+		prefix = prefix[:len(prefix)-1]
+	}
+	return prefix
+}
+
+// setAddPathInput replaces the input, recomputes candidates and starts a
+// preview scan for the new path.
+func (m Model) setAddPathInput(input string) (Model, tea.Cmd) {
+	m.addPathInput = input
+	m.addPathCandidates = pathCandidates(input)
+	m.addPathChoice = -1
+	if config.NormalizePath(input) == "" {
+		m.addPathPreview = pathPreview{}
+		return m, nil
+	}
+	return m, previewPathCmd(m.config, input)
+}
+
+// completeAddPath handles Tab: accept the highlighted or only candidate,
+// otherwise extend to the candidates' common prefix, otherwise start
+// highlighting candidates.
+func (m Model) completeAddPath() (Model, tea.Cmd) {
+	candidates := m.addPathCandidates
+	dir, base := splitPathInput(m.addPathInput)
+	switch {
+	case m.addPathChoice >= 0 && m.addPathChoice < len(candidates):
+		return m.setAddPathInput(dir + candidates[m.addPathChoice] + string(os.PathSeparator))
+	case len(candidates) == 1:
+		return m.setAddPathInput(dir + candidates[0] + string(os.PathSeparator))
+	case len(candidates) > 1:
+		if prefix := commonPrefix(candidates); len(prefix) > len(base) {
+			return m.setAddPathInput(dir + prefix)
+		}
+		m.addPathChoice = 0
+	case m.addPathInput == "~":
+		return m.setAddPathInput(dir)
+	}
+	return m, nil
+}
+
+func (m Model) moveAddPathChoice(delta int) Model {
+	if len(m.addPathCandidates) == 0 {
+		return m
+	}
+	m.addPathChoice = clamp(m.addPathChoice+delta, -1, len(m.addPathCandidates)-1)
+	return m
+}
+
+func previewPathCmd(cfg config.Config, input string) tea.Cmd {
+	return func() tea.Msg {
+		return addPathPreviewMsg(previewPath(cfg, config.NormalizePath(input)))
+	}
+}
+
+// previewPath stats the path, checks it against the configured roots and
+// counts the repos a scan at scan_depth would find.
+func previewPath(cfg config.Config, path string) pathPreview {
+	p := pathPreview{path: path}
+	info, err := os.Stat(path)
+	if err != nil {
+		p.missing = true
+		return p
+	}
+	if !info.IsDir() {
+		p.notDir = true
+		return p
+	}
+	roots := cfg.ScanPaths()
+	for _, root := range roots {
+		if root == path {
+			p.covered = root
+		}
+	}
+	if p.covered == "" {
+		p.covered, _ = coveringRoot(roots, path)
+	}
+	p.repos = len(git.FindRepos(path, cfg.ScanDepth))
+	return p
+}
@@ -0,0 +1,104 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"rtui/internal/config"
+)
+
+func addPathModel(t *testing.T) (Model, string) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	for _, dir := range []string{"alpha", "alps", "beta", ".alcove", "beta/one/.git", "beta/two/.git"} {
+		if err := os.MkdirAll(filepath.Join(home, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(home, "al.txt"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	m := NewModel(config.DefaultConfig())
+	m.width = 80
+	m.height = 30
+	next, _ := m.actAddPath()
+	return next.(Model), home
+}
+
+// typeText types s one key at a time, as a terminal would.
+func typeText(t *testing.T, m Model, s string) Model {
+	t.Helper()
+	for _, r := range s {
+		m = typeKeys(t, m, string(r))
+	}
+	return m
+}
+
+func pressTab(m Model) Model {
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	return next.(Model)
+}
+
+func TestAddPathTabCompletesDirectories(t *testing.T) {
+	m, home := addPathModel(t)
+	m = typeText(t, m, home+"/al")
+	if strings.Join(m.addPathCandidates, ",") != "alpha,alps" {
+		t.Fatalf("candidates = %v (files and hidden dirs should be skipped)", m.addPathCandidates)
+	}
+	m = pressTab(m)
+	if m.addPathInput != home+"/alp" {
+		t.Fatalf("input = %q, want common prefix", m.addPathInput)
+	}
+	m = pressTab(m)
+	if m.addPathChoice != 0 {
+		t.Fatalf("second tab should highlight the first candidate, got %d", m.addPathChoice)
+	}
+	m = typeKeys(t, m, "enter")
+	if m.addPathInput != home+"/alpha/" || m.mode != ModeAddPath {
+		t.Fatalf("input = %q mode %v, want accepted candidate", m.addPathInput, m.mode)
+	}
+}
+
+func TestAddPathCompletesTilde(t *testing.T) {
+	m, _ := addPathModel(t)
+	m = typeText(t, m, "~/be")
+	m = pressTab(m)
+	if m.addPathInput != "~/beta/" {
+		t.Fatalf("input = %q, want ~/beta/", m.addPathInput)
+	}
+}
+
+func TestPreviewPathFlagsProblems(t *testing.T) {
+	_, home := addPathModel(t)
+	cfg := config.DefaultConfig()
+	cfg.Paths = []string{home}
+
+	if p := previewPath(cfg, filepath.Join(home, "beta")); p.repos != 2 || p.covered != home {
+		t.Fatalf("preview = %+v, want 2 repos covered by %s", p, home)
+	}
+	if p := previewPath(cfg, filepath.Join(home, "al.txt")); !p.notDir {
+		t.Fatalf("preview = %+v, want not a directory", p)
+	}
+	if p := previewPath(cfg, filepath.Join(home, "nope")); !p.missing {
+		t.Fatalf("preview = %+v, want missing", p)
+	}
+}
+
+func TestAddPathShowsPreview(t *testing.T) {
+	m, home := addPathModel(t)
+	m = typeText(t, m, home+"/beta")
+	next, _ := m.Update(addPathPreviewMsg(previewPath(m.config, home+"/beta")))
+	m = next.(Model)
+	if !strings.Contains(m.renderAddPath(), "2 repos at depth 1") {
+		t.Fatalf("missing preview:\n%s", m.renderAddPath())
+	}
+	next, _ = m.Update(addPathPreviewMsg(previewPath(m.config, home)))
+	if next.(Model).addPathPreview.path != home+"/beta" {
+		t.Fatal("a stale preview should be ignored")
+	}
+}
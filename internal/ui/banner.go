@@ -0,0 +1,46 @@
+package ui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"rtui/internal/config"
+)
+
+// hasBanner reports whether the config problems banner is showing.
+func hasBanner(m Model) bool { return len(m.problems) > 0 && !m.bannerHidden }
+
+// bannerLines is the height of the banner above the repo list.
+func (m Model) bannerLines() int {
+	if hasBanner(m) {
+		return 1
+	}
+	return 0
+}
+
+// renderBanner summarizes config problems on one line: the count, the
+// first problem and where to see the rest. Problems never stop rtui from
+// starting; this is how they surface.
+func (m Model) renderBanner() string {
+	first := m.problems[0]
+	for _, p := range m.problems {
+		if p.Severity == config.SeverityError {
+			first = p
+			break
+		}
+	}
+	style := modifiedStyle
+	if config.HasErrors(m.problems) {
+		style = conflictStyle
+	}
+	n := len(m.problems)
+	text := fmt.Sprintf("⚠ %d config %s: %s · rtui config check · %s",
+		n, plural(n, "problem", "problems"), first, m.hint("dismiss-banner", "hide"))
+	return style.Render(truncate(text, m.width))
+}
+
+func (m Model) actDismissBanner() (tea.Model, tea.Cmd) {
+	m.bannerHidden = true
+	return m, nil
+}
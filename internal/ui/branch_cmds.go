@@ -29,21 +29,10 @@ func (m Model) loadBranchesCmd(path string) tea.Cmd {
 
 func (m Model) switchBranchCmd(path string, item BranchItem, stash bool) tea.Cmd {
 	return func() tea.Msg {
-		if stash {
-			if err := git.StashPush(path); err != nil {
-				return errMsg(err)
-			}
-		}
-		if item.IsRemote {
-			if err := git.CheckoutRemoteBranch(path, item.Name); err != nil {
-				return errMsg(err)
-			}
-		} else {
-			if err := git.CheckoutBranch(path, item.Name); err != nil {
-				return errMsg(err)
-			}
+		if err := git.SwitchBranch(path, item.Name, item.IsRemote, stash); err != nil {
+			return errMsg(err)
 		}
-		repo := git.GetRepoStatus(path)
+		repo := m.repoStatus(path)
 		return repoUpdatedMsg{repo: repo}
 	}
 }
@@ -129,23 +129,32 @@ func (m Model) selectedBranch() (BranchItem, bool) {
 }
 
 func (m Model) handleBranchPicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "tab", "ctrl+i":
+	if msg.String() == "backspace" {
+		filter := m.branchFilter()
+		if len(filter) > 0 {
+			filter = filter[:len(filter)-1]
+			m.setBranchFilter(filter)
+			m.branchCursor = 0
+		}
+		return m, nil
+	}
+	switch m.modalAction(ModeBranchPicker, msg.String()) {
+	case "picker-tab":
 		m.branchTab = toggleTab(m.branchTab)
 		m.branchCursor = 0
 		return m, nil
-	case "l":
+	case "picker-local":
 		m.branchTab = BranchTabLocal
 		m.branchCursor = 0
 		return m, nil
-	case "r":
+	case "picker-remote":
 		m.branchTab = BranchTabRemote
 		m.branchCursor = 0
 		return m, nil
-	case "esc":
+	case "picker-cancel":
 		m.mode = ModeNormal
 		return m, nil
-	case "enter":
+	case "picker-switch":
 		item, ok := m.selectedBranch()
 		if !ok {
 			return m, nil
@@ -168,19 +177,12 @@ func (m Model) handleBranchPicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.mode = ModeNormal
 		m = m.setStatusInfo("Switching to " + item.Name + "...")
 		return m, m.switchBranchCmd(repo.Path, item, false)
-	case "backspace":
-		filter := m.branchFilter()
-		if len(filter) > 0 {
-			filter = filter[:len(filter)-1]
-			m.setBranchFilter(filter)
-			m.branchCursor = 0
-		}
-	case "j", "down":
+	case "picker-down":
 		items := m.filteredBranches()
 		if m.branchCursor < len(items)-1 {
 			m.branchCursor++
 		}
-	case "k", "up":
+	case "picker-up":
 		if m.branchCursor > 0 {
 			m.branchCursor--
 		}
@@ -194,8 +196,12 @@ func (m Model) handleBranchPicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m Model) handleConfirmStash(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "s":
+	switch m.modalAction(ModeConfirmStash, msg.String()) {
+	case "stash-confirm":
+		if branch := m.cross.pending; branch != "" {
+			m.cross.pending = ""
+			return m.startCrossSwitch(branch, true)
+		}
 		repo := m.currentRepo()
 		if repo == nil {
 			m.mode = ModeNormal
@@ -206,7 +212,12 @@ func (m Model) handleConfirmStash(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.mode = ModeNormal
 		m = m.setStatusInfo("Stashing and switching...")
 		return m, m.switchBranchCmd(repo.Path, item, true)
-	case "c", "esc":
+	case "stash-cancel":
+		if m.cross.pending != "" {
+			m.cross.pending = ""
+			m.mode = ModeCrossBranch
+			return m, nil
+		}
 		m.pendingBranch = BranchItem{}
 		m.mode = ModeBranchPicker
 		return m, nil
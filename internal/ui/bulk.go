@@ -0,0 +1,127 @@
+package ui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"rtui/internal/git"
+)
+
+type bulkOp int
+
+const (
+	bulkFetch bulkOp = iota
+	bulkPull
+	bulkPush
+)
+
+func (op bulkOp) String() string {
+	switch op {
+	case bulkPull:
+		return "Pulled"
+	case bulkPush:
+		return "Pushed"
+	default:
+		return "Fetched"
+	}
+}
+
+type bulkDoneMsg struct {
+	summary string
+	failed  int
+}
+
+// bulkEligible applies the single-repo guards: pull skips dirty repos
+// (unless autostash), push only sends clean, ahead, tracked branches that
+// are not protected, and fetch skips repos marked no_auto_fetch.
+func (m Model) bulkEligible(op bulkOp, repo git.Repo) bool {
+	settings := m.config.RepoSettings(repo.Path)
+	if op == bulkFetch {
+		return !settings.NoAutoFetch
+	}
+	if repo.HasConflict || repo.State != git.StateNone {
+		return false
+	}
+	switch op {
+	case bulkPull:
+		return !repo.IsDirty() || m.config.PullAutostash
+	case bulkPush:
+		return !repo.IsDirty() && repo.Upstream != "" && repo.Ahead > 0 && repo.Behind == 0 && !settings.IsProtected(repo.Branch)
+	}
+	return true
+}
+
+func (m Model) startBulk(op bulkOp, group string) (Model, tea.Cmd) {
+	var targets []git.Repo
+	skipped := 0
+	for _, r := range m.groupRepos(group) {
+		if m.bulkEligible(op, r) {
+			targets = append(targets, r)
+		} else {
+			skipped++
+		}
+	}
+	if len(targets) == 0 {
+		m = m.setStatusInfo("Nothing to do in " + groupLabel(group))
+		return m, nil
+	}
+	m = m.setStatusInfo(fmt.Sprintf("%s: %d repos in %s...", bulkVerb(op), len(targets), groupLabel(group)))
+	return m, m.bulkCmd(op, group, targets, skipped)
+}
+
+func bulkVerb(op bulkOp) string {
+	switch op {
+	case bulkPull:
+		return "Pulling"
+	case bulkPush:
+		return "Pushing"
+	default:
+		return "Fetching"
+	}
+}
+
+func (m Model) bulkCmd(op bulkOp, group string, repos []git.Repo, skipped int) tea.Cmd {
+	opts := make([]git.PullOptions, len(repos))
+	for i, r := range repos {
+		opts[i] = m.pullOptions(r)
+	}
+	return func() tea.Msg {
+		done := 0
+		var firstErr string
+		for i, r := range repos {
+			var err error
+			switch op {
+			case bulkFetch:
+				err = git.FetchAll(r.Path)
+			case bulkPull:
+				_, err = git.Pull(r.Path, opts[i])
+			case bulkPush:
+				err = git.Push(r.Path)
+			}
+			if err != nil {
+				if firstErr == "" {
+					firstErr = r.Name + ": " + err.Error()
+				}
+				continue
+			}
+			done++
+		}
+		summary := fmt.Sprintf("%s %d/%d in %s", op, done, len(repos), groupLabel(group))
+		if skipped > 0 {
+			summary += fmt.Sprintf(", %d skipped", skipped)
+		}
+		failed := len(repos) - done
+		if failed > 0 {
+			summary += fmt.Sprintf(", %d failed (%s)", failed, firstErr)
+		}
+		return bulkDoneMsg{summary: summary, failed: failed}
+	}
+}
+
+func groupLabel(name string) string {
+	if name == "" {
+		return "Other"
+	}
+	return name
+}
@@ -0,0 +1,152 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"rtui/internal/git"
+)
+
+type conflictAction func(path, file string) error
+
+func (m Model) conflictFiles() []git.ChangedFile {
+	repo := m.currentRepo()
+	if repo == nil {
+		return nil
+	}
+	return repo.ConflictFiles()
+}
+
+func (m Model) selectedConflict() (git.ChangedFile, bool) {
+	files := m.conflictFiles()
+	if len(files) == 0 || m.conflictCursor < 0 || m.conflictCursor >= len(files) {
+		return git.ChangedFile{}, false
+	}
+	return files[m.conflictCursor], true
+}
+
+func (m Model) conflictFileCmd(path, file string, action conflictAction, done string) tea.Cmd {
+	return func() tea.Msg {
+		if err := action(path, file); err != nil {
+			return errMsg(err)
+		}
+		return repoUpdatedMsg{repo: m.repoStatus(path), status: done}
+	}
+}
+
+func (m Model) conflictOperationCmd(path string, state git.RepoState, abort bool) tea.Cmd {
+	return func() tea.Msg {
+		var err error
+		done := state.Verb() + " continued"
+		if abort {
+			err = git.AbortOperation(path, state)
+			done = state.Verb() + " aborted"
+		} else {
+			err = git.ContinueOperation(path, state)
+		}
+		if err != nil {
+			return errMsg(err)
+		}
+		return repoUpdatedMsg{repo: m.repoStatus(path), status: done}
+	}
+}
+
+func (m Model) mergetoolCmd(path, file string) tea.Cmd {
+	return tea.ExecProcess(git.MergetoolCmd(path, file), func(err error) tea.Msg {
+		return m.mergetoolExited(path, err)
+	})
+}
+
+// mergetoolExited refreshes the repo however the mergetool ended: an
+// aborted or partial resolution still changes which files conflict.
+func (m Model) mergetoolExited(path string, err error) tea.Msg {
+	if err != nil {
+		return repoUpdatedMsg{repo: m.repoStatus(path), status: "Mergetool: " + err.Error(), failed: true}
+	}
+	return repoUpdatedMsg{repo: m.repoStatus(path), status: "Mergetool finished"}
+}
+
+func (m Model) handleConflicts(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	repo := m.currentRepo()
+	if repo == nil {
+		m.mode = ModeNormal
+		return m, nil
+	}
+
+	if m.confirmAbort {
+		m.confirmAbort = false
+		if m.modalAction(ModeConflicts, msg.String()) == "conflict-abort-confirm" {
+			m = m.setStatusInfo("Aborting " + repo.State.Verb() + "...")
+			return m, m.conflictOperationCmd(repo.Path, repo.State, true)
+		}
+		return m, nil
+	}
+
+	action := m.modalAction(ModeConflicts, msg.String())
+	switch action {
+	case "conflict-close":
+		m.mode = ModeNormal
+		return m, nil
+	case "conflict-down":
+		if m.conflictCursor < len(m.conflictFiles())-1 {
+			m.conflictCursor++
+		}
+		return m, nil
+	case "conflict-up":
+		if m.conflictCursor > 0 {
+			m.conflictCursor--
+		}
+		return m, nil
+	case "conflict-continue":
+		if repo.State == git.StateNone || repo.State == git.StateBisecting {
+			m = m.setStatusError("Nothing to continue")
+			return m, nil
+		}
+		if repo.HasConflict {
+			m = m.setStatusError("Cannot continue: resolve all conflicts first")
+			return m, nil
+		}
+		m = m.setStatusInfo("Continuing " + repo.State.Verb() + "...")
+		return m, m.conflictOperationCmd(repo.Path, repo.State, false)
+	case "conflict-abort":
+		if repo.State == git.StateNone {
+			m = m.setStatusError("Nothing to abort")
+			return m, nil
+		}
+		m.confirmAbort = true
+		return m, nil
+	}
+
+	file, ok := m.selectedConflict()
+	if !ok {
+		return m, nil
+	}
+	switch action {
+	case "conflict-ours":
+		m = m.setStatusInfo("Taking ours for " + file.Path + "...")
+		return m, m.conflictFileCmd(repo.Path, file.Path, git.TakeOurs, "Took ours: "+file.Path)
+	case "conflict-theirs":
+		m = m.setStatusInfo("Taking theirs for " + file.Path + "...")
+		return m, m.conflictFileCmd(repo.Path, file.Path, git.TakeTheirs, "Took theirs: "+file.Path)
+	case "conflict-resolved":
+		return m, m.conflictFileCmd(repo.Path, file.Path, git.MarkResolved, "Resolved "+file.Path)
+	case "conflict-edit":
+		m = m.setStatusInfo("Opening " + file.Path + "...")
+		return m, m.openChangeCmd(*repo, file)
+	case "conflict-mergetool":
+		return m, m.mergetoolCmd(repo.Path, file.Path)
+	}
+	return m, nil
+}
+
+// syncConflictView keeps the cursor in range after a refresh and closes
+// the view once the operation has finished.
+func (m Model) syncConflictView() Model {
+	repo := m.currentRepo()
+	if repo == nil || (!repo.HasConflict && repo.State == git.StateNone) {
+		m.mode = ModeNormal
+		m.confirmAbort = false
+		return m
+	}
+	m.conflictCursor = clamp(m.conflictCursor, 0, max(len(repo.ConflictFiles())-1, 0))
+	return m
+}
@@ -0,0 +1,416 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"rtui/internal/git"
+)
+
+// crossBranch is the state of the cross-repo branch picker: one branch
+// name switched in every target repo that has it.
+type crossBranch struct {
+	label   string
+	repos   []repoBranches
+	items   []crossBranchItem
+	cursor  int
+	filter  string
+	create  bool
+	pending string
+	// load numbers the branch load this picker waits for, so a load
+	// started by an earlier, cancelled picker is dropped.
+	load int
+}
+
+// repoBranches is what one target repo has: local branch names, and
+// remote branch names mapped to the remote ref to track.
+type repoBranches struct {
+	repo          git.Repo
+	locals        map[string]bool
+	remotes       map[string]string
+	defaultBranch string
+	err           error
+}
+
+// crossBranchItem is a branch name with the number of repos that are on
+// it, have it locally and have it on a remote.
+type crossBranchItem struct {
+	Name    string
+	Current int
+	Local   int
+	Remote  int
+}
+
+type crossStepKind int
+
+const (
+	stepUnchanged crossStepKind = iota
+	stepLocal
+	stepRemote
+	stepCreate
+	stepMissing
+	stepBlocked
+)
+
+// crossStep is what switching to a branch does in one repo. ref is the
+// remote ref to track or the start point to create from.
+type crossStep struct {
+	repo git.Repo
+	kind crossStepKind
+	ref  string
+}
+
+func (s crossStep) changes() bool {
+	return s.kind == stepLocal || s.kind == stepRemote || s.kind == stepCreate
+}
+
+func (m Model) actGroupBranch() (tea.Model, tea.Cmd) {
+	group, ok := m.currentGroup()
+	if !ok {
+		return m, nil
+	}
+	return m.openCrossBranch(groupLabel(group), m.groupRepos(group))
+}
+
+func (m Model) actBranchAll() (tea.Model, tea.Cmd) {
+	var repos []git.Repo
+	for _, r := range m.visibleRepos() {
+		if !m.isPlaceholder(r) {
+			repos = append(repos, r)
+		}
+	}
+	return m.openCrossBranch("all repos", repos)
+}
+
+func (m Model) openCrossBranch(label string, repos []git.Repo) (tea.Model, tea.Cmd) {
+	if len(repos) == 0 {
+		return m.setStatusInfo("No repos in " + label), nil
+	}
+	m.crossLoads++
+	m.cross = crossBranch{label: label, load: m.crossLoads}
+	m.mode = ModeCrossBranch
+	m = m.setStatusInfo(fmt.Sprintf("Loading branches of %d repos...", len(repos)))
+	return m, loadCrossBranchesCmd(m.crossLoads, repos, m.config.DefaultBranchFor)
+}
+
+func loadCrossBranchesCmd(load int, repos []git.Repo, defaultFor func(path string) string) tea.Cmd {
+	return func() tea.Msg {
+		out := make([]repoBranches, len(repos))
+		for i, r := range repos {
+			out[i] = readRepoBranches(r, defaultFor(r.Path))
+		}
+		return crossBranchesLoadedMsg{load: load, repos: out}
+	}
+}
+
+// readRepoBranches lists r's branches. New branches start from
+// defaultBranch, the configured default_branch, or the detected one when
+// that is empty.
+func readRepoBranches(r git.Repo, defaultBranch string) repoBranches {
+	rb := repoBranches{repo: r, locals: map[string]bool{}, remotes: map[string]string{}}
+	locals, remotes, _, err := git.ListBranches(r.Path)
+	if err != nil {
+		rb.err = err
+		return rb
+	}
+	for _, b := range locals {
+		// "(HEAD detached at ...)" is listed like a branch.
+		if !strings.HasPrefix(b, "(") {
+			rb.locals[b] = true
+		}
+	}
+	for _, ref := range remotes {
+		remote, name, ok := strings.Cut(ref, "/")
+		if !ok || name == "HEAD" {
+			continue
+		}
+		// Prefer origin when several remotes have the branch.
+		if prev, seen := rb.remotes[name]; !seen || (remote == "origin" && !strings.HasPrefix(prev, "origin/")) {
+			rb.remotes[name] = ref
+		}
+	}
+	if defaultBranch == "" {
+		defaultBranch = git.DefaultBranch(r.Path)
+	}
+	rb.defaultBranch = defaultBranch
+	return rb
+}
+
+// crossBranchItems merges the branch names of every repo, most widely
+// present first.
+func crossBranchItems(repos []repoBranches) []crossBranchItem {
+	byName := map[string]*crossBranchItem{}
+	item := func(name string) *crossBranchItem {
+		if byName[name] == nil {
+			byName[name] = &crossBranchItem{Name: name}
+		}
+		return byName[name]
+	}
+	for _, rb := range repos {
+		for name := range rb.locals {
+			item(name).Local++
+		}
+		for name := range rb.remotes {
+			item(name).Remote++
+		}
+		if rb.locals[rb.repo.Branch] {
+			item(rb.repo.Branch).Current++
+		}
+	}
+	items := make([]crossBranchItem, 0, len(byName))
+	for _, it := range byName {
+		items = append(items, *it)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if pa, pb := a.Local+a.Remote, b.Local+b.Remote; pa != pb {
+			return pa > pb
+		}
+		return a.Name < b.Name
+	})
+	return items
+}
+
+func (c crossBranch) filtered() []crossBranchItem {
+	if c.filter == "" {
+		return c.items
+	}
+	needle := strings.ToLower(c.filter)
+	var out []crossBranchItem
+	for _, it := range c.items {
+		if strings.Contains(strings.ToLower(it.Name), needle) {
+			out = append(out, it)
+		}
+	}
+	return out
+}
+
+func (c crossBranch) selected() (crossBranchItem, bool) {
+	items := c.filtered()
+	if c.cursor < 0 || c.cursor >= len(items) {
+		return crossBranchItem{}, false
+	}
+	return items[c.cursor], true
+}
+
+// crossPlan decides, per repo, how to get onto branch: a local branch, a
+// tracking branch from a remote, a new branch from the default branch
+// (only with create), or nothing. Repos in a merge or rebase are blocked,
+// as in group pull.
+func crossPlan(repos []repoBranches, branch string, create bool) []crossStep {
+	steps := make([]crossStep, len(repos))
+	for i, rb := range repos {
+		s := crossStep{repo: rb.repo}
+		switch {
+		case rb.err != nil:
+			s.kind = stepBlocked
+		case rb.repo.Branch == branch:
+			s.kind = stepUnchanged
+		case rb.repo.HasConflict || rb.repo.State != git.StateNone:
+			s.kind = stepBlocked
+		case rb.locals[branch]:
+			s.kind = stepLocal
+		case rb.remotes[branch] != "":
+			s.kind, s.ref = stepRemote, rb.remotes[branch]
+		case create && rb.defaultBranch != "":
+			s.kind, s.ref = stepCreate, rb.defaultBranch
+		default:
+			s.kind = stepMissing
+		}
+		steps[i] = s
+	}
+	return steps
+}
+
+// dirtySteps counts the repos a switch would stash.
+func dirtySteps(steps []crossStep) int {
+	n := 0
+	for _, s := range steps {
+		if s.changes() && s.repo.IsDirty() {
+			n++
+		}
+	}
+	return n
+}
+
+func (m Model) handleCrossBranch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.String() == "backspace" {
+		if f := m.cross.filter; f != "" {
+			m.cross.filter = f[:len(f)-1]
+			m.cross.cursor = 0
+		}
+		return m, nil
+	}
+	switch m.modalAction(ModeCrossBranch, msg.String()) {
+	case "cross-cancel":
+		m.mode = ModeNormal
+		m.cross = crossBranch{}
+		return m.clearStatus(), nil
+	case "cross-down":
+		if m.cross.cursor < len(m.cross.filtered())-1 {
+			m.cross.cursor++
+		}
+	case "cross-up":
+		if m.cross.cursor > 0 {
+			m.cross.cursor--
+		}
+	case "cross-create":
+		m.cross.create = !m.cross.create
+	case "cross-switch":
+		item, ok := m.cross.selected()
+		if !ok {
+			return m, nil
+		}
+		steps := crossPlan(m.cross.repos, item.Name, m.cross.create)
+		if dirtySteps(steps) > 0 {
+			m.cross.pending = item.Name
+			m.mode = ModeConfirmStash
+			return m, nil
+		}
+		return m.startCrossSwitch(item.Name, false)
+	default:
+		if msg.Type == tea.KeyRunes && len(msg.Runes) == 1 {
+			m.cross.filter += string(msg.Runes)
+			m.cross.cursor = 0
+		}
+	}
+	return m, nil
+}
+
+func (m Model) startCrossSwitch(branch string, stash bool) (tea.Model, tea.Cmd) {
+	steps := crossPlan(m.cross.repos, branch, m.cross.create)
+	label := m.cross.label
+	m.cross = crossBranch{}
+	m.mode = ModeNormal
+	if stash {
+		m = m.setStatusInfo("Stashing and switching to " + branch + "...")
+	} else {
+		m = m.setStatusInfo("Switching to " + branch + "...")
+	}
+	return m, crossSwitchCmd(branch, label, steps, stash)
+}
+
+// crossSwitchCmd runs the plan repo by repo and reports like a group bulk
+// action, which reloads the list.
+func crossSwitchCmd(branch, label string, steps []crossStep, stash bool) tea.Cmd {
+	return func() tea.Msg {
+		switched, created, skipped, failed := 0, 0, 0, 0
+		var firstErr string
+		for _, s := range steps {
+			var err error
+			dirty := stash && s.repo.IsDirty()
+			switch s.kind {
+			case stepLocal:
+				err = git.SwitchBranch(s.repo.Path, branch, false, dirty)
+			case stepRemote:
+				err = git.SwitchBranch(s.repo.Path, s.ref, true, dirty)
+			case stepCreate:
+				err = git.CreateBranch(s.repo.Path, branch, s.ref, dirty)
+			case stepMissing, stepBlocked:
+				skipped++
+				continue
+			default:
+				continue
+			}
+			if err != nil {
+				failed++
+				if firstErr == "" {
+					firstErr = s.repo.Name + ": " + err.Error()
+				}
+				continue
+			}
+			switched++
+			if s.kind == stepCreate {
+				created++
+			}
+		}
+		summary := fmt.Sprintf("Switched to %s in %d/%d repos of %s", branch, switched, len(steps), label)
+		if created > 0 {
+			summary += fmt.Sprintf(" (%d created)", created)
+		}
+		if skipped > 0 {
+			summary += fmt.Sprintf(", %d skipped", skipped)
+		}
+		if failed > 0 {
+			summary += fmt.Sprintf(", %d failed (%s)", failed, firstErr)
+		}
+		return bulkDoneMsg{summary: summary, failed: failed}
+	}
+}
+
+func (m Model) renderCrossBranch() string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Switch branch in %d repos (%s)\n", len(m.cross.repos), m.cross.label))
+	b.WriteString(footerStyle.Render("Filter: " + m.cross.filter))
+	b.WriteString("\n\n")
+
+	boxW := min(m.width-4, 72)
+	contentW := boxW - 4
+	items := m.cross.filtered()
+	maxList := max(m.height-10, 3)
+	start, end, showTop, showBottom := branchWindowInfo(len(items), m.cross.cursor, maxList)
+	if showTop {
+		b.WriteString(footerStyle.Render("  ↑ more") + "\n")
+	}
+	total := len(m.cross.repos)
+	switch {
+	case m.cross.repos == nil:
+		b.WriteString(footerStyle.Render("  Loading...") + "\n")
+	case len(items) == 0:
+		b.WriteString(footerStyle.Render("  No branches") + "\n")
+	default:
+		for i, it := range items[start:end] {
+			cursor := "  "
+			if i+start == m.cross.cursor {
+				cursor = "→ "
+			}
+			counts := fmt.Sprintf("on %d · local %d · remote %d / %d", it.Current, it.Local, it.Remote, total)
+			nameW := max(contentW-2-len([]rune(counts))-2, 8)
+			name := padRight(truncate(it.Name, nameW), nameW)
+			if it.Current == total {
+				name = stagedStyle.Render(name)
+			}
+			b.WriteString(cursor + name + "  " + footerStyle.Render(counts) + "\n")
+		}
+	}
+	if showBottom {
+		b.WriteString(footerStyle.Render("  ↓ more") + "\n")
+	}
+
+	create := "off"
+	if m.cross.create {
+		create = "on"
+	}
+	b.WriteString("\nCreate from default branch where missing: " + create + "\n")
+	if it, ok := m.cross.selected(); ok {
+		b.WriteString(footerStyle.Render(crossPlanSummary(crossPlan(m.cross.repos, it.Name, m.cross.create))) + "\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(footerStyle.Render(m.hints("cross-switch", "switch", "cross-create", "create missing", "cross-cancel", "cancel")))
+	return boxStyle.Width(boxW).Render(b.String())
+}
+
+// crossPlanSummary previews a plan: "3 switch, 1 create, 1 skip, 2 stash".
+func crossPlanSummary(steps []crossStep) string {
+	counts := map[crossStepKind]int{}
+	for _, s := range steps {
+		counts[s.kind]++
+	}
+	parts := []string{fmt.Sprintf("%d switch", counts[stepLocal]+counts[stepRemote])}
+	if n := counts[stepCreate]; n > 0 {
+		parts = append(parts, fmt.Sprintf("%d create", n))
+	}
+	if n := counts[stepUnchanged]; n > 0 {
+		parts = append(parts, fmt.Sprintf("%d already on it", n))
+	}
+	if n := counts[stepMissing] + counts[stepBlocked]; n > 0 {
+		parts = append(parts, fmt.Sprintf("%d skip", n))
+	}
+	if n := dirtySteps(steps); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d stash", n))
+	}
+	return strings.Join(parts, ", ")
+}
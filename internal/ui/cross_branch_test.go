@@ -0,0 +1,198 @@
+package ui
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"rtui/internal/config"
+	"rtui/internal/git"
+)
+
+func crossRepos() []repoBranches {
+	branches := func(names ...string) map[string]bool {
+		out := map[string]bool{}
+		for _, n := range names {
+			out[n] = true
+		}
+		return out
+	}
+	return []repoBranches{
+		{repo: git.Repo{Name: "api", Path: "/src/api", Branch: "main"}, locals: branches("main", "feat"), remotes: map[string]string{"main": "origin/main", "feat": "origin/feat"}, defaultBranch: "origin/main"},
+		{repo: git.Repo{Name: "web", Path: "/src/web", Branch: "main", Modified: 1}, locals: branches("main"), remotes: map[string]string{"main": "origin/main", "feat": "origin/feat"}, defaultBranch: "origin/main"},
+		{repo: git.Repo{Name: "db", Path: "/src/db", Branch: "feat"}, locals: branches("main", "feat"), remotes: map[string]string{}, defaultBranch: "main"},
+		{repo: git.Repo{Name: "ops", Path: "/src/ops", Branch: "main"}, locals: branches("main"), remotes: map[string]string{}, defaultBranch: ""},
+	}
+}
+
+func TestCrossBranchItemsCountRepos(t *testing.T) {
+	items := crossBranchItems(crossRepos())
+	if len(items) != 2 || items[0].Name != "main" || items[1].Name != "feat" {
+		t.Fatalf("items = %+v", items)
+	}
+	if feat := items[1]; feat.Current != 1 || feat.Local != 2 || feat.Remote != 2 {
+		t.Fatalf("feat counts = %+v", feat)
+	}
+}
+
+func TestCrossPlan(t *testing.T) {
+	kinds := func(steps []crossStep) []crossStepKind {
+		out := make([]crossStepKind, len(steps))
+		for i, s := range steps {
+			out[i] = s.kind
+		}
+		return out
+	}
+	steps := crossPlan(crossRepos(), "feat", false)
+	want := []crossStepKind{stepLocal, stepRemote, stepUnchanged, stepMissing}
+	for i, k := range kinds(steps) {
+		if k != want[i] {
+			t.Fatalf("plan = %v, want %v", kinds(steps), want)
+		}
+	}
+	if steps[1].ref != "origin/feat" || dirtySteps(steps) != 1 {
+		t.Fatalf("expected web to track origin/feat and need a stash, got %+v", steps[1])
+	}
+
+	steps = crossPlan(crossRepos(), "topic", true)
+	want = []crossStepKind{stepCreate, stepCreate, stepCreate, stepMissing}
+	for i, k := range kinds(steps) {
+		if k != want[i] {
+			t.Fatalf("create plan = %v, want %v", kinds(steps), want)
+		}
+	}
+	if steps[0].ref != "origin/main" || steps[2].ref != "main" {
+		t.Fatalf("expected creation from the default branch, got %q and %q", steps[0].ref, steps[2].ref)
+	}
+	if got := crossPlanSummary(steps); got != "0 switch, 3 create, 1 skip, 1 stash" {
+		t.Fatalf("summary = %q", got)
+	}
+
+	blocked := crossRepos()
+	blocked[0].repo.State = git.StateRebasing
+	if crossPlan(blocked, "feat", false)[0].kind != stepBlocked {
+		t.Fatal("expected a repo mid-rebase to be skipped")
+	}
+}
+
+func TestCrossBranchOpensFromGroupHeader(t *testing.T) {
+	m := groupedModel()
+	m.cursor = 0
+	m2, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'b'}})
+	m = m2.(Model)
+	if m.mode != ModeCrossBranch || cmd == nil || m.cross.label != "payments" {
+		t.Fatalf("expected the cross-repo picker for payments, mode %v label %q", m.mode, m.cross.label)
+	}
+	if !strings.Contains(m.View(), "Loading...") {
+		t.Fatal("expected a loading placeholder before branches arrive")
+	}
+
+	m2, _ = m.Update(crossBranchesLoadedMsg{load: m.cross.load, repos: crossRepos()})
+	m = m2.(Model)
+	view := m.View()
+	if !strings.Contains(view, "Switch branch in 4 repos (payments)") || !strings.Contains(view, "on 1 · local 2 · remote 2 / 4") {
+		t.Fatalf("unexpected picker:\n%s", view)
+	}
+}
+
+func TestCrossBranchDropsLoadOfCancelledPicker(t *testing.T) {
+	m := groupedModel()
+	m.cursor = 0
+	m = typeKeys(t, m, "b")
+	first := m.cross.load
+	m = typeKeys(t, m, "esc")
+	m.cursor = 3
+	m = typeKeys(t, m, "b")
+	if m.mode != ModeCrossBranch || m.cross.label != "/src/work" {
+		t.Fatalf("expected the picker for /src/work, mode %v label %q", m.mode, m.cross.label)
+	}
+
+	m2, _ := m.Update(crossBranchesLoadedMsg{load: first, repos: crossRepos()})
+	if got := m2.(Model); got.cross.repos != nil || got.cross.label != "/src/work" {
+		t.Fatalf("branches loaded for payments must not fill the /src/work picker: %d repos", len(got.cross.repos))
+	}
+	m2, _ = m.Update(crossBranchesLoadedMsg{load: m.cross.load, repos: crossRepos()[:1]})
+	if got := m2.(Model); len(got.cross.repos) != 1 {
+		t.Fatalf("expected the current load to apply, got %d repos", len(got.cross.repos))
+	}
+}
+
+func TestCrossBranchDirtyUsesStashConfirm(t *testing.T) {
+	m := groupedModel()
+	m.mode = ModeCrossBranch
+	m.cross = crossBranch{label: "all repos", repos: crossRepos()}
+	m.cross.items = crossBranchItems(m.cross.repos)
+
+	m = typeKeys(t, m, "f", "e")
+	if items := m.cross.filtered(); len(items) != 1 || items[0].Name != "feat" {
+		t.Fatalf("filter = %+v", items)
+	}
+	m2, cmd := m.handleCrossBranch(tea.KeyMsg{Type: tea.KeyEnter})
+	m = m2.(Model)
+	if m.mode != ModeConfirmStash || m.cross.pending != "feat" || cmd != nil {
+		t.Fatalf("expected stash confirm for feat, mode %v pending %q", m.mode, m.cross.pending)
+	}
+	if !strings.Contains(m.renderStashConfirm(), "1 repos have uncommitted changes") {
+		t.Fatalf("unexpected confirm:\n%s", m.renderStashConfirm())
+	}
+
+	m2, _ = m.handleConfirmStash(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	m = m2.(Model)
+	if m.mode != ModeCrossBranch || m.cross.pending != "" {
+		t.Fatalf("cancel should return to the cross-repo picker, got %v", m.mode)
+	}
+
+	m2, _ = m.handleCrossBranch(tea.KeyMsg{Type: tea.KeyEnter})
+	m2, cmd = m2.(Model).handleConfirmStash(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	m = m2.(Model)
+	if m.mode != ModeNormal || cmd == nil || !strings.HasPrefix(m.statusMsg, "Stashing and switching to feat") {
+		t.Fatalf("expected the switch to start, mode %v status %q", m.mode, m.statusMsg)
+	}
+}
+
+func TestCrossBranchCreateToggle(t *testing.T) {
+	m := groupedModel()
+	m.mode = ModeCrossBranch
+	m.cross = crossBranch{label: "all repos", repos: crossRepos()}
+	m.cross.items = crossBranchItems(m.cross.repos)
+
+	m2, _ := m.handleCrossBranch(tea.KeyMsg{Type: tea.KeyTab})
+	m = m2.(Model)
+	if !m.cross.create || !strings.Contains(m.View(), "Create from default branch where missing: on") {
+		t.Fatal("tab should turn on creating missing branches")
+	}
+	m2, _ = m.handleCrossBranch(tea.KeyMsg{Type: tea.KeyEsc})
+	if m2.(Model).mode != ModeNormal {
+		t.Fatal("esc should close the picker")
+	}
+}
+
+func TestCrossBranchCreatesFromConfiguredDefault(t *testing.T) {
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main"},
+		{"-c", "user.name=t", "-c", "user.email=t@t", "commit", "-q", "--allow-empty", "-m", "init"},
+		{"branch", "develop"},
+	} {
+		if out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	cfg := config.DefaultConfig()
+	cfg.Repos = []config.RepoOverride{{Path: dir, DefaultBranch: "develop"}}
+	m := NewModel(cfg)
+	repos := []git.Repo{{Name: "r", Path: dir, Branch: "main"}}
+
+	_, cmd := m.openCrossBranch("all repos", repos)
+	msg := cmd().(crossBranchesLoadedMsg)
+	if got := msg.repos[0].defaultBranch; got != "develop" {
+		t.Fatalf("new branches should start from the configured default_branch, got %q", got)
+	}
+	m.config.Repos = nil
+	_, cmd = m.openCrossBranch("all repos", repos)
+	if got := cmd().(crossBranchesLoadedMsg).repos[0].defaultBranch; got != "main" {
+		t.Fatalf("without default_branch the detected one is used, got %q", got)
+	}
+}
@@ -0,0 +1,123 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"rtui/internal/git"
+	"rtui/internal/trash"
+)
+
+// discardRecord holds what is needed to undo one discard.
+type discardRecord struct {
+	repoPath string
+	file     git.ChangedFile
+	backup   trash.Entry
+	index    git.IndexEntry
+	inIndex  bool
+}
+
+type discardDoneMsg struct {
+	record discardRecord
+	repo   git.Repo
+}
+
+// undoFailedMsg puts a record back on the history when its undo failed,
+// e.g. because the file was edited after the discard.
+type undoFailedMsg struct {
+	record discardRecord
+	err    error
+}
+
+func discardVerb(status git.FileStatus) string {
+	switch status {
+	case git.StatusStaged:
+		return "Unstage"
+	case git.StatusUntracked:
+		return "Delete"
+	default:
+		return "Discard changes to"
+	}
+}
+
+// discardCmd backs up the file (worktree copy, or index blob for staged
+// files) before running the destructive git command.
+func (m Model) discardCmd(path string, file git.ChangedFile) tea.Cmd {
+	return func() tea.Msg {
+		record := discardRecord{repoPath: path, file: file}
+		var err error
+		if file.Status == git.StatusStaged {
+			record.index, record.inIndex, err = git.ReadIndexEntry(path, file.Path)
+			if err != nil {
+				return errMsg(err)
+			}
+			err = git.Unstage(path, file.Path)
+		} else {
+			record.backup, err = trash.Save(path, file.Path)
+			if err != nil {
+				return errMsg(err)
+			}
+			if file.Status == git.StatusUntracked {
+				err = git.RemoveUntracked(path, file.Path)
+			} else {
+				err = git.RestoreWorktree(path, file.Path)
+			}
+			if err == nil {
+				// Without a settled entry undo still works, just without
+				// the check for later edits.
+				if settled, serr := record.backup.Settle(); serr == nil {
+					record.backup = settled
+				}
+			}
+		}
+		if err != nil {
+			return errMsg(err)
+		}
+		return discardDoneMsg{record: record, repo: m.repoStatus(path)}
+	}
+}
+
+func (m Model) undoDiscardCmd(record discardRecord) tea.Cmd {
+	return func() tea.Msg {
+		var err error
+		if record.file.Status == git.StatusStaged {
+			if record.inIndex {
+				err = git.WriteIndexEntry(record.repoPath, record.file.Path, record.index)
+			}
+		} else if err = record.backup.Restore(); err == nil {
+			// The backup is back in place; it is no longer needed.
+			_ = record.backup.Remove()
+		}
+		if err != nil {
+			return undoFailedMsg{record: record, err: err}
+		}
+		return repoUpdatedMsg{repo: m.repoStatus(record.repoPath), status: "Restored " + record.file.Path}
+	}
+}
+
+// pruneTrashCmd drops backups older than trash.MaxAge, left by earlier
+// sessions whose undo history is gone.
+func pruneTrashCmd() tea.Cmd {
+	return func() tea.Msg {
+		_ = trash.Prune(trash.MaxAge)
+		return nil
+	}
+}
+
+func (m Model) handleConfirmDiscard(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch m.modalAction(ModeConfirmDiscard, msg.String()) {
+	case "discard-confirm":
+		file := m.pendingDiscard
+		m.pendingDiscard = git.ChangedFile{}
+		m.mode = ModeNormal
+		repo := m.currentRepo()
+		if repo == nil {
+			return m, nil
+		}
+		m = m.setStatusInfo(discardVerb(file.Status) + " " + file.Path + "...")
+		return m, m.discardCmd(repo.Path, file)
+	case "discard-cancel":
+		m.pendingDiscard = git.ChangedFile{}
+		m.mode = ModeNormal
+	}
+	return m, nil
+}
@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"errors"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"rtui/internal/config"
+	"rtui/internal/git"
+)
+
+func discardModel() Model {
+	m := NewModel(config.DefaultConfig())
+	m.width = 60
+	m.height = 30
+	m.repos = []git.Repo{{
+		Name: "repo",
+		Path: "/tmp/repo",
+		ChangedFiles: []git.ChangedFile{
+			{Path: "conflict.go", Status: git.StatusConflict},
+			{Path: "mod.go", Status: git.StatusModified},
+		},
+	}}
+	m.panelFocus = FocusBottom
+	return m
+}
+
+func TestDiscardAsksConfirmation(t *testing.T) {
+	m := discardModel()
+	m.changesCursor = 1
+
+	m2, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	m = m2.(Model)
+	if cmd != nil {
+		t.Fatal("expected no cmd before confirmation")
+	}
+	if m.mode != ModeConfirmDiscard || m.pendingDiscard.Path != "mod.go" {
+		t.Fatalf("expected discard confirm for mod.go, got mode=%v file=%q", m.mode, m.pendingDiscard.Path)
+	}
+
+	m2, cmd = m.handleConfirmDiscard(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	m = m2.(Model)
+	if cmd != nil || m.mode != ModeNormal {
+		t.Fatal("expected discard cancelled")
+	}
+}
+
+func TestDiscardRefusesConflict(t *testing.T) {
+	m := discardModel()
+
+	m2, _ := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	m = m2.(Model)
+	if m.mode != ModeNormal {
+		t.Fatalf("expected ModeNormal, got %v", m.mode)
+	}
+	if m.statusMsg != "Cannot discard conflict: use m to resolve" {
+		t.Fatalf("unexpected status: %q", m.statusMsg)
+	}
+}
+
+func TestUndoDiscardUsesHistory(t *testing.T) {
+	m := discardModel()
+
+	m2, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'u'}})
+	m = m2.(Model)
+	if cmd != nil || m.statusMsg != "Nothing to undo" {
+		t.Fatalf("expected nothing to undo, got %q", m.statusMsg)
+	}
+
+	record := discardRecord{repoPath: "/tmp/repo", file: git.ChangedFile{Path: "mod.go", Status: git.StatusModified}}
+	m2, _ = m.Update(discardDoneMsg{record: record, repo: m.repos[0]})
+	m = m2.(Model)
+	if len(m.discardHistory) != 1 {
+		t.Fatalf("expected 1 history entry, got %d", len(m.discardHistory))
+	}
+
+	m2, cmd = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'u'}})
+	m = m2.(Model)
+	if cmd == nil || len(m.discardHistory) != 0 {
+		t.Fatal("expected undo cmd and history popped")
+	}
+
+	m2, _ = m.Update(undoFailedMsg{record: record, err: errors.New("mod.go changed since it was discarded")})
+	m = m2.(Model)
+	if len(m.discardHistory) != 1 || m.statusKind != StatusError || m.statusMsg != "Cannot undo: mod.go changed since it was discarded" {
+		t.Fatalf("a failed undo should keep its record, got %d entries, status %q", len(m.discardHistory), m.statusMsg)
+	}
+}
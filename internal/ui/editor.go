@@ -0,0 +1,119 @@
+package ui
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"rtui/internal/config"
+	"rtui/internal/git"
+)
+
+// terminalEditors take over the terminal and must run in the foreground.
+var terminalEditors = map[string]bool{
+	"vi": true, "vim": true, "nvim": true, "nano": true, "emacs": true,
+	"micro": true, "hx": true, "helix": true, "kak": true, "joe": true, "ne": true,
+}
+
+func isTerminalEditor(editor string) bool {
+	return terminalEditors[filepath.Base(editor)]
+}
+
+// defaultLineArgs picks a goto-line template for well-known editors.
+func defaultLineArgs(editor string) []string {
+	switch filepath.Base(editor) {
+	case "code", "code-insiders", "cursor", "codium", "windsurf":
+		return []string{"--goto", "{file}:{line}"}
+	case "subl", "zed", "hx", "helix", "micro":
+		return []string{"{file}:{line}"}
+	case "idea", "goland", "pycharm", "webstorm":
+		return []string{"--line", "{line}", "{file}"}
+	}
+	if isTerminalEditor(editor) {
+		return []string{"+{line}", "{file}"}
+	}
+	return []string{"{file}"}
+}
+
+// expandLineArgs fills {file} and {line}; the file is appended when the
+// template does not mention it.
+func expandLineArgs(template []string, file string, line int) []string {
+	args := make([]string, 0, len(template)+1)
+	hasFile := false
+	for _, arg := range template {
+		if strings.Contains(arg, "{file}") {
+			hasFile = true
+		}
+		arg = strings.ReplaceAll(arg, "{file}", file)
+		arg = strings.ReplaceAll(arg, "{line}", strconv.Itoa(line))
+		args = append(args, arg)
+	}
+	if !hasFile {
+		args = append(args, file)
+	}
+	return args
+}
+
+func fileEditorArgs(cfg config.Config, file string, line int) []string {
+	template := cfg.EditorLineArgs
+	if len(template) == 0 {
+		template = defaultLineArgs(cfg.Editor)
+	}
+	args := append([]string{}, cfg.EditorArgs...)
+	return append(args, expandLineArgs(template, file, line)...)
+}
+
+// editorCmd opens cfg's editor with args. Terminal editors suspend the
+// TUI via tea.ExecProcess; GUI editors are started detached. cfg is
+// m.config, or config.ForRepo for a repo's files.
+func editorCmd(cfg config.Config, args []string, done string) tea.Cmd {
+	cmd := exec.Command(cfg.Editor, args...)
+	if isTerminalEditor(cfg.Editor) {
+		return tea.ExecProcess(cmd, func(err error) tea.Msg {
+			if err != nil {
+				return errMsg(err)
+			}
+			return statusMsg(done)
+		})
+	}
+	return func() tea.Msg {
+		if err := cmd.Start(); err != nil {
+			return errMsg(err)
+		}
+		return statusMsg(done)
+	}
+}
+
+// openPathCmd opens a repo or config file without a line position.
+func (m Model) openPathCmd(cfg config.Config, path, done string) tea.Cmd {
+	args := append(append([]string{}, cfg.EditorArgs...), path)
+	return editorCmd(cfg, args, done)
+}
+
+type openFileMsg struct {
+	repo  string
+	path  string
+	line  int
+	label string
+}
+
+// openChangeCmd resolves the first changed line off the update loop; the
+// editor itself is started when openFileMsg arrives.
+func (m Model) openChangeCmd(repo git.Repo, file git.ChangedFile) tea.Cmd {
+	return func() tea.Msg {
+		return openFileMsg{
+			repo:  repo.Path,
+			path:  filepath.Join(repo.Path, file.Path),
+			line:  git.FirstChangedLine(repo.Path, file),
+			label: file.Path,
+		}
+	}
+}
+
+func (m Model) openFileCmd(msg openFileMsg) tea.Cmd {
+	cfg := m.config.ForRepo(msg.repo)
+	return editorCmd(cfg, fileEditorArgs(cfg, msg.path, msg.line), "Opened "+msg.label+":"+strconv.Itoa(msg.line))
+}
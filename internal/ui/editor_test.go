@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"rtui/internal/config"
+	"rtui/internal/git"
+)
+
+func TestExpandLineArgs(t *testing.T) {
+	got := expandLineArgs([]string{"--goto", "{file}:{line}"}, "/r/a.go", 12)
+	want := []string{"--goto", "/r/a.go:12"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expandLineArgs() = %v, want %v", got, want)
+	}
+
+	got = expandLineArgs([]string{"+{line}"}, "/r/a.go", 3)
+	want = []string{"+3", "/r/a.go"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected file appended, got %v", got)
+	}
+}
+
+func TestDefaultLineArgs(t *testing.T) {
+	if got := defaultLineArgs("/usr/bin/nvim"); !reflect.DeepEqual(got, []string{"+{line}", "{file}"}) {
+		t.Fatalf("unexpected vim args: %v", got)
+	}
+	if got := defaultLineArgs("code"); !reflect.DeepEqual(got, []string{"--goto", "{file}:{line}"}) {
+		t.Fatalf("unexpected code args: %v", got)
+	}
+	if !isTerminalEditor("/usr/local/bin/vim") || isTerminalEditor("code") {
+		t.Fatal("unexpected terminal editor detection")
+	}
+}
+
+func TestChangesCursorOpensSelectedFile(t *testing.T) {
+	m := NewModel(config.DefaultConfig())
+	m.width = 60
+	m.height = 30
+	m.repos = []git.Repo{{
+		Name: "repo",
+		Path: "/tmp/repo",
+		ChangedFiles: []git.ChangedFile{
+			{Path: "staged.go", Status: git.StatusStaged},
+			{Path: "mod.go", Status: git.StatusModified},
+		},
+	}}
+	m.panelFocus = FocusBottom
+
+	m2, _ := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	m = m2.(Model)
+	file, ok := m.selectedChange()
+	if !ok || file.Path != "mod.go" {
+		t.Fatalf("expected mod.go selected, got %#v", file)
+	}
+
+	m2, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	m = m2.(Model)
+	if cmd == nil {
+		t.Fatal("expected open cmd")
+	}
+	if m.statusMsg != "Opening mod.go..." {
+		t.Fatalf("unexpected status: %q", m.statusMsg)
+	}
+}
+
+func TestFileEditorArgsUseRepoOverride(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Editor = "code"
+	cfg.Repos = []config.RepoOverride{{Path: "/src/notes", Editor: "nvim"}}
+
+	if got := fileEditorArgs(cfg.ForRepo("/src/notes"), "a.md", 3); !reflect.DeepEqual(got, []string{"+3", "a.md"}) {
+		t.Errorf("repo override: got %v", got)
+	}
+	if got := fileEditorArgs(cfg.ForRepo("/src/api"), "a.go", 3); !reflect.DeepEqual(got, []string{"--profile", "Minimalist", "--goto", "a.go:3"}) {
+		t.Errorf("global editor: got %v", got)
+	}
+}
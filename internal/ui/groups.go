@@ -0,0 +1,166 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"rtui/internal/config"
+	"rtui/internal/git"
+)
+
+// repoRow is one line of the repo list: a group header or a repo
+// (index into visibleRepos).
+type repoRow struct {
+	header bool
+	group  string
+	repo   int
+}
+
+type groupStats struct {
+	total  int
+	dirty  int
+	ahead  int
+	behind int
+}
+
+// configGroup returns the configured group containing the repo path.
+func (m Model) configGroup(repo git.Repo) (config.Group, bool) {
+	for _, g := range m.config.Groups {
+		for _, p := range g.Paths {
+			if isUnder(repo.Path, p) {
+				return g, true
+			}
+		}
+	}
+	return config.Group{}, false
+}
+
+// groupOf names the section a repo is listed under: its configured group,
+// else its scan root.
+func (m Model) groupOf(repo git.Repo) string {
+	if g, ok := m.configGroup(repo); ok {
+		return g.Name
+	}
+	return displayPath(repo.Root)
+}
+
+// groupOrder lists group names: configured groups, then scan roots in
+// config order, then anything else in list order.
+func (m Model) groupOrder(repos []git.Repo) []string {
+	present := map[string]bool{}
+	for _, r := range repos {
+		present[m.groupOf(r)] = true
+	}
+	var order []string
+	seen := map[string]bool{}
+	add := func(name string) {
+		if present[name] && !seen[name] {
+			seen[name] = true
+			order = append(order, name)
+		}
+	}
+	for _, g := range m.config.Groups {
+		add(g.Name)
+	}
+	for _, p := range m.config.Paths {
+		add(displayPath(p))
+	}
+	for _, r := range repos {
+		add(m.groupOf(r))
+	}
+	return order
+}
+
+// repoRows builds the list rows. Headers appear only when there is more
+// than one section or groups are configured.
+func (m Model) repoRows() []repoRow {
+	repos := m.visibleRepos()
+	order := m.groupOrder(repos)
+	if len(order) <= 1 && len(m.config.Groups) == 0 {
+		rows := make([]repoRow, len(repos))
+		for i := range repos {
+			rows[i] = repoRow{repo: i}
+		}
+		return rows
+	}
+	var rows []repoRow
+	for _, name := range order {
+		rows = append(rows, repoRow{header: true, group: name, repo: -1})
+		if m.collapsed[name] {
+			continue
+		}
+		for i, r := range repos {
+			if m.groupOf(r) == name {
+				rows = append(rows, repoRow{group: name, repo: i})
+			}
+		}
+	}
+	return rows
+}
+
+func (m Model) currentRow() (repoRow, bool) {
+	rows := m.repoRows()
+	if m.cursor < 0 || m.cursor >= len(rows) {
+		return repoRow{}, false
+	}
+	return rows[m.cursor], true
+}
+
+// currentGroup returns the group name when the cursor is on a header.
+func (m Model) currentGroup() (string, bool) {
+	row, ok := m.currentRow()
+	if !ok || !row.header {
+		return "", false
+	}
+	return row.group, true
+}
+
+func (m Model) groupRepos(name string) []git.Repo {
+	var out []git.Repo
+	for _, r := range m.visibleRepos() {
+		if m.groupOf(r) == name && !m.isPlaceholder(r) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+func statsOf(repos []git.Repo) groupStats {
+	stats := groupStats{total: len(repos)}
+	for _, r := range repos {
+		if r.IsDirty() {
+			stats.dirty++
+		}
+		stats.ahead += r.Ahead
+		stats.behind += r.Behind
+	}
+	return stats
+}
+
+func (m *Model) toggleGroup(name string) {
+	next := make(map[string]bool, len(m.collapsed)+1)
+	for k, v := range m.collapsed {
+		next[k] = v
+	}
+	next[name] = !next[name]
+	m.collapsed = next
+}
+
+func isUnder(path, root string) bool {
+	path = filepath.Clean(path)
+	root = config.NormalizePath(root)
+	return path == root || strings.HasPrefix(path, root+string(filepath.Separator))
+}
+
+// displayPath abbreviates the home directory to ~.
+func displayPath(path string) string {
+	if path == "" {
+		return ""
+	}
+	home, err := os.UserHomeDir()
+	if err == nil && home != "" && isUnder(path, home) {
+		return "~" + strings.TrimPrefix(filepath.Clean(path), filepath.Clean(home))
+	}
+	return path
+}
@@ -0,0 +1,128 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"rtui/internal/config"
+	"rtui/internal/git"
+)
+
+func groupedModel() Model {
+	cfg := config.DefaultConfig()
+	cfg.Paths = []string{"/src/work", "/src/personal"}
+	cfg.Groups = []config.Group{{Name: "payments", Paths: []string{"/src/work/pay-api", "/src/work/pay-web"}}}
+	m := NewModel(cfg)
+	m.width = 60
+	m.height = 30
+	m.repos = []git.Repo{
+		{Name: "pay-api", Path: "/src/work/pay-api", Root: "/src/work", Modified: 1},
+		{Name: "blog", Path: "/src/personal/blog", Root: "/src/personal"},
+		{Name: "infra", Path: "/src/work/infra", Root: "/src/work", Ahead: 2},
+		{Name: "pay-web", Path: "/src/work/pay-web", Root: "/src/work", Behind: 1},
+	}
+	return m
+}
+
+func TestRepoRowsGroupsInConfigOrder(t *testing.T) {
+	m := groupedModel()
+	rows := m.repoRows()
+
+	var got []string
+	for _, row := range rows {
+		if row.header {
+			got = append(got, "#"+row.group)
+			continue
+		}
+		got = append(got, m.visibleRepos()[row.repo].Name)
+	}
+	want := "#payments pay-api pay-web #/src/work infra #/src/personal blog"
+	if strings.Join(got, " ") != want {
+		t.Fatalf("rows = %q, want %q", strings.Join(got, " "), want)
+	}
+}
+
+func TestRepoRowsWithoutGroupsHaveNoHeaders(t *testing.T) {
+	m := NewModel(config.DefaultConfig())
+	m.repos = []git.Repo{{Name: "a"}, {Name: "b"}}
+	rows := m.repoRows()
+	if len(rows) != 2 || rows[0].header {
+		t.Fatalf("expected plain rows, got %#v", rows)
+	}
+	if repo := m.currentRepo(); repo == nil || repo.Name != "a" {
+		t.Fatalf("expected first repo selected, got %#v", repo)
+	}
+}
+
+func TestGroupHeaderCollapseAndStats(t *testing.T) {
+	m := groupedModel()
+	if m.currentRepo() != nil {
+		t.Fatal("expected header under cursor")
+	}
+	stats := statsOf(m.groupRepos("payments"))
+	if stats.total != 2 || stats.dirty != 1 || stats.behind != 1 {
+		t.Fatalf("unexpected stats: %#v", stats)
+	}
+
+	m2, _ := m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	m = m2.(Model)
+	if !m.collapsed["payments"] {
+		t.Fatal("expected payments collapsed")
+	}
+	if got := len(m.repoRows()); got != 5 {
+		t.Fatalf("expected 5 rows when collapsed, got %d", got)
+	}
+	if out := m.renderRepoList(); !strings.Contains(out, "▸ payments") {
+		t.Fatalf("expected collapsed marker, got:\n%s", out)
+	}
+}
+
+func TestGroupBulkPullSkipsDirty(t *testing.T) {
+	m := groupedModel()
+
+	m2, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+	m = m2.(Model)
+	if cmd == nil {
+		t.Fatal("expected bulk pull cmd")
+	}
+	if m.statusMsg != "Pulling: 1 repos in payments..." {
+		t.Fatalf("unexpected status: %q", m.statusMsg)
+	}
+
+	m2, cmd = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'P'}})
+	m = m2.(Model)
+	if cmd != nil {
+		t.Fatal("expected no push cmd when nothing is ahead")
+	}
+	if m.statusMsg != "Nothing to do in payments" {
+		t.Fatalf("unexpected status: %q", m.statusMsg)
+	}
+}
+
+func TestShellCommandUsesGroupOverride(t *testing.T) {
+	m := groupedModel()
+	m.config.Groups[0].ShellCommand = "lazygit"
+	name, _ := m.shellCommand(m.repos[0])
+	if name != "lazygit" {
+		t.Fatalf("expected group shell command, got %q", name)
+	}
+}
+
+func TestGroupBulkHonorsRepoOverrides(t *testing.T) {
+	m := groupedModel()
+	m.config.Repos = []config.RepoOverride{{Path: "/src/work/pay-web", NoAutoFetch: true}}
+	m2, _ := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}})
+	if got := m2.(Model).statusMsg; got != "Fetching: 1 repos in payments..." {
+		t.Fatalf("expected no_auto_fetch repo skipped, got %q", got)
+	}
+
+	m.repos[0] = git.Repo{Name: "pay-api", Path: "/src/work/pay-api", Root: "/src/work", Branch: "main", Upstream: "origin/main", Ahead: 1}
+	m.repos[3] = git.Repo{Name: "pay-web", Path: "/src/work/pay-web", Root: "/src/work", Branch: "feat", Upstream: "origin/feat", Ahead: 1}
+	m.config.Repos = []config.RepoOverride{{Path: "/src/work/*", Protected: []string{"main"}}}
+	m2, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'P'}})
+	if got := m2.(Model).statusMsg; got != "Pushing: 1 repos in payments..." {
+		t.Fatalf("expected protected branch skipped, got %q", got)
+	}
+}
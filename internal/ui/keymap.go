@@ -0,0 +1,285 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"rtui/internal/config"
+)
+
+// binding is a remappable key inside a modal view. Text entry keys
+// (typing, backspace) are not remappable.
+type binding struct {
+	mode ViewMode
+	id   string
+	keys []string
+}
+
+var modalBindings = []binding{
+	{ModeBranchPicker, "picker-tab", []string{"tab", "ctrl+i"}},
+	{ModeBranchPicker, "picker-local", []string{"l"}},
+	{ModeBranchPicker, "picker-remote", []string{"r"}},
+	{ModeBranchPicker, "picker-down", []string{"j", "down"}},
+	{ModeBranchPicker, "picker-up", []string{"k", "up"}},
+	{ModeBranchPicker, "picker-switch", []string{"enter"}},
+	{ModeBranchPicker, "picker-cancel", []string{"esc"}},
+
+	{ModeCrossBranch, "cross-down", []string{"down"}},
+	{ModeCrossBranch, "cross-up", []string{"up"}},
+	{ModeCrossBranch, "cross-create", []string{"tab"}},
+	{ModeCrossBranch, "cross-switch", []string{"enter"}},
+	{ModeCrossBranch, "cross-cancel", []string{"esc"}},
+
+	{ModeConfirmStash, "stash-confirm", []string{"s"}},
+	{ModeConfirmStash, "stash-cancel", []string{"c", "esc"}},
+
+	{ModeConfirmPush, "push-upstream", []string{"p"}},
+	{ModeConfirmPush, "push-force", []string{"f"}},
+	{ModeConfirmPush, "push-cancel", []string{"c", "esc"}},
+
+	{ModePushRemote, "remote-down", []string{"j", "down"}},
+	{ModePushRemote, "remote-up", []string{"k", "up"}},
+	{ModePushRemote, "remote-select", []string{"enter"}},
+	{ModePushRemote, "remote-cancel", []string{"esc"}},
+
+	{ModeConflicts, "conflict-down", []string{"j", "down"}},
+	{ModeConflicts, "conflict-up", []string{"k", "up"}},
+	{ModeConflicts, "conflict-ours", []string{"o"}},
+	{ModeConflicts, "conflict-theirs", []string{"t"}},
+	{ModeConflicts, "conflict-resolved", []string{"a"}},
+	{ModeConflicts, "conflict-edit", []string{"e"}},
+	{ModeConflicts, "conflict-mergetool", []string{"g"}},
+	{ModeConflicts, "conflict-continue", []string{"C"}},
+	{ModeConflicts, "conflict-abort", []string{"A"}},
+	{ModeConflicts, "conflict-abort-confirm", []string{"y"}},
+	{ModeConflicts, "conflict-close", []string{"esc", "q"}},
+
+	{ModeConfirmDiscard, "discard-confirm", []string{"y"}},
+	{ModeConfirmDiscard, "discard-cancel", []string{"n", "esc"}},
+
+	{ModeHelp, "help-down", []string{"j", "down"}},
+	{ModeHelp, "help-up", []string{"k", "up"}},
+	{ModeHelp, "help-page-down", []string{"pgdown"}},
+	{ModeHelp, "help-page-up", []string{"pgup"}},
+
+	{ModePaths, "paths-down", []string{"j", "down"}},
+	{ModePaths, "paths-up", []string{"k", "up"}},
+	{ModePaths, "paths-move-down", []string{"J"}},
+	{ModePaths, "paths-move-up", []string{"K"}},
+	{ModePaths, "paths-remove", []string{"x"}},
+	{ModePaths, "paths-remove-confirm", []string{"y"}},
+	{ModePaths, "paths-remove-cancel", []string{"n", "esc"}},
+	{ModePaths, "paths-depth-up", []string{"+", "="}},
+	{ModePaths, "paths-depth-down", []string{"-"}},
+	{ModePaths, "paths-validate", []string{"v"}},
+	{ModePaths, "paths-close", []string{"esc", "q"}},
+}
+
+// defaultKeymap returns the built-in bindings for every action id.
+func defaultKeymap() map[string][]string {
+	keys := map[string][]string{}
+	for _, a := range actionRegistry {
+		keys[a.id] = a.keys
+	}
+	for _, b := range modalBindings {
+		keys[b.id] = b.keys
+	}
+	return keys
+}
+
+// buildKeymap applies [keys] overrides to the defaults. Unknown action
+// ids, empty keys and keys bound to two actions in the same view are
+// errors; on error the defaults are returned.
+func buildKeymap(overrides map[string]config.KeyList) (map[string][]string, error) {
+	keys, errs := resolveKeymap(overrides)
+	if len(errs) > 0 {
+		return defaultKeymap(), errs[0]
+	}
+	return keys, nil
+}
+
+// resolveKeymap is buildKeymap reporting every problem, in id order.
+func resolveKeymap(overrides map[string]config.KeyList) (map[string][]string, []configError) {
+	defaults := defaultKeymap()
+	keys := make(map[string][]string, len(defaults))
+	for id, k := range defaults {
+		keys[id] = k
+	}
+	var errs []configError
+	ids := make([]string, 0, len(overrides))
+	for id := range overrides {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if _, ok := defaults[id]; !ok {
+			errs = append(errs, configError{"keys", id, fmt.Sprintf("unknown action %q", id), "remove it or use a remappable action id"})
+			continue
+		}
+		normalized := make([]string, 0, len(overrides[id]))
+		for _, k := range overrides[id] {
+			if k = normalizeKey(k); k != "" {
+				normalized = append(normalized, k)
+			}
+		}
+		if len(normalized) != len(overrides[id]) {
+			errs = append(errs, configError{"keys", id, fmt.Sprintf("empty key for %q", id), "remove the empty string"})
+			continue
+		}
+		keys[id] = normalized
+	}
+	for _, scope := range keyScopes() {
+		errs = append(errs, checkConflicts(scope, keys, defaults)...)
+	}
+	return keys, errs
+}
+
+// configError is a [keys] or [theme] problem found by the UI, addressed by
+// table and key so Check can point at the line.
+type configError struct {
+	table string
+	key   string
+	msg   string
+	fix   string
+}
+
+func (e configError) Error() string {
+	section, _, _ := strings.Cut(e.table, ".")
+	return section + ": " + e.msg
+}
+
+// Check lists every config problem: the ones Load found plus those that
+// need the UI's action and style tables ([keys] and [theme]).
+func Check(cfg config.Config) []config.Problem {
+	problems := append([]config.Problem(nil), cfg.Problems...)
+	_, errs := resolveKeymap(cfg.Keys)
+	errs = append(errs, themeErrors(cfg.Theme)...)
+	for _, e := range errs {
+		problems = append(problems, cfg.NewProblem(config.SeverityError, e.table, e.key, e.msg, e.fix))
+	}
+	return problems
+}
+
+// keyScopes groups action ids that are live at the same time: normal mode,
+// then each modal view.
+func keyScopes() [][]string {
+	var normal []string
+	for _, a := range actionRegistry {
+		normal = append(normal, a.id)
+	}
+	scopes := [][]string{normal}
+	index := map[ViewMode]int{}
+	for _, b := range modalBindings {
+		i, ok := index[b.mode]
+		if !ok {
+			i = len(scopes)
+			index[b.mode] = i
+			scopes = append(scopes, nil)
+		}
+		scopes[i] = append(scopes[i], b.id)
+	}
+	return scopes
+}
+
+// checkConflicts rejects a key bound to two actions of one scope, unless
+// the two already share a key by default: those pairs are split by
+// context (e.g. fetch on a repo, group-fetch on a header).
+func checkConflicts(ids []string, keys, defaults map[string][]string) []configError {
+	var errs []configError
+	owner := map[string]string{}
+	for _, id := range ids {
+		for _, k := range keys[id] {
+			other, taken := owner[k]
+			if !taken {
+				owner[k] = id
+				continue
+			}
+			if other != id && !sharesKey(defaults[other], defaults[id]) {
+				errs = append(errs, configError{"keys", id, fmt.Sprintf("%q is bound to both %s and %s", k, other, id), "bind " + id + " or " + other + " to another key"})
+			}
+		}
+	}
+	return errs
+}
+
+func sharesKey(a, b []string) bool {
+	for _, k := range a {
+		if containsString(b, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeKey maps config spellings to bubbletea key names.
+func normalizeKey(key string) string {
+	key = strings.TrimSpace(key)
+	switch strings.ToLower(key) {
+	case "space":
+		return " "
+	case "return":
+		return "enter"
+	case "escape":
+		return "esc"
+	}
+	if len(key) > 1 {
+		return strings.ToLower(key)
+	}
+	return key
+}
+
+// modalAction returns the id bound to key in a modal view, or "".
+func (m Model) modalAction(mode ViewMode, key string) string {
+	for _, b := range modalBindings {
+		if b.mode == mode && containsString(m.bindingKeys(b.id), key) {
+			return b.id
+		}
+	}
+	return ""
+}
+
+// bindingKeys returns the effective keys for an action id.
+func (m Model) bindingKeys(id string) []string {
+	if keys, ok := m.keys[id]; ok {
+		return keys
+	}
+	for _, a := range actionRegistry {
+		if a.id == id {
+			return a.keys
+		}
+	}
+	for _, b := range modalBindings {
+		if b.id == id {
+			return b.keys
+		}
+	}
+	return nil
+}
+
+// hint renders a "[k]ey label" hint for the first key bound to id,
+// inlining single-character keys into the label when they appear in it.
+func (m Model) hint(id, label string) string {
+	keys := m.bindingKeys(id)
+	if len(keys) == 0 {
+		return ""
+	}
+	key := keys[0]
+	if len([]rune(key)) == 1 && key != " " {
+		if i := strings.Index(label, key); i >= 0 {
+			return label[:i] + "[" + key + "]" + label[i+len(key):]
+		}
+	}
+	return "[" + keyLabel(key) + "] " + label
+}
+
+// hints joins hints for several (id, label) pairs, skipping unbound ones.
+func (m Model) hints(pairs ...string) string {
+	var parts []string
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if h := m.hint(pairs[i], pairs[i+1]); h != "" {
+			parts = append(parts, h)
+		}
+	}
+	return strings.Join(parts, "  ")
+}
@@ -0,0 +1,93 @@
+package ui
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"rtui/internal/config"
+	"rtui/internal/git"
+)
+
+func TestBuildKeymapRejectsUnknownAndConflicts(t *testing.T) {
+	if _, err := buildKeymap(map[string]config.KeyList{"launch": {"x"}}); err == nil || !strings.Contains(err.Error(), "unknown action") {
+		t.Fatalf("expected unknown action error, got %v", err)
+	}
+	_, err := buildKeymap(map[string]config.KeyList{"pull": {"u"}})
+	if err == nil || !strings.Contains(err.Error(), `"u" is bound to both`) {
+		t.Fatalf("expected conflict error, got %v", err)
+	}
+	if _, err := buildKeymap(map[string]config.KeyList{"stash-confirm": {"c"}}); err == nil {
+		t.Fatal("expected modal conflict with stash-cancel")
+	}
+}
+
+func TestBuildKeymapAllowsContextPairsAndOtherViews(t *testing.T) {
+	keys, err := buildKeymap(map[string]config.KeyList{
+		"fetch":         {"F"},
+		"group-fetch":   {"F"},
+		"stash-confirm": {"y"},
+		"group-toggle":  {"space"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := keys["group-toggle"]; len(got) != 1 || got[0] != " " {
+		t.Fatalf("expected space alias, got %q", got)
+	}
+}
+
+func TestRemappedKeysDriveDispatchHelpAndFooter(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Keys = map[string]config.KeyList{"pull": {"U"}, "palette": {"ctrl+k"}}
+	m := NewModel(cfg)
+	m.width = 80
+	m.height = 30
+	m.repos = []git.Repo{{Name: "api", Path: "/src/api", Modified: 1}}
+
+	if a, ok := m.actionForKey("U"); !ok || a.id != "pull" {
+		t.Fatalf("expected U to pull, got %q", a.id)
+	}
+	if a, _ := m.actionForKey("p"); a.id == "pull" {
+		t.Fatal("expected p to no longer pull")
+	}
+	m = typeKeys(t, m, "p")
+	if m.statusMsg != "" {
+		t.Fatalf("expected p to do nothing, got status %q", m.statusMsg)
+	}
+
+	tokens := map[string]footerToken{}
+	for _, tok := range m.footerTokens() {
+		tokens[tok.plain] = tok
+	}
+	if tokens["pull"].styled != footerTokenHotkey("U", "pull").styled {
+		t.Fatalf("footer pull token does not reflect remap: %q", tokens["pull"].styled)
+	}
+	if _, ok := tokens["Ctrl+Kpalette"]; !ok {
+		t.Fatalf("footer missing remapped palette: %v", tokens)
+	}
+	if help := strings.Join(m.helpLines(), "\n"); !strings.Contains(help, "Ctrl+K") || !regexp.MustCompile(`U\s+Pull\b`).MatchString(help) {
+		t.Fatalf("help does not reflect remap:\n%s", help)
+	}
+}
+
+func TestRemappedModalKeys(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Keys = map[string]config.KeyList{"discard-confirm": {"d"}, "discard-cancel": {"esc"}}
+	m := NewModel(cfg)
+	m.width = 80
+	m.mode = ModeConfirmDiscard
+	m.pendingDiscard = git.ChangedFile{Path: "a.go", Status: git.StatusModified}
+
+	if got := m.hints("discard-confirm", "yes", "discard-cancel", "no"); got != "[d] yes  [Esc] no" {
+		t.Fatalf("unexpected hints %q", got)
+	}
+	m = typeKeys(t, m, "n")
+	if m.mode != ModeConfirmDiscard {
+		t.Fatal("expected n to be unbound after remap")
+	}
+	m = typeKeys(t, m, "esc")
+	if m.mode != ModeNormal {
+		t.Fatal("expected esc to cancel")
+	}
+}
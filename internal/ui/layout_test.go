@@ -55,6 +55,49 @@ func TestCalculateLayoutNormal(t *testing.T) {
 	}
 }
 
+func TestCalculateLayoutBaseColumn(t *testing.T) {
+	if base := (Model{width: 59}).calculateLayout().Base; base != 0 {
+		t.Fatalf("expected no base column below 60, got %d", base)
+	}
+	m := Model{width: 80}
+	layout := m.calculateLayout()
+	if layout.Base != 8 {
+		t.Fatalf("expected base width 8, got %d", layout.Base)
+	}
+	line := m.renderRepoHeader(layout)
+	if !strings.Contains(line, "Base") || lipgloss.Width(line) > m.width {
+		t.Fatalf("header %q should show Base within %d columns", line, m.width)
+	}
+}
+
+func TestRepoLineDefaultBranchAndUpstreamMarkers(t *testing.T) {
+	m := NewModel(config.DefaultConfig())
+	m.width = 80
+	layout := m.calculateLayout()
+	cell := func(r git.Repo) string {
+		cols := strings.Split(m.renderRepoLine(r, false, layout), " | ")
+		return strings.TrimSpace(cols[len(cols)-2]) + " " + strings.TrimSpace(cols[len(cols)-1])
+	}
+	tests := []struct {
+		repo git.Repo
+		want string
+	}{
+		{git.Repo{Branch: "main", Upstream: "origin/main", DefaultBranch: "origin/main"}, "- ="},
+		{git.Repo{Branch: "main", Upstream: "origin/main", Behind: 2, DefaultBranch: "origin/main", DefaultBehind: 2}, "↓2 -2"},
+		{git.Repo{Branch: "feat", DefaultBranch: "origin/main", DefaultAhead: 3, DefaultBehind: 12}, "no up +3 -12"},
+		{git.Repo{Branch: "done", Upstream: "origin/done", DefaultBranch: "origin/main", DefaultBehind: 4}, "- merged"},
+		{git.Repo{Branch: "wip", Modified: 1, DefaultBranch: "origin/main", DefaultBehind: 4}, "no up +0 -4"},
+		{git.Repo{Branch: "fresh", DefaultBranch: "origin/main"}, "no up new"},
+		{git.Repo{Branch: "detached@abc1234", DefaultBranch: "origin/main"}, "- -"},
+		{git.Repo{Branch: "main"}, "no up -"},
+	}
+	for _, tt := range tests {
+		if got := cell(tt.repo); got != tt.want {
+			t.Errorf("%s: sync/base = %q, want %q", tt.repo.Branch, got, tt.want)
+		}
+	}
+}
+
 func TestFooterActionsFitWidth(t *testing.T) {
 	widths := []int{20, 30, 40, 60}
 	for _, w := range widths {
@@ -0,0 +1,100 @@
+package ui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"rtui/internal/git"
+	"rtui/internal/manifest"
+)
+
+// placeholderRepos turns manifest entries that are not cloned yet into
+// list rows. They carry no status; currentRepo skips them so no git
+// action runs on a path that does not exist.
+func placeholderRepos(m manifest.Manifest) ([]git.Repo, map[string]manifest.Entry) {
+	var repos []git.Repo
+	byPath := map[string]manifest.Entry{}
+	for _, e := range m.Uncloned() {
+		repos = append(repos, git.Repo{Name: e.Name(), Path: e.Path, Root: m.Root, Branch: e.Branch})
+		byPath[e.Path] = e
+	}
+	return repos, byPath
+}
+
+func (m Model) isPlaceholder(repo git.Repo) bool {
+	_, ok := m.placeholders[repo.Path]
+	return ok
+}
+
+// currentPlaceholder returns the manifest entry under the cursor when it
+// is a placeholder row.
+func (m Model) currentPlaceholder() (manifest.Entry, bool) {
+	row, ok := m.currentRow()
+	if !ok || row.header {
+		return manifest.Entry{}, false
+	}
+	e, ok := m.placeholders[m.visibleRepos()[row.repo].Path]
+	return e, ok
+}
+
+func onPlaceholder(m Model) bool {
+	_, ok := m.currentPlaceholder()
+	return ok
+}
+
+func (m Model) actClone() (tea.Model, tea.Cmd) {
+	e, ok := m.currentPlaceholder()
+	if !ok {
+		return m, nil
+	}
+	m = m.setStatusInfo("Cloning " + e.URL + "...")
+	return m, m.cloneCmd(e)
+}
+
+func (m Model) cloneCmd(e manifest.Entry) tea.Cmd {
+	return func() tea.Msg {
+		if err := git.Clone(e.URL, e.Path, e.Branch); err != nil {
+			return cloneDoneMsg{entry: e, err: err}
+		}
+		return cloneDoneMsg{entry: e, repo: m.repoStatus(e.Path)}
+	}
+}
+
+// applyClone replaces a cloned placeholder with the real repo and starts
+// watching it.
+func (m Model) applyClone(msg cloneDoneMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		return m.setStatusError("Clone failed: " + msg.err.Error()), nil
+	}
+	delete(m.placeholders, msg.entry.Path)
+	m.applyRepoUpdate(msg.repo)
+	m = m.setStatusInfo("Cloned " + msg.entry.Name())
+	cmds := []tea.Cmd{m.maybeLoadGraph()}
+	if m.watcher != nil {
+		cmds = append(cmds, m.watchAddReposCmd([]git.Repo{msg.repo}))
+	}
+	return m, tea.Batch(cmds...)
+}
+
+func (m Model) renderPlaceholderPanel(e manifest.Entry, maxLines int) string {
+	var b strings.Builder
+	b.WriteString(sectionTitleStyle.Render("NOT CLONED"))
+	b.WriteString("\n")
+	b.WriteString(strings.Repeat("─", m.width))
+	b.WriteString("\n")
+	lines := []string{
+		"URL:    " + e.URL,
+		"Path:   " + displayPath(e.Path),
+	}
+	if e.Branch != "" {
+		lines = append(lines, "Branch: "+e.Branch)
+	}
+	lines = append(lines, "", footerStyle.Render("Listed in the manifest and not cloned yet: "+m.hint("clone", "clone")))
+	contentMax := maxLines - 2
+	if contentMax < 1 {
+		return b.String()
+	}
+	m.writePanelLines(&b, lines[:min(len(lines), contentMax)], contentMax)
+	return b.String()
+}
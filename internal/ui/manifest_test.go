@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"rtui/internal/config"
+	"rtui/internal/git"
+	"rtui/internal/manifest"
+)
+
+func manifestModel(t *testing.T) (Model, manifest.Entry) {
+	root := t.TempDir()
+	man := manifest.Manifest{Root: root, Repos: []manifest.Entry{
+		{URL: "git@github.com:acme/web.git", Path: filepath.Join(root, "web"), Branch: "main"},
+	}}
+	cloned := git.Repo{Name: "api", Path: filepath.Join(root, "api"), Root: root, Branch: "main", Ahead: 1}
+	placeholders, entries := placeholderRepos(man)
+
+	m := NewModel(config.DefaultConfig())
+	m.width, m.height = 80, 30
+	next, _ := m.Update(reposLoadedMsg{repos: []git.Repo{cloned}, placeholders: placeholders, entries: entries})
+	return next.(Model), man.Repos[0]
+}
+
+func TestPlaceholderRowsAreNotRepos(t *testing.T) {
+	m, entry := manifestModel(t)
+	if len(m.repos) != 2 {
+		t.Fatalf("expected the clone and the placeholder, got %d repos", len(m.repos))
+	}
+	m.cursor = 1
+	if m.currentRepo() != nil {
+		t.Fatal("a placeholder must not be the current repo")
+	}
+	if e, ok := m.currentPlaceholder(); !ok || e.URL != entry.URL {
+		t.Fatalf("currentPlaceholder = %+v, %v", e, ok)
+	}
+	if a, ok := m.actionForKey("c"); !ok || a.id != "clone" {
+		t.Fatalf("c on a placeholder should clone, got %q", a.id)
+	}
+	view := m.View()
+	if !strings.Contains(view, "missing") || !strings.Contains(view, "NOT CLONED") || !strings.Contains(view, entry.URL) {
+		t.Fatalf("expected a placeholder row and panel:\n%s", view)
+	}
+	if stats := statsOf(m.groupRepos(m.groupOf(m.repos[0]))); stats.total != 1 {
+		t.Fatalf("group stats should skip placeholders, got %d", stats.total)
+	}
+
+	m.cursor = 0
+	if a, _ := m.actionForKey("c"); a.id != "commit" {
+		t.Fatalf("c on a repo should stay commit, got %q", a.id)
+	}
+}
+
+func TestCloneDoneReplacesPlaceholder(t *testing.T) {
+	m, entry := manifestModel(t)
+	m.cursor = 1
+
+	next, _ := m.Update(cloneDoneMsg{entry: entry, err: errors.New("exit status 128")})
+	m = next.(Model)
+	if m.statusKind != StatusError || !m.isPlaceholder(m.repos[1]) {
+		t.Fatalf("a failed clone keeps the placeholder, status %q", m.statusMsg)
+	}
+
+	repo := git.Repo{Name: "web", Path: entry.Path, Branch: "main"}
+	next, _ = m.Update(cloneDoneMsg{entry: entry, repo: repo})
+	m = next.(Model)
+	if m.statusMsg != "Cloned web" {
+		t.Fatalf("status = %q", m.statusMsg)
+	}
+	if cur := m.currentRepo(); cur == nil || cur.Path != entry.Path || cur.Root == "" {
+		t.Fatalf("expected the cloned repo under the cursor with its root kept, got %+v", cur)
+	}
+}
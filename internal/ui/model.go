@@ -1,13 +1,13 @@
 package ui
 
 import (
-	"os"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 
 	"rtui/internal/config"
 	"rtui/internal/git"
+	"rtui/internal/manifest"
 	"rtui/internal/watch"
 )
 
@@ -21,15 +21,40 @@ type Model struct {
 	panelFocus         PanelFocus
 	bottomView         BottomView
 	addPathInput       string
+	addPathCandidates  []string
+	addPathChoice      int
+	addPathPreview     pathPreview
 	commitMsg          string
 	filterDirty        bool
 	branchItems        []BranchItem
 	branchFilterLocal  string
 	branchFilterRemote string
 	branchCursor       int
+	helpScroll         int
 	branchTab          BranchTab
 	pendingBranch      BranchItem
+	cross              crossBranch
+	crossLoads         int
+	pendingPush        pushRequest
+	pushRemotes        []string
+	pushCursor         int
+	conflictCursor     int
+	confirmAbort       bool
+	pendingDiscard     git.ChangedFile
+	discardHistory     []discardRecord
+	collapsed          map[string]bool
+	searchQuery        string
+	searchOrigin       int
+	paletteQuery       string
+	paletteCursor      int
+	pathsCursor        int
+	confirmRemovePath  bool
+	pathIssues         map[string]string
+	problems           []config.Problem
+	bannerHidden       bool
+	keys               map[string][]string
 	changesScroll      int
+	changesCursor      int
 	graphScroll        int
 	graphLines         []string
 	loading            bool
@@ -38,17 +63,24 @@ type Model struct {
 	statusUntil        time.Time
 	err                error
 	watcher            watch.Runner
+	placeholders       map[string]manifest.Entry
 }
 
 // Messages
 type reposLoadedMsg struct {
-	repos   []git.Repo
-	usedCWD bool
-	cwd     string
+	repos        []git.Repo
+	placeholders []git.Repo
+	entries      map[string]manifest.Entry
+	manifestErr  error
+	usedCWD      bool
+	cwd          string
 }
 type errMsg error
 type statusMsg string
-type pullDoneMsg string
+type pullDoneMsg struct {
+	name   string
+	result git.PullResult
+}
 type commitDoneMsg string
 type pushDoneMsg string
 type watchEventMsg watch.Event
@@ -57,6 +89,20 @@ type branchesLoadedMsg struct {
 	items   []BranchItem
 	current string
 }
+type pathsCheckedMsg map[string]string
+type configReloadedMsg struct {
+	cfg config.Config
+	err error
+}
+type crossBranchesLoadedMsg struct {
+	load  int
+	repos []repoBranches
+}
+type cloneDoneMsg struct {
+	entry manifest.Entry
+	repo  git.Repo
+	err   error
+}
 type graphLoadedMsg struct {
 	lines []string
 	err   error
@@ -70,7 +116,15 @@ const (
 	ModeCommitInput
 	ModeBranchPicker
 	ModeConfirmStash
+	ModeConfirmPush
+	ModePushRemote
+	ModeConflicts
+	ModeConfirmDiscard
+	ModeSearch
+	ModePalette
 	ModeHelp
+	ModePaths
+	ModeCrossBranch
 )
 
 type PanelFocus int
@@ -88,17 +142,23 @@ const (
 )
 
 func NewModel(cfg config.Config) Model {
-	return Model{
-		config:      cfg,
-		cursor:      0,
-		mode:        ModeNormal,
-		panelFocus:  FocusRepos,
-		bottomView:  BottomChanges,
-		branchTab:   BranchTabLocal,
+	keys, _ := buildKeymap(cfg.Keys)
+	applyTheme(cfg.Theme, noColor())
+	m := Model{
+		keys:          keys,
+		config:        cfg,
+		cursor:        0,
+		mode:          ModeNormal,
+		panelFocus:    FocusRepos,
+		bottomView:    BottomChanges,
+		branchTab:     BranchTabLocal,
+		addPathChoice: -1,
 		changesScroll: 0,
 		graphScroll:   0,
 		statusKind:    StatusInfo,
+		problems:      Check(cfg),
 	}
+	return m
 }
 
 func (m Model) Init() tea.Cmd {
@@ -106,43 +166,58 @@ func (m Model) Init() tea.Cmd {
 		m.loadRepos(),
 		startWatcherCmd(),
 		m.statusTickCmd(),
+		pruneTrashCmd(),
 	)
 }
 
 func (m Model) loadRepos() tea.Cmd {
 	return func() tea.Msg {
-		paths := m.config.Paths
-		usedCWD := false
-		cwd := ""
-		if len(paths) == 0 {
-			if cwd, err := os.Getwd(); err == nil {
-				paths = []string{cwd}
-				usedCWD = true
-				cwd = cwd
-			}
+		paths, cwd := m.config.ScanRoots()
+		msg := reposLoadedMsg{usedCWD: cwd != "", cwd: cwd}
+		if m.config.Manifest == "" {
+			msg.repos = git.ScanReposDepths(paths, m.config.DepthFor, m.config.DefaultBranchFor)
+		} else if man, err := manifest.Load(m.config.Manifest); err != nil {
+			msg.repos = git.ScanReposDepths(paths, m.config.DepthFor, m.config.DefaultBranchFor)
+			msg.manifestErr = err
+		} else {
+			msg.repos = man.Scan(paths, m.config.DepthFor, m.config.DefaultBranchFor)
+			msg.placeholders, msg.entries = placeholderRepos(man)
 		}
-		repos := git.ScanRepos(paths, m.config.ScanDepth)
-		return reposLoadedMsg{repos: repos, usedCWD: usedCWD, cwd: cwd}
+		return msg
 	}
 }
 
+// repoStatus reads one repo's status, compared with its configured
+// default_branch or, without one, the detected default branch.
+func (m Model) repoStatus(path string) git.Repo {
+	return git.GetRepoStatusAgainst(path, m.config.DefaultBranchFor(path))
+}
+
+// visibleRepos is the list as shown: without repos hidden by a [[repo]]
+// table and, when filtering, without clean ones.
 func (m Model) visibleRepos() []git.Repo {
-	if !m.filterDirty {
+	if !m.filterDirty && len(m.config.Repos) == 0 {
 		return m.repos
 	}
 	var result []git.Repo
 	for _, r := range m.repos {
-		if r.IsDirty() {
+		if (!m.filterDirty || r.IsDirty()) && !m.config.RepoSettings(r.Path).Hidden {
 			result = append(result, r)
 		}
 	}
 	return result
 }
 
+// currentRepo returns the repo under the cursor, or nil on a group header
+// or a manifest placeholder.
 func (m Model) currentRepo() *git.Repo {
+	row, ok := m.currentRow()
+	if !ok || row.header {
+		return nil
+	}
 	repos := m.visibleRepos()
-	if len(repos) == 0 || m.cursor < 0 || m.cursor >= len(repos) {
+	if m.isPlaceholder(repos[row.repo]) {
 		return nil
 	}
-	return &repos[m.cursor]
+	return &repos[row.repo]
 }
@@ -0,0 +1,166 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// repoRowsTop is the first screen line of repo rows: section header,
+	// rule, column header, rule.
+	repoRowsTop = 4
+	// wheelLines is how far one wheel notch scrolls the bottom panel.
+	wheelLines = 3
+)
+
+// handleMouse maps clicks and wheel events onto the normal-mode layout.
+// Modal views stay keyboard-only.
+func (m Model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if m.mode != ModeNormal {
+		return m, nil
+	}
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		return m.mouseWheel(msg.Y, -1)
+	case tea.MouseButtonWheelDown:
+		return m.mouseWheel(msg.Y, 1)
+	case tea.MouseButtonLeft:
+		if msg.Action == tea.MouseActionPress {
+			return m.mouseClick(msg.X, msg.Y)
+		}
+	}
+	return m, nil
+}
+
+func (m Model) mouseClick(x, y int) (tea.Model, tea.Cmd) {
+	if id, ok := m.footerActionAt(x, y); ok {
+		// Dispatch through the token's key so the click resolves by
+		// context like the key does (pull on a group header pulls the
+		// group).
+		if keys := m.bindingKeys(id); len(keys) > 0 {
+			if a, ok := m.actionForKey(keys[0]); ok {
+				return a.run(m)
+			}
+		}
+		return m, nil
+	}
+	if y -= m.bannerLines(); y < 0 {
+		return m, nil
+	}
+	if top, ok := m.bottomPanelTop(); ok && y >= top {
+		if y >= top+m.bottomPanelMaxLines() {
+			return m, nil
+		}
+		m.panelFocus = FocusBottom
+		if line := y - top - 2; line >= 0 {
+			m.selectChangeLine(line)
+		}
+		return m, nil
+	}
+	if y >= m.repoListLineCount() {
+		return m, nil
+	}
+	m.panelFocus = FocusRepos
+	index, ok := m.repoRowAt(y)
+	if !ok {
+		return m, nil
+	}
+	if index == m.cursor {
+		if group, ok := m.currentGroup(); ok {
+			m.toggleGroup(group)
+		}
+		return m, nil
+	}
+	m.cursor = index
+	m.resetBottomScroll()
+	return m, m.maybeLoadGraph()
+}
+
+// mouseWheel scrolls the panel under the pointer without moving focus.
+func (m Model) mouseWheel(y, delta int) (tea.Model, tea.Cmd) {
+	if y -= m.bannerLines(); y < 0 {
+		return m, nil
+	}
+	if top, ok := m.bottomPanelTop(); ok && y >= top {
+		m.scrollBottom(delta * wheelLines)
+		return m, nil
+	}
+	if y >= m.repoListLineCount() {
+		return m, nil
+	}
+	next := clamp(m.cursor+delta, 0, max(len(m.repoRows())-1, 0))
+	if next == m.cursor {
+		return m, nil
+	}
+	m.cursor = next
+	m.resetBottomScroll()
+	return m, m.maybeLoadGraph()
+}
+
+// repoRowAt returns the repo row index drawn on screen line y.
+func (m Model) repoRowAt(y int) (int, bool) {
+	rows := m.repoRows()
+	start, end := m.repoWindow(len(rows))
+	index := start + y - repoRowsTop
+	if y < repoRowsTop || index >= end {
+		return 0, false
+	}
+	return index, true
+}
+
+// bottomPanelTop returns the screen line of the bottom panel header, when
+// the panel is drawn.
+func (m Model) bottomPanelTop() (int, bool) {
+	if len(m.visibleRepos()) == 0 || m.bottomPanelMaxLines() < 3 {
+		return 0, false
+	}
+	return m.repoListLineCount() + 1, true
+}
+
+// selectChangeLine moves the CHANGES cursor to the file drawn on content
+// line `line` of the panel. Section titles and blank lines are ignored.
+func (m *Model) selectChangeLine(line int) {
+	if m.bottomView != BottomChanges {
+		return
+	}
+	repo := m.currentRepo()
+	if repo == nil {
+		return
+	}
+	lines, entryLines := m.changesLines(*repo)
+	scroll := clamp(m.changesScroll, 0, maxScroll(len(lines), m.bottomListMaxLines()))
+	for i, l := range entryLines {
+		if l == scroll+line {
+			m.changesCursor = i
+			m.changesScroll = scroll
+			return
+		}
+	}
+}
+
+// footerActionAt returns the action of the footer token under (x, y),
+// following the same wrapping as footerActions.
+func (m Model) footerActionAt(x, y int) (string, bool) {
+	if m.mode == ModeSearch {
+		return "", false
+	}
+	var lines [][]footerToken
+	for _, line := range wrapFooterTokens(m.footerTokens(), m.width, 2, footerGap) {
+		if len(line) > 0 {
+			lines = append(lines, line)
+		}
+	}
+	row := y - (m.height - len(lines))
+	if row < 0 || row >= len(lines) {
+		return "", false
+	}
+	left := 0
+	for _, tok := range lines[row] {
+		right := left + lipgloss.Width(tok.plain)
+		if x >= left && x < right {
+			return tok.action, tok.action != ""
+		}
+		left = right + footerGap
+	}
+	return "", false
+}
@@ -0,0 +1,158 @@
+package ui
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"rtui/internal/config"
+	"rtui/internal/git"
+)
+
+var ansiSeq = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// screenPos finds text in the rendered view and returns its cell position.
+func screenPos(t *testing.T, m Model, text string) (x, y int) {
+	t.Helper()
+	for y, line := range strings.Split(ansiSeq.ReplaceAllString(m.View(), ""), "\n") {
+		if i := strings.Index(line, text); i >= 0 {
+			return len([]rune(line[:i])), y
+		}
+	}
+	t.Fatalf("%q not on screen:\n%s", text, m.View())
+	return 0, 0
+}
+
+func click(m Model, x, y int) (Model, tea.Cmd) {
+	next, cmd := m.Update(tea.MouseMsg{X: x, Y: y, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+	return next.(Model), cmd
+}
+
+func wheel(m Model, y int, button tea.MouseButton) Model {
+	next, _ := m.Update(tea.MouseMsg{Y: y, Button: button, Action: tea.MouseActionPress})
+	return next.(Model)
+}
+
+func TestClickSelectsRepoAndFocusesList(t *testing.T) {
+	m := searchModel()
+	m.panelFocus = FocusBottom
+	x, y := screenPos(t, m, "infra")
+	m, _ = click(m, x, y)
+	if repo := m.currentRepo(); repo == nil || repo.Name != "infra" {
+		t.Fatalf("current repo = %v, want infra", repo)
+	}
+	if m.panelFocus != FocusRepos {
+		t.Fatal("click on the list should focus it")
+	}
+}
+
+func TestClickAccountsForConfigBanner(t *testing.T) {
+	m := searchModel()
+	m.problems = []config.Problem{{Severity: config.SeverityWarning, Message: "editor not found"}}
+	x, y := screenPos(t, m, "infra")
+	m, _ = click(m, x, y)
+	if repo := m.currentRepo(); repo == nil || repo.Name != "infra" {
+		t.Fatalf("current repo = %v, want infra", repo)
+	}
+}
+
+func TestClickSelectedGroupHeaderToggles(t *testing.T) {
+	m := groupedModel()
+	m.cursor = 1
+	_, y := screenPos(t, m, "payments")
+	m, _ = click(m, 0, y)
+	if m.collapsed["payments"] {
+		t.Fatal("first click should only select the header")
+	}
+	m, _ = click(m, 0, y)
+	if !m.collapsed["payments"] {
+		t.Fatal("second click should collapse the group")
+	}
+}
+
+func TestClickChangesSelectsFile(t *testing.T) {
+	m := searchModel()
+	m.repos[0].ChangedFiles = []git.ChangedFile{
+		{Path: "a.go", Status: git.StatusModified},
+		{Path: "b.go", Status: git.StatusModified},
+	}
+	x, y := screenPos(t, m, "b.go")
+	m, _ = click(m, x, y)
+	if m.panelFocus != FocusBottom {
+		t.Fatal("click in CHANGES should focus the bottom panel")
+	}
+	if f, ok := m.selectedChange(); !ok || f.Path != "b.go" {
+		t.Fatalf("selected change = %v, want b.go", f)
+	}
+}
+
+func TestWheelScrollsGraphWithoutFocus(t *testing.T) {
+	m := searchModel()
+	m.bottomView = BottomGraph
+	for i := 0; i < 40; i++ {
+		m.graphLines = append(m.graphLines, "* commit")
+	}
+	_, y := screenPos(t, m, "GRAPH")
+	m = wheel(m, y+3, tea.MouseButtonWheelDown)
+	if m.graphScroll != wheelLines {
+		t.Fatalf("graphScroll = %d, want %d", m.graphScroll, wheelLines)
+	}
+	m = wheel(m, y+3, tea.MouseButtonWheelUp)
+	if m.graphScroll != 0 {
+		t.Fatalf("graphScroll = %d after wheel up, want 0", m.graphScroll)
+	}
+	if m.panelFocus != FocusRepos {
+		t.Fatal("wheel should not move focus")
+	}
+}
+
+func TestWheelOverListMovesCursor(t *testing.T) {
+	m := searchModel()
+	_, y := screenPos(t, m, "api")
+	m = wheel(m, y, tea.MouseButtonWheelDown)
+	if m.cursor != 1 {
+		t.Fatalf("cursor = %d, want 1", m.cursor)
+	}
+}
+
+func TestClickFooterRunsAction(t *testing.T) {
+	m := searchModel()
+	x, y := screenPos(t, m, "search")
+	m, _ = click(m, x+1, y)
+	if m.mode != ModeSearch {
+		t.Fatalf("mode = %v, want search", m.mode)
+	}
+}
+
+func TestClickFooterOnGroupHeaderMatchesKey(t *testing.T) {
+	m := groupedModel()
+	m.width = 120
+	m.cursor = 0
+	keyed, keyCmd := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+	lines := strings.Split(ansiSeq.ReplaceAllString(m.View(), ""), "\n")
+	y := len(lines) - 1
+	for ; y >= 0 && !strings.Contains(lines[y], "pull"); y-- {
+	}
+	if y < 0 {
+		t.Fatalf("no pull token in the footer:\n%s", m.View())
+	}
+	clicked, clickCmd := click(m, len([]rune(lines[y][:strings.Index(lines[y], "pull")]))+1, y)
+	if want := keyed.(Model).statusMsg; clicked.statusMsg != want || (clickCmd == nil) != (keyCmd == nil) {
+		t.Fatalf("clicking pull on a group header should run group-pull like p: status %q, want %q", clicked.statusMsg, want)
+	}
+	if !strings.Contains(clicked.statusMsg, "in payments") {
+		t.Fatalf("expected a group pull, got %q", clicked.statusMsg)
+	}
+}
+
+func TestMouseIgnoredInModalViews(t *testing.T) {
+	m := searchModel()
+	m.mode = ModeHelp
+	x, y := screenPos(t, searchModel(), "infra")
+	m, _ = click(m, x, y)
+	if m.cursor != 0 || m.mode != ModeHelp {
+		t.Fatal("clicks should not reach the list behind a modal view")
+	}
+}
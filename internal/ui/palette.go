@@ -0,0 +1,154 @@
+package ui
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// paletteItem is an action offered in the palette with its match offsets
+// into desc.
+type paletteItem struct {
+	action  action
+	matches []int
+}
+
+func (m Model) openPalette() Model {
+	m.mode = ModePalette
+	m.paletteQuery = ""
+	m.paletteCursor = 0
+	return m
+}
+
+// paletteItems lists the actions valid for the current repo and focus,
+// filtered by the palette query against description, id and keys. Tighter
+// description matches sort first.
+func (m Model) paletteItems() []paletteItem {
+	var out []paletteItem
+	for _, a := range m.availableActions() {
+		if a.id == "palette" {
+			continue
+		}
+		if m.paletteQuery == "" {
+			out = append(out, paletteItem{action: a})
+			continue
+		}
+		if pos, ok := fuzzyMatch(a.desc, m.paletteQuery); ok {
+			out = append(out, paletteItem{action: a, matches: pos})
+			continue
+		}
+		if _, ok := fuzzyMatch(a.id+" "+m.keysLabel(a), m.paletteQuery); ok {
+			out = append(out, paletteItem{action: a})
+		}
+	}
+	if m.paletteQuery != "" {
+		sort.SliceStable(out, func(i, j int) bool {
+			return matchSpan(out[i].matches) < matchSpan(out[j].matches)
+		})
+	}
+	return out
+}
+
+// matchSpan scores a match by how spread out it is; no match scores worst.
+func matchSpan(positions []int) int {
+	if len(positions) == 0 {
+		return math.MaxInt
+	}
+	return positions[len(positions)-1] - positions[0] + positions[0]/4
+}
+
+func (m Model) handlePalette(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.mode = ModeNormal
+		m.paletteQuery = ""
+		return m, nil
+	case "enter":
+		items := m.paletteItems()
+		if m.paletteCursor < 0 || m.paletteCursor >= len(items) {
+			return m, nil
+		}
+		m.mode = ModeNormal
+		m.paletteQuery = ""
+		return items[m.paletteCursor].action.run(m)
+	case "down", "ctrl+n":
+		if m.paletteCursor < len(m.paletteItems())-1 {
+			m.paletteCursor++
+		}
+	case "up", "ctrl+p":
+		if m.paletteCursor > 0 {
+			m.paletteCursor--
+		}
+	case "backspace":
+		if m.paletteQuery != "" {
+			_, size := utf8.DecodeLastRuneInString(m.paletteQuery)
+			m.paletteQuery = m.paletteQuery[:len(m.paletteQuery)-size]
+			m.paletteCursor = 0
+		}
+	default:
+		if msg.Type == tea.KeyRunes {
+			m.paletteQuery += stripNewlines(string(msg.Runes))
+			m.paletteCursor = 0
+		}
+	}
+	return m, nil
+}
+
+func (m Model) renderPalette() string {
+	var b strings.Builder
+	title := "Commands"
+	if repo := m.currentRepo(); repo != nil {
+		title += " · " + repo.Name
+	} else if group, ok := m.currentGroup(); ok {
+		title += " · " + group
+	}
+	b.WriteString(title + "\n")
+	b.WriteString(footerStyle.Render("> ") + m.paletteQuery + "█")
+	b.WriteString("\n\n")
+
+	boxW := min(m.width-4, 60)
+	contentW := boxW - 4
+
+	items := m.paletteItems()
+	maxList := m.height - 8
+	if maxList < 3 {
+		maxList = 3
+	}
+	start, end, showTop, showBottom := branchWindowInfo(len(items), m.paletteCursor, maxList)
+
+	if showTop {
+		b.WriteString(footerStyle.Render("  ↑ more"))
+		b.WriteString("\n")
+	}
+	if len(items) == 0 {
+		b.WriteString(footerStyle.Render("  No matching commands"))
+		b.WriteString("\n")
+	}
+	for i := start; i < end; i++ {
+		item := items[i]
+		cursor := "  "
+		base := lipgloss.NewStyle()
+		if i == m.paletteCursor {
+			cursor = "→ "
+			base = selectedRepoStyle
+		}
+		keys := m.keysLabel(item.action)
+		descW := max(contentW-2-lipgloss.Width(keys)-1, 1)
+		desc := highlightMatches(truncate(item.action.desc, descW), item.action.desc, item.matches, base)
+		gap := max(contentW-2-lipgloss.Width(desc)-lipgloss.Width(keys), 1)
+		b.WriteString(cursor + desc + strings.Repeat(" ", gap) + hotkeyStyle.Render(keys) + "\n")
+	}
+	if showBottom {
+		b.WriteString(footerStyle.Render("  ↓ more"))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(footerStyle.Render("[Enter] run  [↑/↓] select  [Esc] cancel"))
+
+	return boxStyle.Width(boxW).Render(b.String())
+}
@@ -1,6 +1,10 @@
 package ui
 
-import tea "github.com/charmbracelet/bubbletea"
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"rtui/internal/git"
+)
 
 func maxScroll(total, window int) int {
 	if window <= 0 || total <= window {
@@ -11,6 +15,7 @@ func maxScroll(total, window int) int {
 
 func (m *Model) resetBottomScroll() {
 	m.changesScroll = 0
+	m.changesCursor = 0
 	m.graphScroll = 0
 }
 
@@ -53,7 +58,45 @@ func (m *Model) scrollBottom(delta int) {
 		m.graphScroll = clamp(m.graphScroll+delta, 0, maxScroll(len(m.graphLines), window))
 		return
 	}
-	m.changesScroll = clamp(m.changesScroll+delta, 0, maxScroll(m.changesTotalLines(), window))
+	m.moveChangesCursor(delta, window)
+}
+
+// moveChangesCursor moves the file cursor in CHANGES and scrolls so the
+// selected file (and its section header at the top) stays visible.
+func (m *Model) moveChangesCursor(delta, window int) {
+	repo := m.currentRepo()
+	if repo == nil {
+		return
+	}
+	lines, entryLines := m.changesLines(*repo)
+	if len(entryLines) == 0 {
+		m.changesCursor = 0
+		m.changesScroll = clamp(m.changesScroll+delta, 0, maxScroll(len(lines), window))
+		return
+	}
+	m.changesCursor = clamp(m.changesCursor+delta, 0, len(entryLines)-1)
+	line := entryLines[m.changesCursor]
+	if m.changesCursor == 0 {
+		line = 0
+	}
+	if line < m.changesScroll {
+		m.changesScroll = line
+	} else if line >= m.changesScroll+window {
+		m.changesScroll = line - window + 1
+	}
+	m.changesScroll = clamp(m.changesScroll, 0, maxScroll(len(lines), window))
+}
+
+func (m Model) selectedChange() (git.ChangedFile, bool) {
+	repo := m.currentRepo()
+	if repo == nil {
+		return git.ChangedFile{}, false
+	}
+	entries := changeEntries(*repo)
+	if m.changesCursor < 0 || m.changesCursor >= len(entries) {
+		return git.ChangedFile{}, false
+	}
+	return entries[m.changesCursor], true
 }
 
 func clamp(v, minV, maxV int) int {
@@ -0,0 +1,232 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"rtui/internal/config"
+	"rtui/internal/git"
+)
+
+// maxPathDepth caps the per-path depth the paths screen can set.
+const maxPathDepth = 10
+
+func (m Model) actPaths() (tea.Model, tea.Cmd) {
+	m.mode = ModePaths
+	m.pathsCursor = 0
+	m.confirmRemovePath = false
+	m.pathIssues = nil
+	return m, nil
+}
+
+func (m Model) selectedPath() (string, bool) {
+	if m.pathsCursor < 0 || m.pathsCursor >= len(m.config.Paths) {
+		return "", false
+	}
+	return m.config.Paths[m.pathsCursor], true
+}
+
+// pathRepoCount counts loaded repos found under root.
+func (m Model) pathRepoCount(root string) int {
+	count := 0
+	for _, repo := range m.repos {
+		if repo.Root == root {
+			count++
+		}
+	}
+	return count
+}
+
+func (m Model) handlePaths(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.confirmRemovePath {
+		// The cancel keys share esc with paths-close, so they are looked
+		// up directly rather than through modalAction. Other keys leave
+		// the question open.
+		key := msg.String()
+		switch {
+		case containsString(m.bindingKeys("paths-remove-confirm"), key):
+			m.confirmRemovePath = false
+			path, ok := m.selectedPath()
+			if !ok {
+				return m, nil
+			}
+			return m.savePaths(config.RemovePath(&m.config, path), "Removed "+path)
+		case containsString(m.bindingKeys("paths-remove-cancel"), key):
+			m.confirmRemovePath = false
+		}
+		return m, nil
+	}
+
+	action := m.modalAction(ModePaths, msg.String())
+	switch action {
+	case "paths-close":
+		m.mode = ModeNormal
+		return m, nil
+	case "paths-down":
+		if m.pathsCursor < len(m.config.Paths)-1 {
+			m.pathsCursor++
+		}
+		return m, nil
+	case "paths-up":
+		if m.pathsCursor > 0 {
+			m.pathsCursor--
+		}
+		return m, nil
+	case "paths-validate":
+		m = m.setStatusInfo("Checking paths...")
+		return m, m.checkPathsCmd()
+	}
+
+	path, ok := m.selectedPath()
+	if !ok {
+		return m, nil
+	}
+	switch action {
+	case "paths-remove":
+		m.confirmRemovePath = true
+		return m, nil
+	case "paths-move-down", "paths-move-up":
+		delta := 1
+		if action == "paths-move-up" {
+			delta = -1
+		}
+		target := m.pathsCursor + delta
+		if target < 0 || target >= len(m.config.Paths) {
+			return m, nil
+		}
+		err := config.MovePath(&m.config, path, delta)
+		if err == nil {
+			m.pathsCursor = target
+		}
+		return m.savePaths(err, "")
+	case "paths-depth-up", "paths-depth-down":
+		depth := m.config.DepthFor(path)
+		if action == "paths-depth-up" {
+			depth++
+		} else {
+			depth--
+		}
+		if depth < 0 || depth > maxPathDepth {
+			return m, nil
+		}
+		return m.savePaths(config.SetPathDepth(&m.config, path, depth), fmt.Sprintf("Depth %d for %s", depth, path))
+	}
+	return m, nil
+}
+
+// savePaths reports the result of a config edit and rescans on success.
+// Validation results are dropped because they no longer match.
+func (m Model) savePaths(err error, status string) (tea.Model, tea.Cmd) {
+	if err != nil {
+		return m, func() tea.Msg { return errMsg(err) }
+	}
+	m.pathsCursor = clamp(m.pathsCursor, 0, max(len(m.config.Paths)-1, 0))
+	m.pathIssues = nil
+	if status != "" {
+		m = m.setStatusInfo(status)
+	}
+	return m, m.loadRepos()
+}
+
+// checkPathsCmd validates every configured path off the UI goroutine.
+func (m Model) checkPathsCmd() tea.Cmd {
+	cfg := m.config
+	return func() tea.Msg {
+		return pathsCheckedMsg(checkPaths(cfg))
+	}
+}
+
+// checkPaths reports, per path, why it would not contribute repos: it is
+// missing, not a directory, already inside another root, or has no repos
+// within its depth. Paths without problems are absent from the result.
+func checkPaths(cfg config.Config) map[string]string {
+	issues := map[string]string{}
+	for _, path := range cfg.Paths {
+		info, err := os.Stat(path)
+		switch {
+		case err != nil:
+			issues[path] = "missing"
+		case !info.IsDir():
+			issues[path] = "not a directory"
+		default:
+			if parent, ok := coveringRoot(cfg.Paths, path); ok {
+				issues[path] = "inside " + parent
+			} else if len(git.FindRepos(path, cfg.DepthFor(path))) == 0 {
+				issues[path] = fmt.Sprintf("no repos at depth %d", cfg.DepthFor(path))
+			}
+		}
+	}
+	return issues
+}
+
+// coveringRoot returns another root that path lies under.
+func coveringRoot(roots []string, path string) (string, bool) {
+	for _, root := range roots {
+		if root == path {
+			continue
+		}
+		rel, err := filepath.Rel(root, path)
+		if err == nil && rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return root, true
+		}
+	}
+	return "", false
+}
+
+func (m Model) renderPaths() string {
+	var b strings.Builder
+	b.WriteString(sectionTitleStyle.Render("Scan paths"))
+	b.WriteString(fmt.Sprintf("  (%d)\n\n", len(m.config.Paths)))
+
+	boxW := min(m.width-4, 60)
+	contentW := boxW - 4
+	paths := m.config.Paths
+	maxList := max((m.height-12)/2, 3)
+	start, end, showTop, showBottom := branchWindowInfo(len(paths), m.pathsCursor, maxList)
+	if showTop {
+		b.WriteString(footerStyle.Render("  ↑ more"))
+		b.WriteString("\n")
+	}
+	if len(paths) == 0 {
+		b.WriteString(footerStyle.Render("  No scan paths configured"))
+		b.WriteString("\n")
+	}
+	for i := start; i < end; i++ {
+		path := paths[i]
+		cursor := "  "
+		style := dirtyRepoStyle
+		if i == m.pathsCursor {
+			cursor = "→ "
+			style = selectedRepoStyle
+		}
+		info := fmt.Sprintf("%d repos · depth %d", m.pathRepoCount(path), m.config.DepthFor(path))
+		if m.config.Included(path) {
+			info = "included · " + info
+		}
+		pathW := max(contentW-2-len([]rune(info))-1, 4)
+		name := truncatePath(path, pathW)
+		gap := max(contentW-2-len([]rune(name))-len([]rune(info)), 1)
+		b.WriteString(cursor + style.Render(name) + strings.Repeat(" ", gap) + footerStyle.Render(info) + "\n")
+		if issue, ok := m.pathIssues[path]; ok {
+			b.WriteString("    " + conflictStyle.Render(truncate(issue, contentW-4)) + "\n")
+		}
+	}
+	if showBottom {
+		b.WriteString(footerStyle.Render("  ↓ more"))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	if path, ok := m.selectedPath(); ok && m.confirmRemovePath {
+		b.WriteString(conflictStyle.Render("Remove " + truncatePath(path, contentW-20) + "? " + m.hints("paths-remove-confirm", "yes", "paths-remove-cancel", "no")))
+	} else {
+		b.WriteString(footerStyle.Render(m.hints("paths-remove", "remove", "paths-move-up", "move up", "paths-move-down", "move down")))
+		b.WriteString("\n")
+		b.WriteString(footerStyle.Render(m.hints("paths-depth-up", "depth+", "paths-depth-down", "depth-", "paths-validate", "validate", "paths-close", "close")))
+	}
+	return boxStyle.Width(boxW).Render(b.String())
+}
@@ -0,0 +1,159 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"rtui/internal/config"
+	"rtui/internal/git"
+)
+
+func pathsModel(t *testing.T) (Model, []string) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	var paths []string
+	for _, name := range []string{"work", "personal", "oss"} {
+		dir := filepath.Join(home, name)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, dir)
+	}
+	cfg := config.DefaultConfig()
+	cfg.Paths = append([]string(nil), paths...)
+	m := NewModel(cfg)
+	m.width = 80
+	m.height = 30
+	m.repos = []git.Repo{
+		{Name: "api", Path: filepath.Join(paths[0], "api"), Root: paths[0]},
+		{Name: "web", Path: filepath.Join(paths[0], "web"), Root: paths[0]},
+	}
+	next, _ := m.actPaths()
+	return next.(Model), paths
+}
+
+func TestPathsScreenReorderAndDepth(t *testing.T) {
+	m, paths := pathsModel(t)
+	if !strings.Contains(m.renderPaths(), "2 repos · depth 1") {
+		t.Fatalf("expected repo count for first path:\n%s", m.renderPaths())
+	}
+	m = typeKeys(t, m, "J", "+", "+")
+	if m.config.Paths[1] != paths[0] || m.pathsCursor != 1 {
+		t.Fatalf("paths = %v, cursor %d", m.config.Paths, m.pathsCursor)
+	}
+	if m.config.DepthFor(paths[0]) != 3 {
+		t.Fatalf("depth = %d, want 3", m.config.DepthFor(paths[0]))
+	}
+	loaded, err := config.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if loaded.Paths[1] != paths[0] || loaded.DepthFor(paths[0]) != 3 {
+		t.Fatalf("not saved: %v %v", loaded.Paths, loaded.PathDepths)
+	}
+}
+
+func TestPathsScreenRemoveNeedsConfirm(t *testing.T) {
+	m, paths := pathsModel(t)
+	m = typeKeys(t, m, "x", "n")
+	if len(m.config.Paths) != 3 {
+		t.Fatal("remove should wait for confirmation")
+	}
+	m = typeKeys(t, m, "x", "j")
+	if !m.confirmRemovePath || m.pathsCursor != 0 {
+		t.Fatal("other keys should leave the question open")
+	}
+	if !strings.Contains(m.renderPaths(), "[y]es  [n]o") {
+		t.Fatalf("expected the confirm hints:\n%s", m.renderPaths())
+	}
+	m = typeKeys(t, m, "esc")
+	if m.confirmRemovePath || m.mode != ModePaths {
+		t.Fatal("esc should cancel the remove, not close the screen")
+	}
+	m = typeKeys(t, m, "x", "y")
+	if len(m.config.Paths) != 2 || m.config.Paths[0] != paths[1] {
+		t.Fatalf("paths = %v", m.config.Paths)
+	}
+}
+
+func TestPathsRemoveHintsFollowRemappedKeys(t *testing.T) {
+	m, _ := pathsModel(t)
+	m.keys = map[string][]string{"paths-remove-cancel": {"c"}}
+	m = typeKeys(t, m, "x")
+	if !strings.Contains(m.renderPaths(), "[y]es  [c] no") {
+		t.Fatalf("expected the remapped cancel key:\n%s", m.renderPaths())
+	}
+	m = typeKeys(t, m, "c")
+	if m.confirmRemovePath || len(m.config.Paths) != 3 {
+		t.Fatal("the remapped key should cancel")
+	}
+}
+
+func TestCoveringRootKeepsDotDotNamedChildren(t *testing.T) {
+	roots := []string{"/src", "/src/..old"}
+	if root, ok := coveringRoot(roots, "/src/..old"); !ok || root != "/src" {
+		t.Fatalf("expected /src to cover /src/..old, got %q %v", root, ok)
+	}
+	if _, ok := coveringRoot([]string{"/src/a"}, "/src/b"); ok {
+		t.Fatal("a sibling is not covered")
+	}
+}
+
+func TestPathsScreenKeepsConfigWhenSaveFails(t *testing.T) {
+	m, paths := pathsModel(t)
+	// a directory where the config file should be cannot be written
+	if err := os.MkdirAll(config.ConfigPath(), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, keys := range [][]string{{"J"}, {"+"}, {"x", "y"}} {
+		for _, k := range keys[:len(keys)-1] {
+			m = typeKeys(t, m, k)
+		}
+		updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(keys[len(keys)-1])})
+		m = updated.(Model)
+		if cmd == nil {
+			t.Fatalf("%v: expected an error", keys)
+		}
+		if _, ok := cmd().(errMsg); !ok {
+			t.Fatalf("%v: expected an error, got %#v", keys, cmd())
+		}
+		if !slices.Equal(m.config.Paths, paths) || len(m.config.PathDepths) != 0 || m.pathsCursor != 0 {
+			t.Fatalf("%v: config changed without being saved: %v %v cursor %d", keys, m.config.Paths, m.config.PathDepths, m.pathsCursor)
+		}
+	}
+}
+
+func TestCheckPathsReportsProblems(t *testing.T) {
+	home := t.TempDir()
+	file := filepath.Join(home, "notes.txt")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	nested := filepath.Join(home, "src")
+	if err := os.MkdirAll(filepath.Join(nested, "repo", ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.DefaultConfig()
+	cfg.Paths = []string{filepath.Join(home, "missing"), file, home, nested}
+	issues := checkPaths(cfg)
+
+	want := map[string]string{
+		cfg.Paths[0]: "missing",
+		file:         "not a directory",
+		nested:       "inside " + home,
+	}
+	for path, issue := range want {
+		if issues[path] != issue {
+			t.Errorf("issue for %s = %q, want %q", path, issues[path], issue)
+		}
+	}
+	if issue, ok := issues[home]; ok {
+		t.Errorf("unexpected issue for %s: %q", home, issue)
+	}
+}
@@ -0,0 +1,180 @@
+package ui
+
+import (
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"rtui/internal/git"
+)
+
+type PushKind int
+
+const (
+	PushSetUpstream PushKind = iota
+	PushForceWithLease
+)
+
+type pushRequest struct {
+	kind   PushKind
+	remote string
+	branch string
+}
+
+type remotesLoadedMsg struct {
+	path    string
+	remotes []string
+}
+
+func (m Model) loadRemotesCmd(path string) tea.Cmd {
+	return func() tea.Msg {
+		remotes, err := git.ListRemotes(path)
+		if err != nil {
+			return errMsg(err)
+		}
+		return remotesLoadedMsg{path: path, remotes: remotes}
+	}
+}
+
+// rewriteCheckedMsg tells whether a diverged branch rewrote commits it
+// had already pushed (see git.RewroteUpstream).
+type rewriteCheckedMsg struct {
+	path      string
+	rewritten bool
+}
+
+func (m Model) checkRewriteCmd(repo git.Repo) tea.Cmd {
+	return func() tea.Msg {
+		return rewriteCheckedMsg{path: repo.Path, rewritten: git.RewroteUpstream(repo.Path, repo.Branch)}
+	}
+}
+
+// applyRewriteCheck offers force-with-lease only after a rebase, amend
+// or reset of pushed work. A branch that is behind because others pushed
+// must pull first; force-pushing it would drop their commits.
+func (m Model) applyRewriteCheck(msg rewriteCheckedMsg) Model {
+	repo := m.currentRepo()
+	if repo == nil || repo.Path != msg.path || m.mode != ModeNormal {
+		return m
+	}
+	if !msg.rewritten {
+		return m.setStatusError("Cannot push: behind remote (pull first)")
+	}
+	m.pendingPush = pushRequest{kind: PushForceWithLease, branch: repo.Branch}
+	m.mode = ModeConfirmPush
+	return m
+}
+
+func (m Model) pushCmd(repo git.Repo, req pushRequest) tea.Cmd {
+	return func() tea.Msg {
+		var err error
+		switch req.kind {
+		case PushSetUpstream:
+			err = git.PushSetUpstream(repo.Path, req.remote, req.branch)
+		case PushForceWithLease:
+			err = git.PushForceWithLease(repo.Path)
+		}
+		if err != nil {
+			return errMsg(err)
+		}
+		return pushDoneMsg(repo.Name)
+	}
+}
+
+// applyRemotes decides the next step of a push without upstream: a
+// single remote, or the repo's configured remote, goes straight to
+// confirmation; several open a picker. Remotes that arrive after the
+// cursor moved or another modal opened are dropped.
+func (m Model) applyRemotes(msg remotesLoadedMsg) Model {
+	repo := m.currentRepo()
+	if repo == nil || repo.Path != msg.path || m.mode != ModeNormal {
+		return m
+	}
+	remotes := msg.remotes
+	if remote := m.config.RepoSettings(repo.Path).Remote; remote != "" {
+		if !slices.Contains(remotes, remote) {
+			return m.setStatusError("Cannot push: remote " + remote + " from config does not exist")
+		}
+		remotes = []string{remote}
+	}
+	switch len(remotes) {
+	case 0:
+		return m.setStatusError("Cannot push: no remote configured")
+	case 1:
+		m.pendingPush = pushRequest{kind: PushSetUpstream, remote: remotes[0], branch: repo.Branch}
+		m.mode = ModeConfirmPush
+		return m.clearStatus()
+	}
+	m.pushRemotes = remotes
+	m.pushCursor = indexOfString(remotes, "origin")
+	m.pendingPush = pushRequest{kind: PushSetUpstream, branch: repo.Branch}
+	m.mode = ModePushRemote
+	return m.clearStatus()
+}
+
+func (m Model) handlePushRemote(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch m.modalAction(ModePushRemote, msg.String()) {
+	case "remote-cancel":
+		m.mode = ModeNormal
+		m.pendingPush = pushRequest{}
+		m.pushRemotes = nil
+	case "remote-down":
+		if m.pushCursor < len(m.pushRemotes)-1 {
+			m.pushCursor++
+		}
+	case "remote-up":
+		if m.pushCursor > 0 {
+			m.pushCursor--
+		}
+	case "remote-select":
+		if m.pushCursor < 0 || m.pushCursor >= len(m.pushRemotes) {
+			return m, nil
+		}
+		m.pendingPush.remote = m.pushRemotes[m.pushCursor]
+		m.pushRemotes = nil
+		return m.startPendingPush()
+	}
+	return m, nil
+}
+
+func (m Model) handleConfirmPush(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch m.modalAction(ModeConfirmPush, msg.String()) {
+	case "push-upstream":
+		if m.pendingPush.kind == PushSetUpstream {
+			return m.startPendingPush()
+		}
+	case "push-force":
+		if m.pendingPush.kind == PushForceWithLease {
+			return m.startPendingPush()
+		}
+	case "push-cancel":
+		m.pendingPush = pushRequest{}
+		m.mode = ModeNormal
+	}
+	return m, nil
+}
+
+func (m Model) startPendingPush() (tea.Model, tea.Cmd) {
+	req := m.pendingPush
+	m.pendingPush = pushRequest{}
+	m.mode = ModeNormal
+	repo := m.currentRepo()
+	if repo == nil {
+		return m, nil
+	}
+	if req.kind == PushForceWithLease {
+		m = m.setStatusInfo("Force pushing (with lease)...")
+	} else {
+		m = m.setStatusInfo("Pushing to " + req.remote + "/" + req.branch + "...")
+	}
+	return m, m.pushCmd(*repo, req)
+}
+
+func indexOfString(items []string, target string) int {
+	for i, item := range items {
+		if item == target {
+			return i
+		}
+	}
+	return 0
+}
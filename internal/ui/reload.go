@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"errors"
+	"io/fs"
+	"maps"
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"rtui/internal/config"
+)
+
+// watchConfigCmd adds the config file, the workspace file and their
+// includes to the watcher so edits made outside rtui (e.g. after `s`)
+// apply live. Without a config directory there is nothing to watch yet.
+func (m Model) watchConfigCmd() tea.Cmd {
+	files := []string{config.ConfigPath()}
+	if ws := m.config.Workspace(); ws != "" {
+		files = append(files, ws)
+	}
+	files = append(files, m.config.IncludedFiles()...)
+	return func() tea.Msg {
+		if m.watcher == nil {
+			return nil
+		}
+		for _, f := range files {
+			if err := m.watcher.AddFile(f); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return watchErrMsg(err)
+			}
+		}
+		return nil
+	}
+}
+
+func reloadConfigCmd() tea.Cmd {
+	return func() tea.Msg {
+		cfg, err := config.Load()
+		return configReloadedMsg{cfg: cfg, err: err}
+	}
+}
+
+// reloadConfig applies a config re-read from disk: keys and theme are
+// rebuilt, the list is re-sorted and regrouped, and repos are rescanned
+// only when the scan paths or depths changed. A file that cannot be read
+// or parsed keeps the running config; its error goes to the banner.
+func (m Model) reloadConfig(msg configReloadedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.setProblems(msg.cfg.Problems)
+		status := "Config not reloaded: " + msg.err.Error()
+		if len(msg.cfg.Problems) > 0 {
+			status = "Config not reloaded: " + msg.cfg.Problems[0].String()
+		}
+		return m.setStatusError(status), nil
+	}
+
+	old := m.config
+	m.config = msg.cfg
+	m.setProblems(Check(msg.cfg))
+	var cmds []tea.Cmd
+	if !slices.Equal(old.IncludedFiles(), msg.cfg.IncludedFiles()) {
+		cmds = append(cmds, m.watchConfigCmd())
+	}
+	if config.Equal(old, msg.cfg) {
+		return m, tea.Batch(cmds...)
+	}
+	m.keys, _ = buildKeymap(msg.cfg.Keys)
+	applyTheme(msg.cfg.Theme, noColor())
+	if scanChanged(old, msg.cfg) {
+		m = m.setStatusInfo("Config reloaded, rescanning...")
+		return m, tea.Batch(append(cmds, m.loadRepos())...)
+	}
+	m.setRepos(m.repos)
+	m = m.setStatusInfo("Config reloaded")
+	return m, tea.Batch(cmds...)
+}
+
+// setProblems replaces the banner's problems, showing it again if they
+// changed since it was hidden.
+func (m *Model) setProblems(problems []config.Problem) {
+	if !slices.Equal(m.problems, problems) {
+		m.bannerHidden = false
+	}
+	m.problems = problems
+}
+
+// scanChanged reports whether the repo list must be rebuilt: scan roots
+// or depths or the manifest changed, or [[repo]] aliases or default
+// branches did (both are applied by the scan).
+func scanChanged(old, cfg config.Config) bool {
+	return !slices.Equal(old.ScanPaths(), cfg.ScanPaths()) ||
+		old.ScanDepth != cfg.ScanDepth || old.Manifest != cfg.Manifest ||
+		old.DefaultBranch != cfg.DefaultBranch ||
+		!maps.Equal(old.PathDepths, cfg.PathDepths) ||
+		!slices.EqualFunc(old.Repos, cfg.Repos, func(a, b config.RepoOverride) bool {
+			return a.Path == b.Path && a.Alias == b.Alias && a.DefaultBranch == b.DefaultBranch
+		})
+}
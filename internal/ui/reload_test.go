@@ -0,0 +1,107 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"rtui/internal/config"
+	"rtui/internal/watch"
+)
+
+func reload(m Model, cfg config.Config, err error) (Model, bool) {
+	next, cmd := m.Update(configReloadedMsg{cfg: cfg, err: err})
+	return next.(Model), cmd != nil
+}
+
+func TestReloadAppliesKeysAndSortWithoutRescan(t *testing.T) {
+	resetTheme(t)
+	m := searchModel()
+	cfg := m.config
+	cfg.SortMode = config.SortName
+	cfg.Keys = map[string]config.KeyList{"pull": {"U"}}
+	cfg.Theme.Name = config.ThemeLight
+
+	m, rescan := reload(m, cfg, nil)
+	if rescan {
+		t.Fatal("sort/keys/theme changes should not rescan")
+	}
+	if m.repos[0].Name != "api" || m.repos[1].Name != "infra" {
+		t.Fatalf("expected repos re-sorted by name, got %s, %s", m.repos[0].Name, m.repos[1].Name)
+	}
+	if a, ok := m.actionForKey("U"); !ok || a.id != "pull" {
+		t.Fatal("expected U rebound to pull")
+	}
+	if m.statusMsg != "Config reloaded" {
+		t.Fatalf("unexpected status %q", m.statusMsg)
+	}
+}
+
+func TestReloadRescansWhenPathsOrDepthChange(t *testing.T) {
+	m := searchModel()
+	cfg := m.config
+	cfg.ScanDepth = 3
+	if _, rescan := reload(m, cfg, nil); !rescan {
+		t.Fatal("expected a rescan after scan_depth changed")
+	}
+	cfg = m.config
+	cfg.Paths = []string{t.TempDir()}
+	if _, rescan := reload(m, cfg, nil); !rescan {
+		t.Fatal("expected a rescan after paths changed")
+	}
+	cfg = m.config
+	cfg.DefaultBranch = "origin/develop"
+	if _, rescan := reload(m, cfg, nil); !rescan {
+		t.Fatal("expected a rescan after default_branch changed")
+	}
+}
+
+func TestReloadKeepsConfigOnParseError(t *testing.T) {
+	m := searchModel()
+	m.config.SortMode = config.SortName
+	broken := config.DefaultConfig()
+	broken.Problems = []config.Problem{{Severity: config.SeverityError, Line: 3, Col: 14, Message: "expected value"}}
+
+	m, rescan := reload(m, broken, errors.New("toml: line 3"))
+	if rescan {
+		t.Fatal("a broken file should not rescan")
+	}
+	if m.config.SortMode != config.SortName {
+		t.Fatal("expected the previous config to stay in use")
+	}
+	if m.statusKind != StatusError || !strings.Contains(m.statusMsg, "3:14: error: expected value") {
+		t.Fatalf("unexpected status %q", m.statusMsg)
+	}
+	if !hasBanner(m) {
+		t.Fatal("expected the parse error in the banner")
+	}
+}
+
+func TestReloadOfUnchangedFileIsQuiet(t *testing.T) {
+	m := searchModel()
+	m.problems = []config.Problem{{Message: "editor not found"}}
+	m.bannerHidden = true
+	m, rescan := reload(m, m.config, nil)
+	if rescan || m.statusMsg != "" {
+		t.Fatalf("expected no-op reload, got rescan=%v status=%q", rescan, m.statusMsg)
+	}
+	if len(m.problems) != 0 {
+		t.Fatal("expected problems replaced by the fresh check")
+	}
+}
+
+func TestConfigFileEventTriggersReload(t *testing.T) {
+	fw := newFakeWatcher()
+	m := searchModel()
+	m.watcher = fw
+	if msg := m.watchConfigCmd()(); msg != nil {
+		t.Fatalf("unexpected msg %v", msg)
+	}
+	if len(fw.files) != 1 || fw.files[0] != config.ConfigPath() {
+		t.Fatalf("expected config file watched, got %v", fw.files)
+	}
+	_, cmd := m.Update(watchEventMsg(watch.Event{Path: config.ConfigPath()}))
+	if cmd == nil {
+		t.Fatal("expected reload command")
+	}
+}
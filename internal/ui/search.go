@@ -0,0 +1,253 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"rtui/internal/git"
+)
+
+// fuzzyMatch reports whether every rune of query appears in text in order,
+// ignoring case, and returns the byte offsets of the matched runes.
+func fuzzyMatch(text, query string) ([]int, bool) {
+	if query == "" {
+		return nil, false
+	}
+	needle := []rune(strings.ToLower(query))
+	var positions []int
+	for i, r := range text {
+		if len(positions) == len(needle) {
+			break
+		}
+		if unicode.ToLower(r) == needle[len(positions)] {
+			positions = append(positions, i)
+		}
+	}
+	if len(positions) < len(needle) {
+		return nil, false
+	}
+	return positions, true
+}
+
+// repoMatch holds the match offsets for the rendered repo fields.
+type repoMatch struct {
+	name   []int
+	branch []int
+}
+
+// matchRepo fuzzy-matches query against a repo's name, branch and path.
+// A path-only match still counts but highlights nothing.
+func matchRepo(repo git.Repo, query string) (repoMatch, bool) {
+	var match repoMatch
+	nameOK, branchOK := false, false
+	match.name, nameOK = fuzzyMatch(repo.Name, query)
+	match.branch, branchOK = fuzzyMatch(repo.Branch, query)
+	if nameOK || branchOK {
+		return match, true
+	}
+	_, pathOK := fuzzyMatch(displayPath(repo.Path), query)
+	return match, pathOK
+}
+
+// searchRows is repoRows with every group expanded: search walks repos
+// in collapsed groups too.
+func (m Model) searchRows() []repoRow {
+	m.collapsed = nil
+	return m.repoRows()
+}
+
+// searchIndex maps a list row to its index in searchRows.
+func (m Model) searchIndex(row int) int {
+	rows := m.repoRows()
+	if row < 0 || row >= len(rows) {
+		return 0
+	}
+	for i, r := range m.searchRows() {
+		if r == rows[row] {
+			return i
+		}
+	}
+	return 0
+}
+
+// listIndex maps a searchRows index back to a list row, or -1 when it is
+// inside a collapsed group.
+func (m Model) listIndex(i int) int {
+	all := m.searchRows()
+	if i < 0 || i >= len(all) {
+		return -1
+	}
+	for row, r := range m.repoRows() {
+		if r == all[i] {
+			return row
+		}
+	}
+	return -1
+}
+
+// searchMatches returns the searchRows indices of repos matching the
+// search query.
+func (m Model) searchMatches() []int {
+	if m.searchQuery == "" {
+		return nil
+	}
+	repos := m.visibleRepos()
+	var out []int
+	for i, row := range m.searchRows() {
+		if row.header {
+			continue
+		}
+		if _, ok := matchRepo(repos[row.repo], m.searchQuery); ok {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+// jumpToMatch moves the cursor to the next match at or after from, a
+// searchRows index (or before it when backward), wrapping around. A match
+// inside a collapsed group expands the group. It reports the match
+// position.
+func (m *Model) jumpToMatch(from int, backward bool) (int, int, bool) {
+	matches := m.searchMatches()
+	if len(matches) == 0 {
+		return 0, 0, false
+	}
+	pick := -1
+	if backward {
+		for i := len(matches) - 1; i >= 0; i-- {
+			if matches[i] < from {
+				pick = i
+				break
+			}
+		}
+		if pick < 0 {
+			pick = len(matches) - 1
+		}
+	} else {
+		for i, row := range matches {
+			if row >= from {
+				pick = i
+				break
+			}
+		}
+		if pick < 0 {
+			pick = 0
+		}
+	}
+	if group := m.searchRows()[matches[pick]].group; m.collapsed[group] {
+		m.toggleGroup(group)
+	}
+	m.cursor = m.listIndex(matches[pick])
+	m.resetBottomScroll()
+	return pick + 1, len(matches), true
+}
+
+func (m Model) startSearch() Model {
+	m.mode = ModeSearch
+	m.searchQuery = ""
+	m.searchOrigin = m.searchIndex(m.cursor)
+	return m
+}
+
+func (m Model) handleSearch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.mode = ModeNormal
+		m.searchQuery = ""
+		// Search only expands groups, so the origin row is still shown.
+		m.cursor = max(m.listIndex(m.searchOrigin), 0)
+		m.resetBottomScroll()
+		return m, nil
+	case "enter":
+		m.mode = ModeNormal
+		if len(m.searchMatches()) == 0 {
+			m.searchQuery = ""
+		}
+		return m, m.maybeLoadGraph()
+	case "backspace":
+		if m.searchQuery != "" {
+			_, size := utf8.DecodeLastRuneInString(m.searchQuery)
+			m.searchQuery = m.searchQuery[:len(m.searchQuery)-size]
+			m.jumpToMatch(m.searchOrigin, false)
+		}
+	case "down", "ctrl+n":
+		m.jumpToMatch(m.searchIndex(m.cursor)+1, false)
+	case "up":
+		m.jumpToMatch(m.searchIndex(m.cursor), true)
+	default:
+		if msg.Type == tea.KeyRunes {
+			m.searchQuery += stripNewlines(string(msg.Runes))
+			m.jumpToMatch(m.searchOrigin, false)
+		}
+	}
+	return m, nil
+}
+
+// cycleMatch handles n/N after a search was confirmed.
+func (m Model) cycleMatch(backward bool) (tea.Model, tea.Cmd) {
+	if m.searchQuery == "" {
+		return m, nil
+	}
+	from := m.searchIndex(m.cursor) + 1
+	if backward {
+		from--
+	}
+	pos, total, ok := m.jumpToMatch(from, backward)
+	if !ok {
+		m = m.setStatusInfo("No match for " + m.searchQuery)
+		return m, nil
+	}
+	m = m.setStatusInfo(fmt.Sprintf("/%s  %d/%d", m.searchQuery, pos, total))
+	return m, m.maybeLoadGraph()
+}
+
+func (m Model) renderSearchBar() string {
+	matches := m.searchMatches()
+	count := ""
+	if m.searchQuery != "" {
+		count = fmt.Sprintf("  %d match%s", len(matches), plural(len(matches), "", "es"))
+	}
+	bar := sectionTitleStyle.Render("/") + m.searchQuery + "█" + footerStyle.Render(count)
+	hints := footerStyle.Render("↑/↓ prev/next  [enter] keep  [esc] cancel")
+	gap := m.width - lipgloss.Width(bar) - lipgloss.Width(hints)
+	if gap < 2 {
+		return bar
+	}
+	return bar + strings.Repeat(" ", gap) + hints
+}
+
+// highlightMatches renders display with the runes at positions (byte
+// offsets into orig) emphasised. display may be a truncated orig; offsets
+// past the shared prefix are ignored. base styles the unmatched runes.
+func highlightMatches(display, orig string, positions []int, base lipgloss.Style) string {
+	if len(positions) == 0 {
+		return base.Render(display)
+	}
+	hit := make(map[int]bool, len(positions))
+	for _, p := range positions {
+		hit[p] = true
+	}
+	var b, plain strings.Builder
+	flush := func() {
+		if plain.Len() > 0 {
+			b.WriteString(base.Render(plain.String()))
+			plain.Reset()
+		}
+	}
+	for i, r := range display {
+		if hit[i] && strings.HasPrefix(orig[i:], string(r)) {
+			flush()
+			b.WriteString(searchMatchStyle.Render(string(r)))
+			continue
+		}
+		plain.WriteRune(r)
+	}
+	flush()
+	return b.String()
+}
@@ -0,0 +1,150 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"rtui/internal/config"
+	"rtui/internal/git"
+)
+
+func searchModel() Model {
+	m := NewModel(config.DefaultConfig())
+	m.width = 80
+	m.height = 30
+	m.repos = []git.Repo{
+		{Name: "api", Path: "/src/api", Branch: "main"},
+		{Name: "web-client", Path: "/src/web-client", Branch: "feature/login"},
+		{Name: "infra", Path: "/srv/ops/infra", Branch: "main"},
+		{Name: "worker", Path: "/src/worker", Branch: "wip"},
+	}
+	return m
+}
+
+func typeKeys(t *testing.T, m Model, keys ...string) Model {
+	t.Helper()
+	for _, k := range keys {
+		var msg tea.KeyMsg
+		switch k {
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "backspace":
+			msg = tea.KeyMsg{Type: tea.KeyBackspace}
+		case "pgdown":
+			msg = tea.KeyMsg{Type: tea.KeyPgDown}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		}
+		updated, _ := m.Update(msg)
+		m = updated.(Model)
+	}
+	return m
+}
+
+func TestFuzzyMatch(t *testing.T) {
+	pos, ok := fuzzyMatch("web-client", "WCL")
+	if !ok {
+		t.Fatal("expected match")
+	}
+	if got := []int{0, 4, 5}; len(pos) != 3 || pos[0] != got[0] || pos[1] != got[1] || pos[2] != got[2] {
+		t.Fatalf("positions = %v, want %v", pos, got)
+	}
+	if _, ok := fuzzyMatch("api", "pa"); ok {
+		t.Fatal("expected out-of-order query not to match")
+	}
+}
+
+func TestSearchJumpsAsYouType(t *testing.T) {
+	m := typeKeys(t, searchModel(), "/", "w", "k")
+	if m.mode != ModeSearch {
+		t.Fatalf("expected search mode, got %v", m.mode)
+	}
+	if repo := m.currentRepo(); repo == nil || repo.Name != "worker" {
+		t.Fatalf("expected worker, got %+v", repo)
+	}
+	m = typeKeys(t, m, "backspace")
+	if repo := m.currentRepo(); repo == nil || repo.Name != "web-client" {
+		t.Fatalf("expected web-client after backspace, got %+v", repo)
+	}
+}
+
+func TestSearchMatchesBranchAndPath(t *testing.T) {
+	m := typeKeys(t, searchModel(), "/", "l", "o", "g")
+	if repo := m.currentRepo(); repo == nil || repo.Name != "web-client" {
+		t.Fatalf("expected branch match on web-client, got %+v", repo)
+	}
+	m = typeKeys(t, searchModel(), "/", "o", "p", "s")
+	if repo := m.currentRepo(); repo == nil || repo.Name != "infra" {
+		t.Fatalf("expected path match on infra, got %+v", repo)
+	}
+}
+
+func TestSearchNextPreviousWraps(t *testing.T) {
+	m := typeKeys(t, searchModel(), "/", "m", "a", "i", "n", "enter")
+	if m.mode != ModeNormal || m.searchQuery != "main" {
+		t.Fatalf("expected kept query in normal mode, got %v %q", m.mode, m.searchQuery)
+	}
+	if m.cursor != 0 {
+		t.Fatalf("expected first match at 0, got %d", m.cursor)
+	}
+	m = typeKeys(t, m, "n")
+	if m.cursor != 2 {
+		t.Fatalf("expected n to reach infra, got %d", m.cursor)
+	}
+	m = typeKeys(t, m, "n")
+	if m.cursor != 0 {
+		t.Fatalf("expected n to wrap to api, got %d", m.cursor)
+	}
+	m = typeKeys(t, m, "N")
+	if m.cursor != 2 {
+		t.Fatalf("expected N to wrap back to infra, got %d", m.cursor)
+	}
+}
+
+func TestSearchEscRestoresCursor(t *testing.T) {
+	m := searchModel()
+	m.cursor = 1
+	m = typeKeys(t, m, "/", "i", "n", "f", "esc")
+	if m.cursor != 1 || m.searchQuery != "" || m.mode != ModeNormal {
+		t.Fatalf("expected cancelled search at 1, got cursor=%d query=%q mode=%v", m.cursor, m.searchQuery, m.mode)
+	}
+}
+
+func TestHighlightMatchesSkipsTruncatedTail(t *testing.T) {
+	got := highlightMatches(truncate("web-client", 5), "web-client", []int{0, 4, 9}, lipgloss.NewStyle())
+	if !strings.HasPrefix(got, searchMatchStyle.Render("w")) {
+		t.Fatalf("expected first rune highlighted, got %q", got)
+	}
+	if !strings.HasSuffix(got, "eb-…") {
+		t.Fatalf("expected ellipsis left plain, got %q", got)
+	}
+}
+
+func TestSearchExpandsCollapsedGroup(t *testing.T) {
+	m := groupedModel()
+	m.toggleGroup("payments")
+	m.toggleGroup("/src/personal")
+	m.cursor = 2 // infra
+
+	m = typeKeys(t, m, "/", "b", "l", "o", "g")
+	if r := m.currentRepo(); r == nil || r.Name != "blog" || m.collapsed["/src/personal"] {
+		t.Fatalf("expected blog's group expanded and selected, got %v collapsed=%v", r, m.collapsed)
+	}
+	m = typeKeys(t, m, "esc")
+	if r := m.currentRepo(); r == nil || r.Name != "infra" {
+		t.Fatalf("expected esc to return to infra, got %v", r)
+	}
+
+	m = typeKeys(t, m, "/", "w", "e", "b", "enter")
+	if r := m.currentRepo(); r == nil || r.Name != "pay-web" || m.collapsed["payments"] {
+		t.Fatalf("expected pay-web's group expanded and selected, got %v collapsed=%v", r, m.collapsed)
+	}
+	if m.collapsed["/src/personal"] {
+		t.Fatal("a group opened by search stays open")
+	}
+}
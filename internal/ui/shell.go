@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"rtui/internal/git"
+)
+
+// shellCommand returns the command opened by the shell key for a repo:
+// the repo group's shell_command, the global one, else $SHELL, else /bin/sh.
+func (m Model) shellCommand(repo git.Repo) (string, []string) {
+	if g, ok := m.configGroup(repo); ok && g.ShellCommand != "" {
+		return g.ShellCommand, g.ShellArgs
+	}
+	if m.config.ShellCommand != "" {
+		return m.config.ShellCommand, m.config.ShellArgs
+	}
+	if sh := os.Getenv("SHELL"); sh != "" {
+		return sh, nil
+	}
+	return "/bin/sh", nil
+}
+
+// openShellCmd suspends the TUI, runs the command in the repo directory
+// and refreshes the repo once it exits.
+func (m Model) openShellCmd(repo git.Repo) tea.Cmd {
+	name, args := m.shellCommand(repo)
+	cmd := exec.Command(name, args...)
+	cmd.Dir = repo.Path
+	path := repo.Path
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return m.shellExited(name, path, err)
+	})
+}
+
+// shellExited refreshes the repo whatever the command exited with: an
+// interactive shell returns the status of its last command, which says
+// nothing about the repo. Only a command that did not start is an error.
+func (m Model) shellExited(name, path string, err error) tea.Msg {
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return errMsg(err)
+	}
+	status := "Back from " + filepath.Base(name)
+	if exitErr != nil {
+		status += " (" + exitErr.Error() + ")"
+	}
+	return repoUpdatedMsg{repo: m.repoStatus(path), status: status}
+}
@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"os/exec"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"rtui/internal/config"
+	"rtui/internal/git"
+)
+
+func TestShellCommandFallbacks(t *testing.T) {
+	repo := git.Repo{Path: "/tmp/repo"}
+
+	t.Setenv("SHELL", "/bin/zsh")
+	m := NewModel(config.DefaultConfig())
+	if name, _ := m.shellCommand(repo); name != "/bin/zsh" {
+		t.Fatalf("expected $SHELL, got %q", name)
+	}
+
+	t.Setenv("SHELL", "")
+	if name, _ := m.shellCommand(repo); name != "/bin/sh" {
+		t.Fatalf("expected /bin/sh fallback, got %q", name)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.ShellCommand = "tig"
+	cfg.ShellArgs = []string{"--all"}
+	m = NewModel(cfg)
+	name, args := m.shellCommand(repo)
+	if name != "tig" || len(args) != 1 || args[0] != "--all" {
+		t.Fatalf("expected configured command, got %q %v", name, args)
+	}
+}
+
+func TestShellKeyReturnsExecCmd(t *testing.T) {
+	m := NewModel(config.DefaultConfig())
+	m.repos = []git.Repo{{Name: "repo", Path: "/tmp/repo"}}
+
+	_, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+	if cmd == nil {
+		t.Fatal("expected exec cmd")
+	}
+}
+
+func TestShellExitRefreshesRepo(t *testing.T) {
+	m := NewModel(config.DefaultConfig())
+	dir := t.TempDir()
+
+	exitErr := exec.Command("sh", "-c", "exit 1").Run()
+	msg, ok := m.shellExited("/bin/zsh", dir, exitErr).(repoUpdatedMsg)
+	if !ok || msg.repo.Path != dir || msg.status != "Back from zsh (exit status 1)" {
+		t.Fatalf("expected a refresh with the exit status noted, got %#v", msg)
+	}
+	if msg, ok := m.shellExited("/bin/zsh", dir, nil).(repoUpdatedMsg); !ok || msg.status != "Back from zsh" {
+		t.Fatalf("expected a plain refresh, got %#v", msg)
+	}
+	if _, ok := m.shellExited("nope", dir, exec.ErrNotFound).(errMsg); !ok {
+		t.Fatal("expected a command that did not start to be an error")
+	}
+}
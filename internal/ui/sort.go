@@ -0,0 +1,152 @@
+package ui
+
+import (
+	"sort"
+	"strings"
+
+	"rtui/internal/config"
+	"rtui/internal/git"
+)
+
+// sortRepos returns repos ordered by mode. Ties fall back to name, then
+// path, so refreshes never shuffle equal repos.
+func sortRepos(repos []git.Repo, mode string) []git.Repo {
+	out := make([]git.Repo, len(repos))
+	copy(out, repos)
+	byName := func(a, b git.Repo) bool {
+		an, bn := strings.ToLower(a.Name), strings.ToLower(b.Name)
+		if an != bn {
+			return an < bn
+		}
+		return a.Path < b.Path
+	}
+	var less func(a, b git.Repo) bool
+	switch mode {
+	case config.SortName:
+		less = byName
+	case config.SortCommitted:
+		less = func(a, b git.Repo) bool {
+			if !a.LastCommit.Equal(b.LastCommit) {
+				return a.LastCommit.After(b.LastCommit)
+			}
+			return byName(a, b)
+		}
+	case config.SortModified:
+		less = func(a, b git.Repo) bool {
+			if !a.LastModified.Equal(b.LastModified) {
+				return a.LastModified.After(b.LastModified)
+			}
+			return byName(a, b)
+		}
+	case config.SortDirty:
+		less = func(a, b git.Repo) bool {
+			if a.HasConflict != b.HasConflict {
+				return a.HasConflict
+			}
+			if da, db := changeCount(a), changeCount(b); da != db {
+				return da > db
+			}
+			return byName(a, b)
+		}
+	case config.SortBehind:
+		less = func(a, b git.Repo) bool {
+			if a.Behind != b.Behind {
+				return a.Behind > b.Behind
+			}
+			return byName(a, b)
+		}
+	default:
+		less = func(a, b git.Repo) bool {
+			return a.Path < b.Path
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return less(out[i], out[j])
+	})
+	return out
+}
+
+func changeCount(r git.Repo) int {
+	return r.Staged + r.Modified + r.Untracked
+}
+
+// sortLabel describes a sort mode for the status line and list title.
+func sortLabel(mode string) string {
+	switch mode {
+	case config.SortName:
+		return "name"
+	case config.SortCommitted:
+		return "last commit"
+	case config.SortModified:
+		return "last modified"
+	case config.SortDirty:
+		return "dirtiest"
+	case config.SortBehind:
+		return "most behind"
+	default:
+		return "path"
+	}
+}
+
+// rowKey identifies the row under the cursor independently of its index.
+type rowKey struct {
+	header bool
+	group  string
+	path   string
+}
+
+func (m Model) cursorKey() (rowKey, bool) {
+	row, ok := m.currentRow()
+	if !ok {
+		return rowKey{}, false
+	}
+	if row.header {
+		return rowKey{header: true, group: row.group}, true
+	}
+	return rowKey{path: m.visibleRepos()[row.repo].Path}, true
+}
+
+// restoreCursor moves the cursor back onto key after the list changed,
+// clamping to the list when the row is gone.
+func (m *Model) restoreCursor(key rowKey, ok bool) {
+	rows := m.repoRows()
+	if ok {
+		repos := m.visibleRepos()
+		for i, row := range rows {
+			if key.header && row.header && row.group == key.group {
+				m.cursor = i
+				return
+			}
+			if !key.header && !row.header && repos[row.repo].Path == key.path {
+				m.cursor = i
+				return
+			}
+		}
+	}
+	if m.cursor >= len(rows) {
+		m.cursor = max(len(rows)-1, 0)
+	}
+}
+
+// setRepos replaces the repo list in sort order, keeping the cursor on
+// the same repo. Names follow the [[repo]] aliases in the config.
+func (m *Model) setRepos(repos []git.Repo) {
+	key, ok := m.cursorKey()
+	named := make([]git.Repo, len(repos))
+	for i, r := range repos {
+		if alias := m.config.RepoSettings(r.Path).Alias; alias != "" {
+			r.Name = alias
+		}
+		named[i] = r
+	}
+	m.repos = sortRepos(named, m.config.SortMode)
+	m.restoreCursor(key, ok)
+}
+
+// cycleSort switches to the next sort mode and persists it.
+func (m Model) cycleSort() (Model, error) {
+	m.config.SortMode = config.NextSortMode(m.config.SortMode)
+	m.setRepos(m.repos)
+	m = m.setStatusInfo("Sort: " + sortLabel(m.config.SortMode))
+	return m, config.Save(m.config)
+}
@@ -0,0 +1,134 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"rtui/internal/config"
+	"rtui/internal/git"
+)
+
+func sortFixture() []git.Repo {
+	now := time.Now()
+	return []git.Repo{
+		{Name: "beta", Path: "/src/beta", LastCommit: now.Add(-time.Hour), LastModified: now, Modified: 1},
+		{Name: "Alpha", Path: "/src/zeta", LastCommit: now, LastModified: now.Add(-time.Hour), Behind: 3},
+		{Name: "gamma", Path: "/src/alpha", LastCommit: now.Add(-2 * time.Hour), Untracked: 2, Staged: 1, Behind: 1},
+	}
+}
+
+func repoNames(repos []git.Repo) string {
+	var names []string
+	for _, r := range repos {
+		names = append(names, r.Name)
+	}
+	return strings.Join(names, " ")
+}
+
+func TestSortReposModes(t *testing.T) {
+	cases := map[string]string{
+		config.SortPath:      "gamma beta Alpha",
+		config.SortName:      "Alpha beta gamma",
+		config.SortCommitted: "Alpha beta gamma",
+		config.SortModified:  "beta Alpha gamma",
+		config.SortDirty:     "gamma beta Alpha",
+		config.SortBehind:    "Alpha gamma beta",
+	}
+	for mode, want := range cases {
+		if got := repoNames(sortRepos(sortFixture(), mode)); got != want {
+			t.Errorf("%s: got %q, want %q", mode, got, want)
+		}
+	}
+}
+
+func TestSetReposKeepsCursorOnSameRepo(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.SortMode = config.SortName
+	m := NewModel(cfg)
+	m.setRepos(sortFixture())
+	m.cursor = 1 // beta
+
+	reloaded := sortFixture()
+	reloaded = append(reloaded, git.Repo{Name: "aaa", Path: "/src/aaa"})
+	m.setRepos(reloaded)
+
+	if repo := m.currentRepo(); repo == nil || repo.Name != "beta" {
+		t.Fatalf("expected cursor to stay on beta, got %+v", repo)
+	}
+}
+
+func TestApplyRepoUpdateResortsAndKeepsRoot(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.SortMode = config.SortBehind
+	m := NewModel(cfg)
+	repos := sortFixture()
+	for i := range repos {
+		repos[i].Root = "/src"
+	}
+	m.setRepos(repos)
+	m.cursor = 2 // beta, behind 0
+
+	m.applyRepoUpdate(git.Repo{Name: "beta", Path: "/src/beta", Behind: 5})
+
+	if m.cursor != 0 {
+		t.Fatalf("expected cursor to follow beta to the top, got %d", m.cursor)
+	}
+	if repo := m.currentRepo(); repo == nil || repo.Root != "/src" {
+		t.Fatalf("expected root to be kept, got %+v", repo)
+	}
+}
+
+func TestSortKeyCyclesAndPersists(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	m := NewModel(config.DefaultConfig())
+	m.setRepos(sortFixture())
+	m.cursor = 0 // gamma under path sort
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("S")})
+	if cmd != nil {
+		t.Fatalf("expected no error command, got %v", cmd())
+	}
+	model := updated.(Model)
+	if model.config.SortMode != config.SortName {
+		t.Fatalf("expected name sort, got %q", model.config.SortMode)
+	}
+	if repo := model.currentRepo(); repo == nil || repo.Name != "gamma" {
+		t.Fatalf("expected cursor to stay on gamma, got %+v", repo)
+	}
+	data, err := os.ReadFile(filepath.Join(home, ".config", "rtui", "config.toml"))
+	if err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+	if !strings.Contains(string(data), `sort_mode = "name"`) {
+		t.Fatalf("expected sort_mode persisted, got:\n%s", data)
+	}
+}
+
+func TestSetReposAppliesAliasesAndHidesRepos(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.SortMode = config.SortName
+	cfg.Repos = []config.RepoOverride{
+		{Path: "/src/pay-api", Alias: "Payments"},
+		{Path: "/src/old-*", Hidden: true},
+	}
+	m := NewModel(cfg)
+	m.setRepos([]git.Repo{
+		{Name: "pay-api", Path: "/src/pay-api"},
+		{Name: "old-site", Path: "/src/old-site", Modified: 1},
+		{Name: "blog", Path: "/src/blog"},
+	})
+	if got := repoNames(m.visibleRepos()); got != "blog Payments" {
+		t.Fatalf("visible = %q", got)
+	}
+	m.filterDirty = true
+	if got := len(m.visibleRepos()); got != 0 {
+		t.Fatalf("hidden repos stay hidden when filtering, got %d", got)
+	}
+}
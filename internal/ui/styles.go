@@ -1,66 +1,254 @@
 package ui
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 
-var (
-	colorCyan    = lipgloss.Color("6")
-	colorMagenta = lipgloss.Color("5")
-	colorGreen   = lipgloss.Color("2")
-	colorYellow  = lipgloss.Color("3")
-	colorRed     = lipgloss.Color("1")
-	colorGray    = lipgloss.Color("8")
-	colorWhite   = lipgloss.Color("15")
-)
-
-var (
-	cursorStyle = lipgloss.NewStyle().
-			Bold(true).
-			Reverse(true)
-
-	selectedRepoStyle = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(colorCyan)
-
-	cleanRepoStyle = lipgloss.NewStyle().
-			Foreground(colorGray)
-
-	dirtyRepoStyle = lipgloss.NewStyle().
-			Foreground(colorWhite)
-
-	stagedStyle = lipgloss.NewStyle().
-			Foreground(colorGreen)
-
-	modifiedStyle = lipgloss.NewStyle().
-			Foreground(colorYellow)
+	"github.com/charmbracelet/lipgloss"
 
-	untrackedStyle = lipgloss.NewStyle().
-			Foreground(colorGray)
-
-	conflictStyle = lipgloss.NewStyle().
-			Foreground(colorRed).
-			Bold(true)
-
-	aheadStyle = lipgloss.NewStyle().
-			Foreground(colorCyan)
-
-	behindStyle = lipgloss.NewStyle().
-			Foreground(colorMagenta)
-
-	sectionTitleStyle = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(colorWhite)
-
-	footerStyle = lipgloss.NewStyle().
-			Foreground(colorGray)
-
-	hotkeyStyle = footerStyle.Copy().
-			Foreground(colorCyan)
+	"rtui/internal/config"
+)
 
-	inputStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			Padding(0, 1)
+// themeColors are the roles a theme assigns colors to.
+type themeColors struct {
+	text   lipgloss.Color
+	muted  lipgloss.Color
+	accent lipgloss.Color
+	ok     lipgloss.Color
+	warn   lipgloss.Color
+	err    lipgloss.Color
+	behind lipgloss.Color
+}
+
+var themes = map[string]themeColors{
+	config.ThemeDark: {
+		text:   lipgloss.Color("15"),
+		muted:  lipgloss.Color("8"),
+		accent: lipgloss.Color("6"),
+		ok:     lipgloss.Color("2"),
+		warn:   lipgloss.Color("3"),
+		err:    lipgloss.Color("1"),
+		behind: lipgloss.Color("5"),
+	},
+	config.ThemeLight: {
+		text:   lipgloss.Color("235"),
+		muted:  lipgloss.Color("244"),
+		accent: lipgloss.Color("25"),
+		ok:     lipgloss.Color("28"),
+		warn:   lipgloss.Color("130"),
+		err:    lipgloss.Color("160"),
+		behind: lipgloss.Color("90"),
+	},
+	config.ThemeHighContrast: {
+		text:   lipgloss.Color("15"),
+		muted:  lipgloss.Color("7"),
+		accent: lipgloss.Color("14"),
+		ok:     lipgloss.Color("10"),
+		warn:   lipgloss.Color("11"),
+		err:    lipgloss.Color("9"),
+		behind: lipgloss.Color("13"),
+	},
+	config.ThemeSolarized: {
+		text:   lipgloss.Color("#93a1a1"),
+		muted:  lipgloss.Color("#586e75"),
+		accent: lipgloss.Color("#268bd2"),
+		ok:     lipgloss.Color("#859900"),
+		warn:   lipgloss.Color("#b58900"),
+		err:    lipgloss.Color("#dc322f"),
+		behind: lipgloss.Color("#d33682"),
+	},
+}
 
-	boxStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			Padding(0, 1)
+var (
+	cursorStyle       lipgloss.Style
+	selectedRepoStyle lipgloss.Style
+	cleanRepoStyle    lipgloss.Style
+	dirtyRepoStyle    lipgloss.Style
+	stagedStyle       lipgloss.Style
+	modifiedStyle     lipgloss.Style
+	untrackedStyle    lipgloss.Style
+	conflictStyle     lipgloss.Style
+	searchMatchStyle  lipgloss.Style
+	aheadStyle        lipgloss.Style
+	behindStyle       lipgloss.Style
+	sectionTitleStyle lipgloss.Style
+	footerStyle       lipgloss.Style
+	hotkeyStyle       lipgloss.Style
+	inputStyle        lipgloss.Style
+	boxStyle          lipgloss.Style
+
+	// monochrome is set under NO_COLOR; views add symbols where color
+	// alone would carry meaning.
+	monochrome bool
 )
+
+// styleVars maps theme.styles names to the style variables.
+var styleVars = map[string]*lipgloss.Style{
+	"cursor":        &cursorStyle,
+	"selected":      &selectedRepoStyle,
+	"clean_repo":    &cleanRepoStyle,
+	"dirty_repo":    &dirtyRepoStyle,
+	"staged":        &stagedStyle,
+	"modified":      &modifiedStyle,
+	"untracked":     &untrackedStyle,
+	"conflict":      &conflictStyle,
+	"search_match":  &searchMatchStyle,
+	"ahead":         &aheadStyle,
+	"behind":        &behindStyle,
+	"section_title": &sectionTitleStyle,
+	"footer":        &footerStyle,
+	"hotkey":        &hotkeyStyle,
+	"input":         &inputStyle,
+	"box":           &boxStyle,
+}
+
+func init() {
+	applyTheme(config.Theme{}, false)
+}
+
+// noColor reports whether NO_COLOR is set (https://no-color.org).
+func noColor() bool {
+	return os.Getenv("NO_COLOR") != ""
+}
+
+// applyTheme rebuilds every style from the named theme and its overrides.
+// Unknown names fall back to dark; Validate reports them at startup. In
+// monochrome mode colors are dropped and only text attributes remain.
+func applyTheme(theme config.Theme, mono bool) {
+	monochrome = mono
+	if mono {
+		buildMonochrome()
+	} else {
+		colors, ok := themes[theme.Name]
+		if !ok {
+			colors = themes[config.ThemeDark]
+		}
+		buildStyles(colors)
+	}
+	for name, o := range theme.Styles {
+		if style, ok := styleVars[name]; ok {
+			*style = applyOverride(*style, o, mono)
+		}
+	}
+}
+
+func buildStyles(c themeColors) {
+	cursorStyle = lipgloss.NewStyle().Bold(true).Reverse(true)
+	selectedRepoStyle = lipgloss.NewStyle().Bold(true).Foreground(c.accent)
+	cleanRepoStyle = lipgloss.NewStyle().Foreground(c.muted)
+	dirtyRepoStyle = lipgloss.NewStyle().Foreground(c.text)
+	stagedStyle = lipgloss.NewStyle().Foreground(c.ok)
+	modifiedStyle = lipgloss.NewStyle().Foreground(c.warn)
+	untrackedStyle = lipgloss.NewStyle().Foreground(c.muted)
+	conflictStyle = lipgloss.NewStyle().Foreground(c.err).Bold(true)
+	searchMatchStyle = lipgloss.NewStyle().Bold(true).Underline(true).Foreground(c.warn)
+	aheadStyle = lipgloss.NewStyle().Foreground(c.accent)
+	behindStyle = lipgloss.NewStyle().Foreground(c.behind)
+	sectionTitleStyle = lipgloss.NewStyle().Bold(true).Foreground(c.text)
+	footerStyle = lipgloss.NewStyle().Foreground(c.muted)
+	hotkeyStyle = footerStyle.Foreground(c.accent)
+	inputStyle = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(0, 1)
+	boxStyle = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(0, 1)
+}
+
+func buildMonochrome() {
+	plain := lipgloss.NewStyle()
+	cursorStyle = plain.Bold(true).Reverse(true)
+	selectedRepoStyle = plain.Bold(true)
+	cleanRepoStyle = plain
+	dirtyRepoStyle = plain.Bold(true)
+	stagedStyle = plain
+	modifiedStyle = plain
+	untrackedStyle = plain
+	conflictStyle = plain.Bold(true)
+	searchMatchStyle = plain.Bold(true).Underline(true)
+	aheadStyle = plain
+	behindStyle = plain
+	sectionTitleStyle = plain.Bold(true)
+	footerStyle = plain
+	hotkeyStyle = plain.Underline(true)
+	inputStyle = plain.Border(lipgloss.RoundedBorder()).Padding(0, 1)
+	boxStyle = plain.Border(lipgloss.RoundedBorder()).Padding(0, 1)
+}
+
+func applyOverride(style lipgloss.Style, o config.StyleOverride, mono bool) lipgloss.Style {
+	if !mono {
+		if o.Fg != "" {
+			style = style.Foreground(lipgloss.Color(o.Fg))
+			style = style.BorderForeground(lipgloss.Color(o.Fg))
+		}
+		if o.Bg != "" {
+			style = style.Background(lipgloss.Color(o.Bg))
+		}
+	}
+	if o.Bold != nil {
+		style = style.Bold(*o.Bold)
+	}
+	if o.Underline != nil {
+		style = style.Underline(*o.Underline)
+	}
+	if o.Reverse != nil {
+		style = style.Reverse(*o.Reverse)
+	}
+	return style
+}
+
+var hexColor = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// validateTheme reports unknown theme or style names and bad colors.
+func validateTheme(theme config.Theme) error {
+	if errs := themeErrors(theme); len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+// themeErrors is validateTheme reporting every problem, in style order.
+func themeErrors(theme config.Theme) []configError {
+	var errs []configError
+	if theme.Name != "" {
+		if _, ok := themes[theme.Name]; !ok {
+			errs = append(errs, configError{"theme", "name", fmt.Sprintf("unknown name %q", theme.Name), "use one of " + strings.Join(themeNames(), ", ")})
+		}
+	}
+	names := make([]string, 0, len(theme.Styles))
+	for name := range theme.Styles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		table := "theme.styles." + name
+		if _, ok := styleVars[name]; !ok {
+			errs = append(errs, configError{table, "", fmt.Sprintf("unknown style %q", name), "remove the table or use a style listed in the docs"})
+			continue
+		}
+		o := theme.Styles[name]
+		for _, c := range []struct{ key, value string }{{"fg", o.Fg}, {"bg", o.Bg}} {
+			if c.value != "" && !validColor(c.value) {
+				errs = append(errs, configError{table, c.key, fmt.Sprintf("style %q has invalid color %q", name, c.value), `use "#rrggbb" or an ANSI number 0-255`})
+			}
+		}
+	}
+	return errs
+}
+
+func validColor(c string) bool {
+	if hexColor.MatchString(c) {
+		return true
+	}
+	n, err := strconv.Atoi(c)
+	return err == nil && n >= 0 && n <= 255
+}
+
+func themeNames() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
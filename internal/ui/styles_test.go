@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"rtui/internal/config"
+)
+
+func resetTheme(t *testing.T) {
+	t.Cleanup(func() { applyTheme(config.Theme{}, false) })
+}
+
+func TestValidateTheme(t *testing.T) {
+	cases := []struct {
+		theme config.Theme
+		want  string
+	}{
+		{config.Theme{Name: "neon"}, "unknown name"},
+		{config.Theme{Styles: map[string]config.StyleOverride{"sparkle": {}}}, "unknown style"},
+		{config.Theme{Styles: map[string]config.StyleOverride{"footer": {Fg: "grey"}}}, "invalid color"},
+		{config.Theme{Styles: map[string]config.StyleOverride{"footer": {Fg: "300"}}}, "invalid color"},
+	}
+	for _, tc := range cases {
+		if err := validateTheme(tc.theme); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%+v: expected %q error, got %v", tc.theme, tc.want, err)
+		}
+	}
+	ok := config.Theme{Name: config.ThemeSolarized, Styles: map[string]config.StyleOverride{"dirty_repo": {Fg: "#002b36", Bg: "7"}}}
+	if err := validateTheme(ok); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestApplyThemeAndOverrides(t *testing.T) {
+	resetTheme(t)
+	bold := false
+	applyTheme(config.Theme{
+		Name:   config.ThemeLight,
+		Styles: map[string]config.StyleOverride{"selected": {Fg: "#ff0000", Bold: &bold}},
+	}, false)
+
+	if got := dirtyRepoStyle.GetForeground(); got != lipgloss.Color("235") {
+		t.Fatalf("expected light dirty color, got %v", got)
+	}
+	if got := selectedRepoStyle.GetForeground(); got != lipgloss.Color("#ff0000") {
+		t.Fatalf("expected override color, got %v", got)
+	}
+	if selectedRepoStyle.GetBold() {
+		t.Fatal("expected bold override to clear bold")
+	}
+
+	applyTheme(config.Theme{Name: "unknown"}, false)
+	if got := dirtyRepoStyle.GetForeground(); got != lipgloss.Color("15") {
+		t.Fatalf("expected fallback to dark, got %v", got)
+	}
+}
+
+func TestMonochromeDropsColorsAndAddsSymbols(t *testing.T) {
+	resetTheme(t)
+	t.Setenv("NO_COLOR", "1")
+	NewModel(config.Config{Theme: config.Theme{
+		Name:   config.ThemeDark,
+		Styles: map[string]config.StyleOverride{"footer": {Fg: "1"}},
+	}})
+
+	if !monochrome {
+		t.Fatal("expected monochrome under NO_COLOR")
+	}
+	for name, style := range styleVars {
+		if _, ok := style.GetForeground().(lipgloss.NoColor); !ok {
+			t.Errorf("%s still has a foreground color", name)
+		}
+	}
+	if !strings.Contains(panelLabel("1", true), "▸") {
+		t.Fatal("expected focus symbol in monochrome")
+	}
+}
@@ -1,6 +1,8 @@
 package ui
 
 import (
+	"fmt"
+	"slices"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -17,9 +19,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 	case reposLoadedMsg:
 		wasLoading := m.loading
-		m.repos = msg.repos
+		m.placeholders = msg.entries
+		m.setRepos(append(slices.Clip(msg.repos), msg.placeholders...))
 		m.loading = false
-		if msg.usedCWD && msg.cwd != "" {
+		if msg.manifestErr != nil {
+			m = m.setStatusError("Manifest: " + msg.manifestErr.Error())
+		} else if msg.usedCWD && msg.cwd != "" {
 			m = m.setStatusInfo("Scanning CWD: " + msg.cwd)
 		} else if wasLoading {
 			m = m.setStatusInfo("Refreshed")
@@ -31,12 +36,15 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, tea.Batch(cmds...)
 	case watchStartedMsg:
 		m.watcher = msg.manager
-		cmds := []tea.Cmd{m.watchEventsCmd(), m.watchErrorsCmd()}
+		cmds := []tea.Cmd{m.watchEventsCmd(), m.watchErrorsCmd(), m.watchConfigCmd()}
 		if len(m.repos) > 0 {
 			cmds = append(cmds, m.watchAddReposCmd(m.repos))
 		}
 		return m, tea.Batch(cmds...)
 	case watchEventMsg:
+		if msg.Repo == "" {
+			return m, tea.Batch(reloadConfigCmd(), m.watchEventsCmd())
+		}
 		return m, tea.Batch(
 			m.refreshRepoCmd(msg.Repo),
 			m.watchEventsCmd(),
@@ -46,10 +54,48 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, m.watchErrorsCmd()
 	case repoUpdatedMsg:
 		m.applyRepoUpdate(msg.repo)
-		if strings.HasPrefix(m.statusMsg, "Switching") || strings.HasPrefix(m.statusMsg, "Stashing") {
+		if msg.failed {
+			m = m.setStatusError(msg.status)
+		} else if msg.status != "" {
+			m = m.setStatusInfo(msg.status)
+		} else if strings.HasPrefix(m.statusMsg, "Switching") || strings.HasPrefix(m.statusMsg, "Stashing") {
 			m = m.setStatusInfo("Switched to " + msg.repo.Branch)
 		}
+		if m.mode == ModeConflicts {
+			m = m.syncConflictView()
+		}
+		return m, m.maybeLoadGraph()
+	case bulkDoneMsg:
+		if msg.failed > 0 {
+			m = m.setStatusError(msg.summary)
+		} else {
+			m = m.setStatusInfo(msg.summary)
+		}
+		return m, m.loadRepos()
+	case crossBranchesLoadedMsg:
+		if m.mode != ModeCrossBranch || msg.load != m.cross.load {
+			return m, nil
+		}
+		m.cross.repos = msg.repos
+		m.cross.items = crossBranchItems(msg.repos)
+		m.cross.cursor = 0
+		return m.clearStatus(), nil
+	case cloneDoneMsg:
+		return m.applyClone(msg)
+	case undoFailedMsg:
+		m.discardHistory = append(m.discardHistory, msg.record)
+		return m.setStatusError("Cannot undo: " + msg.err.Error()), nil
+	case discardDoneMsg:
+		m.discardHistory = append(m.discardHistory, msg.record)
+		m.applyRepoUpdate(msg.repo)
+		m = m.setStatusInfo("Discarded " + msg.record.file.Path + " (u to undo)")
 		return m, m.maybeLoadGraph()
+	case openFileMsg:
+		return m, m.openFileCmd(msg)
+	case remotesLoadedMsg:
+		return m.applyRemotes(msg), nil
+	case rewriteCheckedMsg:
+		return m.applyRewriteCheck(msg), nil
 	case branchesLoadedMsg:
 		m.branchItems = msg.items
 		m.branchFilterLocal = ""
@@ -69,12 +115,27 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case statusMsg:
 		m = m.setStatusInfo(string(msg))
 		return m, nil
+	case addPathPreviewMsg:
+		if m.mode == ModeAddPath && msg.path == config.NormalizePath(m.addPathInput) {
+			m.addPathPreview = pathPreview(msg)
+		}
+		return m, nil
+	case pathsCheckedMsg:
+		m.pathIssues = msg
+		if len(msg) == 0 {
+			m = m.setStatusInfo(fmt.Sprintf("%d %s OK", len(m.config.Paths), plural(len(m.config.Paths), "path", "paths")))
+		} else {
+			m = m.setStatusError(fmt.Sprintf("%d %s problems", len(msg), plural(len(msg), "path has", "paths have")))
+		}
+		return m, nil
+	case configReloadedMsg:
+		return m.reloadConfig(msg)
 	case errMsg:
 		m.err = msg
 		m = m.setStatusError("Error: " + msg.Error())
 		return m, nil
 	case pullDoneMsg:
-		m = m.setStatusInfo("Pulled " + string(msg))
+		m = m.setStatusInfo(pullSummary(msg.name, msg.result))
 		m.mode = ModeNormal
 		return m, m.loadRepos()
 	case commitDoneMsg:
@@ -93,6 +154,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m = m.clearStatus()
 		}
 		return m.handleKey(msg)
+	case tea.MouseMsg:
+		return m.handleMouse(msg)
 	}
 
 	return m, nil
@@ -108,164 +171,85 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleBranchPicker(msg)
 	case ModeConfirmStash:
 		return m.handleConfirmStash(msg)
+	case ModeConfirmPush:
+		return m.handleConfirmPush(msg)
+	case ModePushRemote:
+		return m.handlePushRemote(msg)
+	case ModeConflicts:
+		return m.handleConflicts(msg)
+	case ModeConfirmDiscard:
+		return m.handleConfirmDiscard(msg)
+	case ModeSearch:
+		return m.handleSearch(msg)
+	case ModePalette:
+		return m.handlePalette(msg)
 	case ModeHelp:
 		return m.handleHelp(msg)
+	case ModePaths:
+		return m.handlePaths(msg)
+	case ModeCrossBranch:
+		return m.handleCrossBranch(msg)
 	}
 
-	switch msg.String() {
-	case "1":
-		m.panelFocus = FocusRepos
-		return m, nil
-	case "2":
-		m.panelFocus = FocusBottom
-		return m, nil
-	case "q", "ctrl+c":
-		if m.watcher != nil {
-			_ = m.watcher.Close()
-		}
-		return m, tea.Quit
-	case "j", "down":
-		if m.panelFocus == FocusBottom {
-			m.scrollBottom(1)
-			return m, nil
-		}
-		if m.cursor < len(m.visibleRepos())-1 {
-			m.cursor++
-			m.resetBottomScroll()
-			return m, m.maybeLoadGraph()
-		}
-	case "k", "up":
-		if m.panelFocus == FocusBottom {
-			m.scrollBottom(-1)
-			return m, nil
-		}
-		if m.cursor > 0 {
-			m.cursor--
-			m.resetBottomScroll()
-			return m, m.maybeLoadGraph()
-		}
-	case "pgdown":
-		if m.panelFocus == FocusBottom {
-			m.scrollBottom(5)
-			return m, nil
-		}
-	case "pgup":
-		if m.panelFocus == FocusBottom {
-			m.scrollBottom(-5)
-			return m, nil
-		}
-	case "tab":
-		if m.panelFocus != FocusBottom {
-			return m, nil
-		}
-		m.toggleBottomView()
-		return m, m.maybeLoadGraph()
-	case "r":
-		m.loading = true
-		m = m.setStatusInfo("Refreshing...")
-		return m, m.loadRepos()
-	case "d":
-		m.filterDirty = !m.filterDirty
-		m.cursor = 0
-	case "a":
-		m.mode = ModeAddPath
-		m.addPathInput = ""
-	case "c":
-		if repo := m.currentRepo(); repo != nil {
-			if repo.HasConflict {
-				m = m.setStatusError("Cannot commit: repo has conflicts")
-				return m, nil
-			}
-			if !repo.IsDirty() {
-				m = m.setStatusInfo("Nothing to commit")
-				return m, nil
-			}
-			m.mode = ModeCommitInput
-			m.commitMsg = ""
-		}
-	case "o":
-		if repo := m.currentRepo(); repo != nil {
-			_ = git.OpenInEditor(repo.Path, m.config.Editor, m.config.EditorArgs)
-			m = m.setStatusInfo("Opened " + repo.Name + " in editor")
-		}
-	case "s":
-		path := config.ConfigPath()
-		m = m.setStatusInfo("Opening settings in editor...")
-		return m, func() tea.Msg {
-			if err := git.OpenInEditor(path, m.config.Editor, m.config.EditorArgs); err != nil {
-				return errMsg(err)
-			}
-			return statusMsg("Opened settings in editor")
-		}
-	case "f":
-		if repo := m.currentRepo(); repo != nil {
-			m = m.setStatusInfo("Fetching...")
-			return m, func() tea.Msg {
-				if err := git.FetchAll(repo.Path); err != nil {
-					return errMsg(err)
-				}
-				return statusMsg("Fetched " + repo.Name)
-			}
-		}
-	case "p":
-		if repo := m.currentRepo(); repo != nil {
-			if repo.HasConflict {
-				m = m.setStatusError("Cannot pull: repo has conflicts")
-				return m, nil
-			}
-			if repo.IsDirty() {
-				m = m.setStatusError("Cannot pull: repo has uncommitted changes")
-				return m, nil
-			}
-			m = m.setStatusInfo("Pulling...")
-			return m, func() tea.Msg {
-				if err := git.Pull(repo.Path); err != nil {
-					return errMsg(err)
-				}
-				return pullDoneMsg(repo.Name)
-			}
-		}
-	case "P":
-		if repo := m.currentRepo(); repo != nil {
-			if repo.HasConflict {
-				m = m.setStatusError("Cannot push: repo has conflicts")
-				return m, nil
-			}
-			if repo.IsDirty() {
-				m = m.setStatusError("Cannot push: repo has uncommitted changes")
-				return m, nil
-			}
-			if repo.Behind > 0 {
-				m = m.setStatusError("Cannot push: behind remote (pull first)")
-				return m, nil
-			}
-			m = m.setStatusInfo("Pushing...")
-			return m, func() tea.Msg {
-				if err := git.Push(repo.Path); err != nil {
-					return errMsg(err)
-				}
-				return pushDoneMsg(repo.Name)
-			}
-		}
-	case "b":
-		if repo := m.currentRepo(); repo != nil {
-			m.mode = ModeBranchPicker
-			m = m.setStatusInfo("Loading branches...")
-			return m, m.loadBranchesCmd(repo.Path)
-		}
-	case "?":
-		m.mode = ModeHelp
+	if a, ok := m.actionForKey(msg.String()); ok {
+		return a.run(m)
 	}
-
 	return m, nil
 }
 
+// pullOptions applies pull_strategy, or the repo's [[repo]] override.
+func (m Model) pullOptions(repo git.Repo) git.PullOptions {
+	return git.PullOptions{
+		Strategy:  m.config.ForRepo(repo.Path).PullStrategy,
+		Autostash: m.config.PullAutostash,
+	}
+}
+
+// pullSummary reports incoming commits and the first few changed files.
+func pullSummary(name string, result git.PullResult) string {
+	if result.Commits == 0 && len(result.Files) == 0 {
+		return "Pulled " + name + ": already up to date"
+	}
+	summary := fmt.Sprintf("Pulled %s: %d %s, %d %s",
+		name, result.Commits, plural(result.Commits, "commit", "commits"),
+		len(result.Files), plural(len(result.Files), "file", "files"))
+	const maxFiles = 3
+	if len(result.Files) > 0 {
+		files := result.Files
+		if len(files) > maxFiles {
+			files = files[:maxFiles]
+		}
+		summary += " (" + strings.Join(files, ", ")
+		if len(result.Files) > maxFiles {
+			summary += ", …"
+		}
+		summary += ")"
+	}
+	return summary
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
+
 func (m Model) handleAddPath(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "esc":
 		m.mode = ModeNormal
 		m.addPathInput = ""
+	case "tab":
+		return m.completeAddPath()
+	case "down", "ctrl+n":
+		return m.moveAddPathChoice(1), nil
+	case "up", "ctrl+p":
+		return m.moveAddPathChoice(-1), nil
 	case "enter":
+		if m.addPathChoice >= 0 {
+			return m.completeAddPath()
+		}
 		normalized := config.NormalizePath(m.addPathInput)
 		if normalized == "" {
 			return m, func() tea.Msg { return errMsg(errEmptyPath{}) }
@@ -287,13 +271,13 @@ func (m Model) handleAddPath(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, m.loadRepos()
 	case "backspace":
 		if len(m.addPathInput) > 0 {
-			m.addPathInput = m.addPathInput[:len(m.addPathInput)-1]
+			return m.setAddPathInput(m.addPathInput[:len(m.addPathInput)-1])
 		}
 	default:
 		if msg.Paste && msg.Type == tea.KeyRunes {
-			m.addPathInput += stripNewlines(string(msg.Runes))
+			return m.setAddPathInput(m.addPathInput + stripNewlines(string(msg.Runes)))
 		} else if len(msg.String()) == 1 {
-			m.addPathInput += msg.String()
+			return m.setAddPathInput(m.addPathInput + msg.String())
 		}
 	}
 
@@ -338,8 +322,23 @@ func (m Model) handleCommitInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// handleHelp scrolls the help; any other key closes it.
 func (m Model) handleHelp(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	m.mode = ModeNormal
+	rows := m.helpRows()
+	switch m.modalAction(ModeHelp, msg.String()) {
+	case "help-down":
+		m.helpScroll++
+	case "help-up":
+		m.helpScroll--
+	case "help-page-down":
+		m.helpScroll += rows
+	case "help-page-up":
+		m.helpScroll -= rows
+	default:
+		m.mode = ModeNormal
+		return m, nil
+	}
+	m.helpScroll = min(max(m.helpScroll, 0), max(len(m.helpLines())-rows, 0))
 	return m, nil
 }
 
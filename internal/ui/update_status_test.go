@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -56,3 +57,26 @@ func TestStatusErrorPersistsUntilKey(t *testing.T) {
 		t.Fatalf("expected error cleared on key, got %q", m.statusMsg)
 	}
 }
+
+func TestConfigProblemsShownInBanner(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Problems = []config.Problem{
+		{Severity: config.SeverityWarning, Line: 6, Col: 1, Message: `unknown key "colour" (kept in file, ignored)`, Fix: "remove it"},
+	}
+	cfg.Keys = map[string]config.KeyList{"launch": {"x"}}
+	m := NewModel(cfg)
+	m.width, m.height = 200, 30
+	if len(m.problems) != 2 {
+		t.Fatalf("expected load and keymap problems, got %v", m.problems)
+	}
+	first := strings.SplitN(m.View(), "\n", 2)[0]
+	if !strings.Contains(first, "2 config problems") || !strings.Contains(first, `unknown action "launch"`) || !strings.Contains(first, "[Esc] hide") {
+		t.Fatalf("expected banner leading with the error, got %q", first)
+	}
+
+	m2, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = m2.(Model)
+	if strings.Contains(m.View(), "config problems") {
+		t.Fatal("expected Esc to hide the banner")
+	}
+}
@@ -2,7 +2,9 @@ package ui
 
 import (
 	ospkg "os"
+	"os/exec"
 	pathpkg "path/filepath"
+	"strings"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -128,6 +130,38 @@ func TestPullStartsWhenClean(t *testing.T) {
 	}
 }
 
+func TestPullAutostashAllowsDirty(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.PullStrategy = config.PullRebase
+	cfg.PullAutostash = true
+	m := NewModel(cfg)
+	m.repos = []git.Repo{{
+		Name:     "repo",
+		Path:     "/tmp/repo",
+		Modified: 1,
+	}}
+
+	m2, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+	m = m2.(Model)
+	if cmd == nil {
+		t.Fatal("expected pull cmd with autostash")
+	}
+	if m.statusMsg != "Pulling (rebase)..." {
+		t.Fatalf("unexpected status: %q", m.statusMsg)
+	}
+}
+
+func TestPullSummary(t *testing.T) {
+	if got := pullSummary("repo", git.PullResult{}); got != "Pulled repo: already up to date" {
+		t.Fatalf("unexpected summary: %q", got)
+	}
+	got := pullSummary("repo", git.PullResult{Commits: 2, Files: []string{"a", "b", "c", "d"}})
+	want := "Pulled repo: 2 commits, 4 files (a, b, c, …)"
+	if got != want {
+		t.Fatalf("pullSummary() = %q, want %q", got, want)
+	}
+}
+
 func TestPushBlockedWhenBehind(t *testing.T) {
 	m := NewModel(config.DefaultConfig())
 	m.repos = []git.Repo{{
@@ -167,8 +201,10 @@ func TestPushBlockedWhenDirty(t *testing.T) {
 func TestPushStartsWhenUpToDate(t *testing.T) {
 	m := NewModel(config.DefaultConfig())
 	m.repos = []git.Repo{{
-		Name: "repo",
-		Path: "/tmp/repo",
+		Name:     "repo",
+		Path:     "/tmp/repo",
+		Branch:   "main",
+		Upstream: "origin/main",
 	}}
 
 	m2, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'P'}})
@@ -181,6 +217,235 @@ func TestPushStartsWhenUpToDate(t *testing.T) {
 	}
 }
 
+func TestPushWithoutUpstreamLoadsRemotes(t *testing.T) {
+	m := NewModel(config.DefaultConfig())
+	m.repos = []git.Repo{{
+		Name:   "repo",
+		Path:   "/tmp/repo",
+		Branch: "feat",
+	}}
+
+	m2, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'P'}})
+	m = m2.(Model)
+	if cmd == nil {
+		t.Fatal("expected remotes cmd")
+	}
+	if m.mode != ModeNormal {
+		t.Fatalf("expected ModeNormal while loading, got %v", m.mode)
+	}
+
+	m2, _ = m.Update(remotesLoadedMsg{path: "/tmp/repo", remotes: []string{"origin"}})
+	m = m2.(Model)
+	if m.mode != ModeConfirmPush {
+		t.Fatalf("expected ModeConfirmPush, got %v", m.mode)
+	}
+	if m.pendingPush.kind != PushSetUpstream || m.pendingPush.remote != "origin" || m.pendingPush.branch != "feat" {
+		t.Fatalf("unexpected pending push: %#v", m.pendingPush)
+	}
+
+	m2, cmd = m.handleConfirmPush(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+	m = m2.(Model)
+	if cmd == nil {
+		t.Fatal("expected push cmd")
+	}
+	if m.statusMsg != "Pushing to origin/feat..." {
+		t.Fatalf("unexpected status: %q", m.statusMsg)
+	}
+}
+
+func TestPushWithoutUpstreamPicksRemote(t *testing.T) {
+	m := NewModel(config.DefaultConfig())
+	m.repos = []git.Repo{{Name: "repo", Path: "/tmp/repo", Branch: "feat"}}
+
+	m2, _ := m.Update(remotesLoadedMsg{path: "/tmp/repo", remotes: []string{"fork", "origin", "upstream"}})
+	m = m2.(Model)
+	if m.mode != ModePushRemote {
+		t.Fatalf("expected ModePushRemote, got %v", m.mode)
+	}
+	if m.pushCursor != 1 {
+		t.Fatalf("expected cursor on origin, got %d", m.pushCursor)
+	}
+
+	m2, _ = m.handlePushRemote(tea.KeyMsg{Type: tea.KeyDown})
+	m = m2.(Model)
+	m2, cmd := m.handlePushRemote(tea.KeyMsg{Type: tea.KeyEnter})
+	m = m2.(Model)
+	if cmd == nil {
+		t.Fatal("expected push cmd")
+	}
+	if m.statusMsg != "Pushing to upstream/feat..." {
+		t.Fatalf("unexpected status: %q", m.statusMsg)
+	}
+}
+
+func TestPushIgnoresRemotesForAnotherRepo(t *testing.T) {
+	m := NewModel(config.DefaultConfig())
+	m.repos = []git.Repo{{Name: "a", Path: "/tmp/a", Branch: "feat"}, {Name: "b", Path: "/tmp/b", Branch: "feat"}}
+	m.cursor = 1
+
+	m2, _ := m.Update(remotesLoadedMsg{path: "/tmp/a", remotes: []string{"origin"}})
+	if got := m2.(Model); got.mode != ModeNormal || got.pendingPush != (pushRequest{}) {
+		t.Fatalf("remotes for another repo should be dropped, got mode %v push %#v", got.mode, got.pendingPush)
+	}
+	m.mode = ModeHelp
+	m2, _ = m.Update(remotesLoadedMsg{path: "/tmp/b", remotes: []string{"origin"}})
+	if got := m2.(Model).mode; got != ModeHelp {
+		t.Fatalf("remotes should not take over an open modal, got mode %v", got)
+	}
+}
+
+func TestPushDivergedAsksForceWithLease(t *testing.T) {
+	m := NewModel(config.DefaultConfig())
+	m.repos = []git.Repo{{
+		Name:     "repo",
+		Path:     "/tmp/repo",
+		Branch:   "feat",
+		Upstream: "origin/feat",
+		Ahead:    1,
+		Behind:   1,
+	}}
+
+	m2, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'P'}})
+	m = m2.(Model)
+	if cmd == nil || m.mode != ModeNormal {
+		t.Fatal("expected the rewrite check to run before any confirmation")
+	}
+
+	// behind because others pushed: no force push offered
+	m2, _ = m.Update(rewriteCheckedMsg{path: "/tmp/repo", rewritten: false})
+	if got := m2.(Model); got.mode != ModeNormal || got.statusMsg != "Cannot push: behind remote (pull first)" {
+		t.Fatalf("expected pull first, got mode=%v status=%q", got.mode, got.statusMsg)
+	}
+
+	m2, _ = m.Update(rewriteCheckedMsg{path: "/tmp/repo", rewritten: true})
+	m = m2.(Model)
+	if m.mode != ModeConfirmPush || m.pendingPush.kind != PushForceWithLease {
+		t.Fatalf("expected force confirm, got mode=%v push=%#v", m.mode, m.pendingPush)
+	}
+	if !strings.Contains(m.renderPushConfirm(), "1 remote commits will be discarded") {
+		t.Fatalf("expected the dialog to name the discarded commits:\n%s", m.renderPushConfirm())
+	}
+
+	m2, cmd = m.handleConfirmPush(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+	m = m2.(Model)
+	if cmd != nil || m.mode != ModeConfirmPush {
+		t.Fatal("expected plain push key to be ignored in force confirm")
+	}
+
+	m2, _ = m.handleConfirmPush(tea.KeyMsg{Type: tea.KeyEsc})
+	m = m2.(Model)
+	if m.mode != ModeNormal {
+		t.Fatalf("expected ModeNormal after cancel, got %v", m.mode)
+	}
+}
+
+func TestPushBlockedWhenDetached(t *testing.T) {
+	m := NewModel(config.DefaultConfig())
+	m.repos = []git.Repo{{Name: "repo", Path: "/tmp/repo", Branch: "detached@abc123"}}
+
+	m2, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'P'}})
+	m = m2.(Model)
+	if cmd != nil {
+		t.Fatal("expected no cmd when push is blocked")
+	}
+	if m.statusMsg != "Cannot push: detached HEAD" {
+		t.Fatalf("unexpected status: %q", m.statusMsg)
+	}
+}
+
+func TestConflictViewOpensOnlyWithConflicts(t *testing.T) {
+	m := NewModel(config.DefaultConfig())
+	m.repos = []git.Repo{{Name: "repo", Path: "/tmp/repo"}}
+
+	m2, _ := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'m'}})
+	m = m2.(Model)
+	if m.mode != ModeNormal {
+		t.Fatalf("expected ModeNormal without conflicts, got %v", m.mode)
+	}
+
+	m.repos[0].State = git.StateMerging
+	m.repos[0].HasConflict = true
+	m.repos[0].ChangedFiles = []git.ChangedFile{{Path: "a.txt", Status: git.StatusConflict}}
+	m2, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'m'}})
+	m = m2.(Model)
+	if m.mode != ModeConflicts {
+		t.Fatalf("expected ModeConflicts, got %v", m.mode)
+	}
+}
+
+func TestConflictViewGuards(t *testing.T) {
+	m := NewModel(config.DefaultConfig())
+	m.repos = []git.Repo{{
+		Name:         "repo",
+		Path:         "/tmp/repo",
+		State:        git.StateRebasing,
+		HasConflict:  true,
+		ChangedFiles: []git.ChangedFile{{Path: "a.txt", Status: git.StatusConflict}},
+	}}
+	m.mode = ModeConflicts
+
+	m2, cmd := m.handleConflicts(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'C'}})
+	m = m2.(Model)
+	if cmd != nil {
+		t.Fatal("expected continue blocked while conflicts remain")
+	}
+	if m.statusMsg != "Cannot continue: resolve all conflicts first" {
+		t.Fatalf("unexpected status: %q", m.statusMsg)
+	}
+
+	m2, cmd = m.handleConflicts(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'A'}})
+	m = m2.(Model)
+	if cmd != nil || !m.confirmAbort {
+		t.Fatal("expected abort to ask for confirmation")
+	}
+	m2, cmd = m.handleConflicts(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	m = m2.(Model)
+	if cmd != nil || m.confirmAbort {
+		t.Fatal("expected abort cancelled")
+	}
+	m2, _ = m.handleConflicts(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'A'}})
+	m = m2.(Model)
+	_, cmd = m.handleConflicts(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	if cmd == nil {
+		t.Fatal("expected abort cmd after confirmation")
+	}
+}
+
+func TestConflictViewClosesWhenDone(t *testing.T) {
+	m := NewModel(config.DefaultConfig())
+	m.repos = []git.Repo{{Name: "repo", Path: "/tmp/repo", State: git.StateMerging}}
+	m.mode = ModeConflicts
+
+	m2, _ := m.Update(repoUpdatedMsg{repo: git.Repo{Name: "repo", Path: "/tmp/repo"}, status: "merge continued"})
+	m = m2.(Model)
+	if m.mode != ModeNormal {
+		t.Fatalf("expected ModeNormal after operation finished, got %v", m.mode)
+	}
+	if m.statusMsg != "merge continued" {
+		t.Fatalf("unexpected status: %q", m.statusMsg)
+	}
+}
+
+func TestMergetoolFailureStillRefreshes(t *testing.T) {
+	dir := t.TempDir()
+	m := NewModel(config.DefaultConfig())
+	m.repos = []git.Repo{{Name: pathpkg.Base(dir), Path: dir, State: git.StateMerging, HasConflict: true}}
+	m.mode = ModeConflicts
+
+	msg, ok := m.mergetoolExited(dir, exec.Command("sh", "-c", "exit 1").Run()).(repoUpdatedMsg)
+	if !ok || !msg.failed || msg.repo.Path != dir {
+		t.Fatalf("expected a refresh flagged as failed, got %#v", msg)
+	}
+	m2, _ := m.Update(msg)
+	m = m2.(Model)
+	if m.statusKind != StatusError || m.statusMsg != "Mergetool: exit status 1" {
+		t.Fatalf("expected the exit status as an error, got %v %q", m.statusKind, m.statusMsg)
+	}
+	if m.repos[0].State != git.StateNone || m.mode != ModeNormal {
+		t.Fatalf("expected the repo refreshed and the finished merge view closed, got %v mode %v", m.repos[0].State, m.mode)
+	}
+}
+
 func TestPanelFocusKeys(t *testing.T) {
 	m := NewModel(config.DefaultConfig())
 
@@ -233,3 +498,30 @@ func TestOpenSettingsKey(t *testing.T) {
 		t.Fatalf("unexpected status: %q", m.statusMsg)
 	}
 }
+
+func TestPushRespectsRepoOverrides(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Repos = []config.RepoOverride{{Path: "/tmp/repo", Remote: "upstream", Protected: []string{"release/*"}}}
+	m := NewModel(cfg)
+	m.repos = []git.Repo{{Name: "repo", Path: "/tmp/repo", Branch: "release/2", Upstream: "origin/release/2"}}
+
+	m2, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'P'}})
+	m = m2.(Model)
+	if cmd != nil || m.statusMsg != "Cannot push: release/2 is protected in config" {
+		t.Fatalf("expected protected push blocked, got %q", m.statusMsg)
+	}
+
+	m.repos = []git.Repo{{Name: "repo", Path: "/tmp/repo", Branch: "feat"}}
+	m2, _ = m.Update(remotesLoadedMsg{path: "/tmp/repo", remotes: []string{"fork", "origin", "upstream"}})
+	m = m2.(Model)
+	if m.mode != ModeConfirmPush || m.pendingPush.remote != "upstream" {
+		t.Fatalf("expected configured remote without picker, got mode %v push %#v", m.mode, m.pendingPush)
+	}
+
+	m.mode = ModeNormal
+	m.config.Repos[0].Remote = "gone"
+	m2, _ = m.Update(remotesLoadedMsg{path: "/tmp/repo", remotes: []string{"origin"}})
+	if got := m2.(Model).statusMsg; got != "Cannot push: remote gone from config does not exist" {
+		t.Fatalf("unexpected status %q", got)
+	}
+}
@@ -5,7 +5,9 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"rtui/internal/config"
 	"rtui/internal/git"
+	"rtui/internal/trash"
 )
 
 type Layout struct {
@@ -13,6 +15,8 @@ type Layout struct {
 	Branch int
 	Status int
 	Sync   int
+	// Base is the divergence from the default branch; 0 hides it.
+	Base int
 }
 
 func (m Model) calculateLayout() Layout {
@@ -36,6 +40,12 @@ func (m Model) calculateLayout() Layout {
 		}
 	}
 
+	baseW := 0
+	if w >= 60 {
+		baseW = 8
+		remaining -= baseW + 3
+	}
+
 	nameW := int(float64(remaining) * 0.55)
 	branchW := remaining - nameW
 
@@ -52,6 +62,7 @@ func (m Model) calculateLayout() Layout {
 		Branch: branchW,
 		Status: statusW,
 		Sync:   syncW,
+		Base:   baseW,
 	}
 }
 
@@ -61,10 +72,16 @@ func (m Model) View() string {
 	}
 
 	var b strings.Builder
+	if hasBanner(m) {
+		b.WriteString(m.renderBanner())
+		b.WriteString("\n")
+	}
 
 	switch m.mode {
 	case ModeHelp:
 		b.WriteString(m.renderHelp())
+	case ModePalette:
+		b.WriteString(m.renderPalette())
 	case ModeAddPath:
 		b.WriteString(m.renderRepoList())
 		b.WriteString("\n")
@@ -77,10 +94,34 @@ func (m Model) View() string {
 		b.WriteString(m.renderRepoList())
 		b.WriteString("\n")
 		b.WriteString(m.renderBranchPicker())
+	case ModeCrossBranch:
+		b.WriteString(m.renderRepoList())
+		b.WriteString("\n")
+		b.WriteString(m.renderCrossBranch())
 	case ModeConfirmStash:
 		b.WriteString(m.renderRepoList())
 		b.WriteString("\n")
 		b.WriteString(m.renderStashConfirm())
+	case ModeConfirmPush:
+		b.WriteString(m.renderRepoList())
+		b.WriteString("\n")
+		b.WriteString(m.renderPushConfirm())
+	case ModePushRemote:
+		b.WriteString(m.renderRepoList())
+		b.WriteString("\n")
+		b.WriteString(m.renderPushRemotePicker())
+	case ModeConflicts:
+		b.WriteString(m.renderRepoList())
+		b.WriteString("\n")
+		b.WriteString(m.renderConflicts())
+	case ModeConfirmDiscard:
+		b.WriteString(m.renderRepoList())
+		b.WriteString("\n")
+		b.WriteString(m.renderDiscardConfirm())
+	case ModePaths:
+		b.WriteString(m.renderRepoList())
+		b.WriteString("\n")
+		b.WriteString(m.renderPaths())
 	default:
 		b.WriteString(m.renderRepoList())
 		bottomMax := m.bottomPanelMaxLines()
@@ -117,14 +158,16 @@ func (m Model) renderRepoList() string {
 	b.WriteString(strings.Repeat("─", m.width))
 	b.WriteString("\n")
 
-	start, end := m.repoWindow(len(repos))
-	window := repos
-	if len(repos) > 0 {
-		window = repos[start:end]
-	}
-	for i, repo := range window {
-		index := start + i
-		line := m.renderRepoLine(repo, index == m.cursor, layout)
+	rows := m.repoRows()
+	start, end := m.repoWindow(len(rows))
+	for index := start; index < end; index++ {
+		row := rows[index]
+		var line string
+		if row.header {
+			line = m.renderGroupHeader(row.group, index == m.cursor)
+		} else {
+			line = m.renderRepoLine(repos[row.repo], index == m.cursor, layout)
+		}
 		b.WriteString(line)
 		b.WriteString("\n")
 	}
@@ -132,11 +175,49 @@ func (m Model) renderRepoList() string {
 	return b.String()
 }
 
+func (m Model) renderGroupHeader(name string, isCursor bool) string {
+	cursor := "  "
+	if isCursor {
+		cursor = "→ "
+	}
+	marker := "▾ "
+	if m.collapsed[name] {
+		marker = "▸ "
+	}
+	stats := statsOf(m.groupRepos(name))
+	summary := fmt.Sprintf("%d repos", stats.total)
+	if stats.dirty > 0 {
+		summary += modifiedStyle.Render(fmt.Sprintf(" %d dirty", stats.dirty))
+	}
+	if stats.ahead > 0 {
+		summary += aheadStyle.Render(fmt.Sprintf(" ↑%d", stats.ahead))
+	}
+	if stats.behind > 0 {
+		summary += behindStyle.Render(fmt.Sprintf(" ↓%d", stats.behind))
+	}
+	summaryW := lipgloss.Width(summary)
+	titleW := max(m.width-lipgloss.Width(cursor+marker)-summaryW-1, 4)
+	title := truncate(groupLabel(name), titleW)
+	style := sectionTitleStyle
+	if isCursor {
+		style = selectedRepoStyle
+	}
+	left := cursor + style.Render(marker+title)
+	gap := max(m.width-lipgloss.Width(left)-summaryW, 1)
+	return left + strings.Repeat(" ", gap) + footerStyle.Render(summary)
+}
+
 func (m Model) renderRepoSectionHeader() string {
 	title := "REPOSITORIES"
+	if name := m.config.WorkspaceName(); name != "" {
+		title += " · " + name
+	}
 	if m.filterDirty {
 		title += " (dirty only)"
 	}
+	if m.config.SortMode != "" && m.config.SortMode != config.SortPath {
+		title += " · by " + sortLabel(m.config.SortMode)
+	}
 	label := panelLabel("1", m.panelFocus == FocusRepos)
 	space := " "
 	labelW := lipgloss.Width(label)
@@ -186,6 +267,9 @@ func (m Model) renderRepoSectionHeader() string {
 func panelLabel(key string, focused bool) string {
 	label := "[" + key + "]"
 	if focused {
+		if monochrome {
+			label = "▸" + label
+		}
 		return selectedRepoStyle.Render(label)
 	}
 	return footerStyle.Render(label)
@@ -208,6 +292,9 @@ func (m Model) renderRepoHeader(layout Layout) string {
 	} else {
 		line = cursor + name + " | " + status + " | " + sync
 	}
+	if layout.Base > 0 {
+		line += " | " + padRight("Base", layout.Base)
+	}
 
 	return footerStyle.Render(line)
 }
@@ -218,17 +305,17 @@ func (m Model) renderRepoLine(repo git.Repo, isCursor bool, layout Layout) strin
 		cursor = "→ "
 	}
 
-	name := padRight(truncate(repo.Name, layout.Name), layout.Name)
+	base := lipgloss.NewStyle()
 	if isCursor {
-		name = selectedRepoStyle.Render(name)
+		base = selectedRepoStyle
 	}
+	match, _ := matchRepo(repo, m.searchQuery)
+
+	name := padRight(highlightMatches(truncate(repo.Name, layout.Name), repo.Name, match.name, base), layout.Name)
 
 	var branch string
 	if layout.Branch > 0 {
-		branch = padRight(truncate(repo.Branch, layout.Branch), layout.Branch)
-		if isCursor {
-			branch = selectedRepoStyle.Render(branch)
-		}
+		branch = padRight(highlightMatches(truncate(repo.Branch, layout.Branch), repo.Branch, match.branch, base), layout.Branch)
 	}
 
 	var status string
@@ -248,11 +335,24 @@ func (m Model) renderRepoLine(repo git.Repo, isCursor bool, layout Layout) strin
 		status = stagedStyle.Render("✓")
 	}
 
+	if m.isPlaceholder(repo) {
+		status = footerStyle.Render("missing")
+	}
 	statusPadded := padRight(status, layout.Status)
 
 	var sync string
-	if repo.HasConflict {
+	if repo.State != git.StateNone {
+		label := repo.State.String()
+		if repo.HasConflict {
+			label += "!"
+			sync = conflictStyle.Render(label)
+		} else {
+			sync = modifiedStyle.Render(label)
+		}
+	} else if repo.HasConflict {
 		sync = conflictStyle.Render("CONFLICT")
+	} else if repo.Upstream == "" && !repo.IsDetached() && !m.isPlaceholder(repo) {
+		sync = modifiedStyle.Render("no up")
 	} else {
 		if repo.Ahead > 0 {
 			sync += aheadStyle.Render(fmt.Sprintf("↑%d", repo.Ahead))
@@ -272,8 +372,13 @@ func (m Model) renderRepoLine(repo git.Repo, isCursor bool, layout Layout) strin
 	} else {
 		line = cursor + name + " | " + statusPadded + " | " + sync
 	}
+	if layout.Base > 0 {
+		line += " | " + padRight(m.renderBase(repo), layout.Base)
+	}
 
-	if repo.IsDirty() {
+	if m.isPlaceholder(repo) {
+		line = footerStyle.Render(line)
+	} else if repo.IsDirty() {
 		line = dirtyRepoStyle.Render(line)
 	} else {
 		line = cleanRepoStyle.Render(line)
@@ -282,7 +387,38 @@ func (m Model) renderRepoLine(repo git.Repo, isCursor bool, layout Layout) strin
 	return line
 }
 
+// renderBase is the Base column: commits ahead/behind the default branch,
+// "merged" once a clean branch has landed in it, "new" for a branch still
+// at the default tip, "=" on the default branch itself and "-" when there
+// is none.
+func (m Model) renderBase(repo git.Repo) string {
+	switch {
+	case repo.DefaultBranch == "" || repo.IsDetached() || m.isPlaceholder(repo):
+		return footerStyle.Render("-")
+	case repo.OnDefaultBranch():
+		if repo.DefaultBehind > 0 {
+			return behindStyle.Render(fmt.Sprintf("-%d", repo.DefaultBehind))
+		}
+		return footerStyle.Render("=")
+	case repo.IsMerged():
+		return stagedStyle.Render("merged")
+	case repo.IsNew():
+		return footerStyle.Render("new")
+	}
+	base := aheadStyle.Render(fmt.Sprintf("+%d", repo.DefaultAhead))
+	if repo.DefaultBehind > 0 {
+		base += " " + behindStyle.Render(fmt.Sprintf("-%d", repo.DefaultBehind))
+	}
+	return base
+}
+
 func (m Model) renderBottomPanel(maxLines int) string {
+	if group, ok := m.currentGroup(); ok {
+		return m.renderGroupPanel(group, maxLines)
+	}
+	if e, ok := m.currentPlaceholder(); ok {
+		return m.renderPlaceholderPanel(e, maxLines)
+	}
 	if m.bottomView == BottomGraph {
 		return m.renderGraphPanel(maxLines)
 	}
@@ -302,22 +438,7 @@ func (m Model) renderChangesPanel(maxLines int) string {
 	b.WriteString(strings.Repeat("─", m.width))
 	b.WriteString("\n")
 
-	staged := []git.ChangedFile{}
-	modified := []git.ChangedFile{}
-	untracked := []git.ChangedFile{}
-
-	for _, f := range repo.ChangedFiles {
-		switch f.Status {
-		case git.StatusStaged:
-			staged = append(staged, f)
-		case git.StatusModified:
-			modified = append(modified, f)
-		case git.StatusUntracked:
-			untracked = append(untracked, f)
-		}
-	}
-
-	lines := m.changesLines(staged, modified, untracked)
+	lines, _ := m.changesLines(*repo)
 	contentMax := maxLines - 2
 	if contentMax < 1 {
 		return b.String()
@@ -328,6 +449,25 @@ func (m Model) renderChangesPanel(maxLines int) string {
 	return b.String()
 }
 
+func (m Model) renderGroupPanel(name string, maxLines int) string {
+	var b strings.Builder
+	b.WriteString(sectionTitleStyle.Render("GROUP " + groupLabel(name)))
+	b.WriteString("\n")
+	b.WriteString(strings.Repeat("─", m.width))
+	b.WriteString("\n")
+	contentMax := maxLines - 2
+	if contentMax < 1 {
+		return b.String()
+	}
+	stats := statsOf(m.groupRepos(name))
+	lines := []string{
+		fmt.Sprintf("%d repos, %d dirty, ↑%d ↓%d", stats.total, stats.dirty, stats.ahead, stats.behind),
+		footerStyle.Render(m.hints("group-fetch", "fetch", "group-pull", "pull", "group-push", "Push all", "group-toggle", "collapse")),
+	}
+	m.writePanelLines(&b, lines, contentMax)
+	return b.String()
+}
+
 func (m Model) renderAddPath() string {
 	msg := "Add repo path"
 	maxW := max(m.width-4, 20)
@@ -338,11 +478,66 @@ func (m Model) renderAddPath() string {
 	if len(input) > inputW {
 		input = input[len(input)-inputW:]
 	}
-	body := msg + "\n\n" + input + "\n\n[Enter]=save  [Esc]=cancel"
+	body := msg + "\n\n" + input + "\n"
+	if candidates := m.renderPathCandidates(inputW); candidates != "" {
+		body += candidates
+	}
+	if preview := m.renderPathPreview(inputW); preview != "" {
+		body += "\n" + preview + "\n"
+	}
+	body += "\n[Tab]=complete  [Enter]=save  [Esc]=cancel"
 	modal := boxStyle.Width(boxW).Render(body)
 	return lipgloss.PlaceHorizontal(m.width, lipgloss.Center, modal)
 }
 
+// renderPathCandidates draws the completion dropdown around the
+// highlighted candidate.
+func (m Model) renderPathCandidates(width int) string {
+	candidates := m.addPathCandidates
+	if len(candidates) == 0 {
+		return ""
+	}
+	start, end, showTop, showBottom := branchWindowInfo(len(candidates), max(m.addPathChoice, 0), maxPathCandidates)
+	var b strings.Builder
+	if showTop {
+		b.WriteString(footerStyle.Render("  ↑ more") + "\n")
+	}
+	for i := start; i < end; i++ {
+		name := truncate(candidates[i]+"/", width-2)
+		if i == m.addPathChoice {
+			b.WriteString(selectedRepoStyle.Render("→ "+name) + "\n")
+		} else {
+			b.WriteString(footerStyle.Render("  "+name) + "\n")
+		}
+	}
+	if showBottom {
+		b.WriteString(footerStyle.Render("  ↓ more") + "\n")
+	}
+	return b.String()
+}
+
+// renderPathPreview summarizes the latest preview for the typed path.
+func (m Model) renderPathPreview(width int) string {
+	p := m.addPathPreview
+	if p.path == "" || p.path != config.NormalizePath(m.addPathInput) {
+		return ""
+	}
+	switch {
+	case p.missing:
+		return conflictStyle.Render("Does not exist")
+	case p.notDir:
+		return conflictStyle.Render("Not a directory")
+	}
+	line := fmt.Sprintf("%d %s at depth %d", p.repos, plural(p.repos, "repo", "repos"), m.config.ScanDepth)
+	if p.covered == p.path {
+		return modifiedStyle.Render(truncate(line+" · already added", width))
+	}
+	if p.covered != "" {
+		return modifiedStyle.Render(truncate(line+" · already scanned under "+p.covered, width))
+	}
+	return stagedStyle.Render(truncate(line, width))
+}
+
 func (m Model) renderCommitInput() string {
 	var b strings.Builder
 	b.WriteString("Commit message (stages all):\n")
@@ -389,23 +584,61 @@ func (m Model) renderGraphPanel(maxLines int) string {
 	return b.String()
 }
 
-func (m Model) changesLines(staged, modified, untracked []git.ChangedFile) []string {
-	maxPathW := m.width - 4
-	lines := []string{
-		stagedStyle.Render(fmt.Sprintf("Staged (%d)", len(staged))),
-	}
-	for _, f := range staged {
-		lines = append(lines, "  "+truncatePath(f.Path, maxPathW))
-	}
-	lines = append(lines, modifiedStyle.Render(fmt.Sprintf("Modified (%d)", len(modified))))
-	for _, f := range modified {
-		lines = append(lines, "  "+truncatePath(f.Path, maxPathW))
+type changeSection struct {
+	title  string
+	style  *lipgloss.Style
+	status git.FileStatus
+	always bool
+}
+
+var changeSections = []changeSection{
+	{title: "Conflicts", style: &conflictStyle, status: git.StatusConflict},
+	{title: "Staged", style: &stagedStyle, status: git.StatusStaged, always: true},
+	{title: "Modified", style: &modifiedStyle, status: git.StatusModified, always: true},
+	{title: "Untracked", style: &untrackedStyle, status: git.StatusUntracked, always: true},
+}
+
+// changeEntries returns changed files in CHANGES panel order.
+func changeEntries(repo git.Repo) []git.ChangedFile {
+	var entries []git.ChangedFile
+	for _, section := range changeSections {
+		for _, f := range repo.ChangedFiles {
+			if f.Status == section.status {
+				entries = append(entries, f)
+			}
+		}
 	}
-	lines = append(lines, untrackedStyle.Render(fmt.Sprintf("Untracked (%d)", len(untracked))))
-	for _, f := range untracked {
-		lines = append(lines, "  "+truncatePath(f.Path, maxPathW))
+	return entries
+}
+
+// changesLines renders the CHANGES list and returns the line index of
+// each entry from changeEntries.
+func (m Model) changesLines(repo git.Repo) ([]string, []int) {
+	maxPathW := m.width - 4
+	showCursor := m.panelFocus == FocusBottom && m.bottomView == BottomChanges
+	var lines []string
+	var entryLines []int
+	for _, section := range changeSections {
+		var files []git.ChangedFile
+		for _, f := range repo.ChangedFiles {
+			if f.Status == section.status {
+				files = append(files, f)
+			}
+		}
+		if len(files) == 0 && !section.always {
+			continue
+		}
+		lines = append(lines, (*section.style).Render(fmt.Sprintf("%s (%d)", section.title, len(files))))
+		for _, f := range files {
+			line := "  " + truncatePath(f.Path, maxPathW)
+			if showCursor && len(entryLines) == m.changesCursor {
+				line = selectedRepoStyle.Render("→ " + truncatePath(f.Path, maxPathW))
+			}
+			entryLines = append(entryLines, len(lines))
+			lines = append(lines, line)
+		}
 	}
-	return lines
+	return lines, entryLines
 }
 
 func (m Model) changesTotalLines() int {
@@ -413,20 +646,8 @@ func (m Model) changesTotalLines() int {
 	if repo == nil {
 		return 0
 	}
-	staged := 0
-	modified := 0
-	untracked := 0
-	for _, f := range repo.ChangedFiles {
-		switch f.Status {
-		case git.StatusStaged:
-			staged++
-		case git.StatusModified:
-			modified++
-		case git.StatusUntracked:
-			untracked++
-		}
-	}
-	return 3 + staged + modified + untracked
+	lines, _ := m.changesLines(*repo)
+	return len(lines)
 }
 
 func (m Model) writePanelLines(b *strings.Builder, lines []string, contentMax int) {
@@ -449,11 +670,10 @@ func (m Model) bottomPanelMaxLines() int {
 }
 
 func (m Model) repoListLineCount() int {
-	repos := m.visibleRepos()
-	if len(repos) == 0 {
+	if len(m.visibleRepos()) == 0 {
 		return 3
 	}
-	return m.repoFixedLines() + m.repoWindowSize(len(repos))
+	return m.repoFixedLines() + m.repoWindowSize(len(m.repoRows()))
 }
 
 func (m Model) repoFixedLines() int {
@@ -508,7 +728,7 @@ func (m Model) maxRepoRows(total int) int {
 
 func (m Model) bodyMaxLines() int {
 	footerLines := m.footerLineCount()
-	bodyMax := m.height - footerLines
+	bodyMax := m.height - footerLines - m.bannerLines()
 	if bodyMax < 0 {
 		return 0
 	}
@@ -568,6 +788,9 @@ func (m Model) renderBranchPicker() string {
 			}
 			name := item.Name
 			if name == current {
+				if monochrome {
+					name = "* " + name
+				}
 				name = stagedStyle.Render(name)
 			}
 			line := cursor + truncate(name, contentW-2)
@@ -580,69 +803,195 @@ func (m Model) renderBranchPicker() string {
 	}
 
 	b.WriteString("\n")
-	b.WriteString(footerStyle.Render("[Enter] switch  [Esc] cancel"))
+	b.WriteString(footerStyle.Render(m.hints("picker-switch", "switch", "picker-cancel", "cancel")))
 
 	return boxStyle.Width(boxW).Render(b.String())
 }
 
 func (m Model) renderStashConfirm() string {
 	msg := "Repo has uncommitted changes. Stash and switch?"
+	if branch := m.cross.pending; branch != "" {
+		n := dirtySteps(crossPlan(m.cross.repos, branch, m.cross.create))
+		msg = fmt.Sprintf("%d repos have uncommitted changes. Stash them and switch to %s?", n, branch)
+	}
 	boxW := min(m.width-4, 60)
-	return boxStyle.Width(boxW).Render(msg + "\n\n[s]tash  [c]ancel")
+	return boxStyle.Width(boxW).Render(msg + "\n\n" + m.hints("stash-confirm", "stash", "stash-cancel", "cancel"))
 }
 
-func (m Model) renderHelp() string {
-	help := `KEYBINDINGS
+func (m Model) renderPushConfirm() string {
+	req := m.pendingPush
+	boxW := min(m.width-4, 60)
+	if req.kind == PushForceWithLease {
+		msg := req.branch + " was rewritten after it was pushed.\nForce push with lease?"
+		if repo := m.currentRepo(); repo != nil {
+			msg = fmt.Sprintf("%s was rewritten after it was pushed (↑%d ↓%d).\nForce push with lease? %d remote commits will be discarded.",
+				req.branch, repo.Ahead, repo.Behind, repo.Behind)
+		}
+		return boxStyle.Width(boxW).Render(conflictStyle.Render("Force push") + "\n\n" + msg + "\n\n" + m.hints("push-force", "force push", "push-cancel", "cancel"))
+	}
+	msg := req.branch + " has no upstream.\nPush and track " + req.remote + "/" + req.branch + "?"
+	return boxStyle.Width(boxW).Render(msg + "\n\n" + m.hints("push-upstream", "push", "push-cancel", "cancel"))
+}
 
-Navigation
-  j/↓     Next repo
-  k/↑     Previous repo
+func (m Model) renderPushRemotePicker() string {
+	var b strings.Builder
+	b.WriteString("Push " + m.pendingPush.branch + " to remote\n\n")
+	boxW := min(m.width-4, 60)
+	contentW := boxW - 4
+	for i, remote := range m.pushRemotes {
+		cursor := "  "
+		if i == m.pushCursor {
+			cursor = "→ "
+		}
+		b.WriteString(cursor + truncate(remote, contentW-2) + "\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(footerStyle.Render(m.hints("remote-select", "push -u", "remote-cancel", "cancel")))
+	return boxStyle.Width(boxW).Render(b.String())
+}
 
-Actions
-  a       Add path
-  c       Commit (stages all)
-  b       Switch branch
-  o       Open in editor
-  s       Settings (open config in editor)
-  p       Pull
-  P       Push
-  f       Fetch all
-  r       Refresh
+func (m Model) renderConflicts() string {
+	repo := m.currentRepo()
+	if repo == nil {
+		return ""
+	}
+	var b strings.Builder
+	files := repo.ConflictFiles()
+	title := "Conflicts"
+	if repo.State != git.StateNone {
+		title = strings.ToUpper(repo.State.Verb()) + " in progress"
+	}
+	b.WriteString(conflictStyle.Render(title))
+	b.WriteString(fmt.Sprintf("  (%d unresolved)\n\n", len(files)))
 
-Panels
-  1       Focus repo list
-  2       Focus bottom panel
-  Tab     Toggle Changes/Graph (bottom panel)
+	boxW := min(m.width-4, 60)
+	contentW := boxW - 4
+	maxList := max(m.height-12, 3)
+	start, end, showTop, showBottom := branchWindowInfo(len(files), m.conflictCursor, maxList)
+	if showTop {
+		b.WriteString(footerStyle.Render("  ↑ more"))
+		b.WriteString("\n")
+	}
+	if len(files) == 0 {
+		if repo.State == git.StateBisecting {
+			b.WriteString(footerStyle.Render("  Bisect in progress"))
+		} else {
+			b.WriteString(stagedStyle.Render("  All conflicts resolved"))
+		}
+		b.WriteString("\n")
+	}
+	for i := start; i < end; i++ {
+		cursor := "  "
+		if i == m.conflictCursor {
+			cursor = "→ "
+		}
+		b.WriteString(cursor + truncatePath(files[i].Path, contentW-2) + "\n")
+	}
+	if showBottom {
+		b.WriteString(footerStyle.Render("  ↓ more"))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
 
-Filters
-  d       Toggle dirty-only
+	if m.confirmAbort {
+		b.WriteString(conflictStyle.Render("Abort " + repo.State.Verb() + "? " + m.hint("conflict-abort-confirm", "yes") + "  [n]o"))
+	} else {
+		b.WriteString(footerStyle.Render(m.hints("conflict-ours", "ours", "conflict-theirs", "theirs", "conflict-edit", "edit", "conflict-mergetool", "mergetool", "conflict-resolved", "add resolved")))
+		b.WriteString("\n")
+		b.WriteString(footerStyle.Render(m.hints("conflict-continue", "Continue", "conflict-abort", "Abort", "conflict-close", "close")))
+	}
+	return boxStyle.Width(boxW).Render(b.String())
+}
 
-Other
-  ?       This help
-  q       Quit
+func (m Model) renderDiscardConfirm() string {
+	file := m.pendingDiscard
+	boxW := min(m.width-4, 60)
+	msg := discardVerb(file.Status) + " " + truncatePath(file.Path, boxW-8) + "?"
+	if file.Status == git.StatusStaged {
+		msg += "\nWorktree is kept."
+	} else {
+		msg += "\nA backup is kept in " + truncatePath(trash.Root(), boxW-20) + "."
+	}
+	msg += "\nPress u afterwards to undo."
+	return boxStyle.Width(boxW).Render(msg + "\n\n" + m.hints("discard-confirm", "yes", "discard-cancel", "no"))
+}
 
-Press any key to close...`
+// renderHelp lists every registered action by section.
+func (m Model) renderHelp() string {
+	var b strings.Builder
+	b.WriteString("KEYBINDINGS\n")
+	lines := m.helpLines()
+	start, end := 0, len(lines)
+	if rows := m.helpRows(); len(lines) > rows {
+		start = min(max(m.helpScroll, 0), len(lines)-rows)
+		end = start + rows
+	}
+	if start > 0 {
+		b.WriteString(footerStyle.Render("  ↑ more") + "\n")
+	}
+	for _, line := range lines[start:end] {
+		b.WriteString(line + "\n")
+	}
+	if end < len(lines) {
+		b.WriteString(footerStyle.Render("  ↓ more") + "\n")
+	}
+	hint := "any key to close"
+	if start > 0 || end < len(lines) {
+		hint = m.hints("help-down", "down", "help-up", "up", "help-page-down", "page down") + "  other keys close"
+	}
+	b.WriteString("\n" + footerStyle.Render(hint))
 
-	boxW := min(m.width-4, 40)
-	return boxStyle.Width(boxW).Render(help)
+	boxW := min(m.width-4, 56)
+	return boxStyle.Width(boxW).Render(b.String())
+}
+
+// helpLines is the help body: every registry action under its section.
+func (m Model) helpLines() []string {
+	keyW := 0
+	for _, a := range actionRegistry {
+		keyW = max(keyW, lipgloss.Width(m.keysLabel(a)))
+	}
+	var lines []string
+	for _, section := range helpSections {
+		lines = append(lines, "", section)
+		for _, a := range actionRegistry {
+			if a.section == section {
+				lines = append(lines, "  "+padRight(m.keysLabel(a), keyW)+"  "+a.desc)
+			}
+		}
+	}
+	return lines
+}
+
+// helpRows is how many help lines fit on screen: the height less the
+// border, title, hint and the two "more" markers.
+func (m Model) helpRows() int {
+	return max(m.height-8, 3)
 }
 
 func (m Model) renderFooter() string {
-	return footerActions(m.width)
+	if m.mode == ModeSearch {
+		return m.renderSearchBar()
+	}
+	return footerActions(m.width, m.footerTokens())
 }
 
 type footerToken struct {
 	plain  string
 	styled string
+	// action is the registry id a click on the token runs.
+	action string
 }
 
-func footerActions(width int) string {
+// footerGap is the spacing between footer tokens.
+const footerGap = 3
+
+func footerActions(width int, tokens []footerToken) string {
 	if width <= 0 {
 		return ""
 	}
 
-	tokens := footerTokens()
-	gap := 3
+	gap := footerGap
 	lines := wrapFooterTokens(tokens, width, 2, gap)
 	if len(lines) == 0 {
 		return ""
@@ -663,17 +1012,20 @@ func footerActions(width int) string {
 	return strings.Join(out, "\n")
 }
 
-func footerTokens() []footerToken {
-	return []footerToken{
-		footerTokenHotkey("a", "add path"),
-		footerTokenHotkey("b", "branch"),
-		footerTokenHotkey("c", "commit"),
-		footerTokenHotkey("P", "push"),
-		footerTokenHotkey("o", "open"),
-		footerTokenHotkey("p", "pull"),
-		footerTokenHotkey("r", "refresh"),
-		footerTokenHotkey("?", "?"),
+// footerTokens renders the registry actions that have a footer label,
+// using each action's first key.
+func (m Model) footerTokens() []footerToken {
+	var tokens []footerToken
+	for _, a := range actionRegistry {
+		keys := m.actionKeys(a)
+		if a.footer == "" || len(keys) == 0 {
+			continue
+		}
+		tok := footerTokenHotkey(keyLabel(keys[0]), a.footer)
+		tok.action = a.id
+		tokens = append(tokens, tok)
 	}
+	return tokens
 }
 
 func footerTokenHotkey(key, label string) footerToken {
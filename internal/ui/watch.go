@@ -14,7 +14,10 @@ type watchStartedMsg struct {
 }
 
 type repoUpdatedMsg struct {
-	repo git.Repo
+	repo   git.Repo
+	status string
+	// failed shows status as an error; the repo is refreshed regardless.
+	failed bool
 }
 
 func startWatcherCmd() tea.Cmd {
@@ -64,7 +67,7 @@ func (m Model) watchAddReposCmd(repos []git.Repo) tea.Cmd {
 			return nil
 		}
 		for _, r := range repos {
-			if r.Path == "" {
+			if r.Path == "" || m.isPlaceholder(r) {
 				continue
 			}
 			if err := m.watcher.AddRepo(r.Path); err != nil {
@@ -77,15 +80,24 @@ func (m Model) watchAddReposCmd(repos []git.Repo) tea.Cmd {
 
 func (m Model) refreshRepoCmd(path string) tea.Cmd {
 	return func() tea.Msg {
-		repo := git.GetRepoStatus(path)
+		repo := m.repoStatus(path)
 		return repoUpdatedMsg{repo: repo}
 	}
 }
 
+// applyRepoUpdate swaps in a refreshed repo and re-sorts, since the
+// update can change its position. The scan root is kept because a single
+// repo refresh does not know it.
 func (m *Model) applyRepoUpdate(updated git.Repo) {
-	for i := range m.repos {
-		if m.repos[i].Path == updated.Path {
-			m.repos[i] = updated
+	repos := make([]git.Repo, len(m.repos))
+	copy(repos, m.repos)
+	for i := range repos {
+		if repos[i].Path == updated.Path {
+			if updated.Root == "" {
+				updated.Root = repos[i].Root
+			}
+			repos[i] = updated
+			m.setRepos(repos)
 			return
 		}
 	}
@@ -9,6 +9,7 @@ import (
 
 type fakeWatcher struct {
 	added  []string
+	files  []string
 	events chan watch.Event
 	errors chan error
 }
@@ -28,6 +29,10 @@ func (f *fakeWatcher) AddRepo(path string) error {
 	f.added = append(f.added, path)
 	return nil
 }
+func (f *fakeWatcher) AddFile(path string) error {
+	f.files = append(f.files, path)
+	return nil
+}
 
 func TestWatchAddReposCmdAddsPaths(t *testing.T) {
 	fw := newFakeWatcher()
@@ -25,9 +25,11 @@ type Runner interface {
 	Events() <-chan Event
 	Errors() <-chan error
 	AddRepo(path string) error
+	AddFile(path string) error
 }
 
-// Event indicates a repo change.
+// Event indicates a repo change, or a change to a file added with
+// AddFile, in which case Repo is empty.
 type Event struct {
 	Repo string
 	Path string
@@ -42,6 +44,7 @@ type Manager struct {
 	done     chan struct{}
 	mu       sync.Mutex
 	repos    []string
+	files    []string
 	debounce map[string]*time.Timer
 	closed   bool
 }
@@ -129,6 +132,24 @@ func (m *Manager) AddRepo(path string) error {
 	return nil
 }
 
+// AddFile watches a single file, e.g. the config. Its directory is watched
+// rather than the file so saves that rename a temp file over it are seen.
+// The directory must exist.
+func (m *Manager) AddFile(path string) error {
+	file := filepath.Clean(path)
+	m.mu.Lock()
+	for _, f := range m.files {
+		if f == file {
+			m.mu.Unlock()
+			return nil
+		}
+	}
+	m.files = append(m.files, file)
+	m.mu.Unlock()
+
+	return m.watcher.Add(filepath.Dir(file))
+}
+
 func (m *Manager) loop() {
 	for {
 		select {
@@ -150,6 +171,9 @@ func (m *Manager) loop() {
 
 func (m *Manager) handleEvent(ev fsnotify.Event) {
 	path := filepath.Clean(ev.Name)
+	if m.watchesFile(path) {
+		m.schedule(path, Event{Path: path})
+	}
 	if m.cfg.Ignore != nil && m.cfg.Ignore(path) {
 		return
 	}
@@ -164,21 +188,33 @@ func (m *Manager) handleEvent(ev fsnotify.Event) {
 	if !ok {
 		return
 	}
-	m.schedule(repo, path)
+	m.schedule(repo, Event{Repo: repo, Path: path})
+}
+
+func (m *Manager) watchesFile(path string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, f := range m.files {
+		if f == path {
+			return true
+		}
+	}
+	return false
 }
 
-func (m *Manager) schedule(repo, path string) {
+// schedule emits ev once key has been quiet for the debounce interval.
+func (m *Manager) schedule(key string, ev Event) {
 	m.mu.Lock()
 	if m.closed {
 		m.mu.Unlock()
 		return
 	}
-	if t, ok := m.debounce[repo]; ok {
+	if t, ok := m.debounce[key]; ok {
 		t.Stop()
 	}
-	m.debounce[repo] = time.AfterFunc(m.cfg.Debounce, func() {
+	m.debounce[key] = time.AfterFunc(m.cfg.Debounce, func() {
 		select {
-		case m.events <- Event{Repo: repo, Path: path}:
+		case m.events <- ev:
 		case <-m.done:
 		}
 	})
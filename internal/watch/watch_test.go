@@ -1,6 +1,7 @@
 package watch
 
 import (
+	"os"
 	"path/filepath"
 	"testing"
 	"time"
@@ -146,3 +147,36 @@ func TestRepoForPath(t *testing.T) {
 		}
 	}
 }
+
+func TestAddFileReportsRenamedSaves(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.toml")
+	if err := os.WriteFile(file, []byte("a = 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	m, err := NewManager(Config{Debounce: 20 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+	m.Start()
+	if err := m.AddFile(file); err != nil {
+		t.Fatal(err)
+	}
+
+	tmp := filepath.Join(dir, "config.toml.swp")
+	if err := os.WriteFile(tmp, []byte("a = 2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp, file); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case ev := <-m.Events():
+		if ev.Repo != "" || ev.Path != file {
+			t.Fatalf("unexpected event %+v", ev)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no event for the watched file")
+	}
+}
@@ -1,4 +1,4 @@
 #!/usr/bin/env bash
 set -euo pipefail
 
-GOFLAGS="-count=1" go test ./internal/config ./internal/git
+GOFLAGS="-count=1" go test ./internal/config ./internal/git ./internal/trash